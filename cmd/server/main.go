@@ -13,6 +13,8 @@ import (
 	"github.com/xuchengvcc/restart-life-api/internal/api/routes"
 	"github.com/xuchengvcc/restart-life-api/internal/config"
 	"github.com/xuchengvcc/restart-life-api/internal/database"
+	"github.com/xuchengvcc/restart-life-api/internal/mailer"
+	"github.com/xuchengvcc/restart-life-api/internal/storage"
 )
 
 func main() {
@@ -41,8 +43,20 @@ func main() {
 	defer redisClient.Client.Close()
 	logrus.Info("Redis connected successfully")
 
+	// 初始化邮件发送器
+	mail, err := mailer.NewFromConfig(cfg)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to initialize mailer")
+	}
+
+	// 初始化对象存储
+	blobs, err := storage.NewFromConfig(cfg)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to initialize blob storage")
+	}
+
 	// 设置路由
-	r := routes.SetupRoutes(cfg)
+	r := routes.SetupRoutes(cfg, db, redisClient, mail, blobs)
 
 	// 在开发环境下添加测试路由
 	routes.SetupTestRoutes(r)
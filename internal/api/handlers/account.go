@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/xuchengvcc/restart-life-api/internal/api/middleware"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+)
+
+// SendVerificationEmail 发送邮箱验证邮件
+// @Summary 发送邮箱验证邮件
+// @Description 向当前用户绑定的邮箱发送验证链接，同一用户每分钟最多发送一次
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SuccessResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 429 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/email/verification [post]
+func (h *AuthHandler) SendVerificationEmail(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "UNAUTHORIZED", "请先登录")
+		return
+	}
+
+	if err := h.accountService.SendVerificationEmail(c.Request.Context(), userID); err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "验证邮件已发送", nil)
+}
+
+// VerifyEmail 验证邮箱
+// @Summary 验证邮箱
+// @Description 使用验证邮件中的令牌完成邮箱验证，令牌只能使用一次
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.VerifyEmailRequest true "验证令牌"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/email/verify [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req models.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "请求数据格式错误", err.Error())
+		return
+	}
+
+	if err := h.accountService.VerifyEmail(c.Request.Context(), req.Token); err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "邮箱验证成功", nil)
+}
+
+// ForgotPassword 找回密码
+// @Summary 找回密码
+// @Description 向邮箱发送重置密码链接，邮箱未注册时同样返回成功
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.ForgotPasswordRequest true "注册邮箱"
+// @Success 200 {object} SuccessResponse
+// @Router /api/v1/auth/password/forgot [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req models.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "请求数据格式错误", err.Error())
+		return
+	}
+
+	if err := h.accountService.RequestPasswordReset(c.Request.Context(), req.Email); err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "如果该邮箱已注册，重置密码邮件已发送", nil)
+}
+
+// ResetPassword 重置密码
+// @Summary 重置密码
+// @Description 使用重置邮件中的令牌设置新密码，成功后所有设备需重新登录
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.ResetPasswordRequest true "重置信息"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req models.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "请求数据格式错误", err.Error())
+		return
+	}
+
+	if err := h.accountService.ResetPassword(c.Request.Context(), req.Token, req.NewPassword); err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "密码已重置，请重新登录", nil)
+}
+
+// sendVerificationEmail 注册或绑定邮箱后自动发送验证邮件，失败不影响主流程
+func (h *AuthHandler) sendVerificationEmail(c *gin.Context, userID uint64) {
+	if err := h.accountService.SendVerificationEmail(c.Request.Context(), userID); err != nil {
+		logrus.WithError(err).WithField("user_id", userID).Warn("Failed to send verification email")
+	}
+}
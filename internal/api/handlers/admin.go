@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/xuchengvcc/restart-life-api/internal/api/middleware"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/services"
+)
+
+// AdminHandler 管理后台处理器
+type AdminHandler struct {
+	adminService *services.AdminService
+}
+
+// NewAdminHandler 创建管理后台处理器
+func NewAdminHandler(adminService *services.AdminService) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+	}
+}
+
+// GetUser 查看用户详情
+// @Summary 查看用户详情
+// @Description 查看用户资料、状态及其角色权限
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path int true "用户ID"
+// @Success 200 {object} models.AdminUserDetail
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/admin/users/{user_id} [get]
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	detail, err := h.adminService.GetUser(c.Request.Context(), userID)
+	if err != nil {
+		h.handleAdminError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "", detail)
+}
+
+// UpdateUserStatus 启用或禁用用户
+// @Summary 启用或禁用用户
+// @Description 禁用后该用户的全部会话立即失效且无法登录；拥有管理后台权限的用户只能由超级管理员修改
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path int true "用户ID"
+// @Param request body models.UpdateUserStatusRequest true "用户状态"
+// @Success 200 {object} SuccessResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Router /api/v1/admin/users/{user_id}/status [put]
+func (h *AdminHandler) UpdateUserStatus(c *gin.Context) {
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+	operatorID, _ := middleware.GetUserID(c)
+
+	var req models.UpdateUserStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "请求数据格式错误", err.Error())
+		return
+	}
+
+	if err := h.adminService.SetUserActive(c.Request.Context(), operatorID, userID, *req.IsActive); err != nil {
+		h.handleAdminError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "用户状态已更新", nil)
+}
+
+// ListRoles 列出角色
+// @Summary 列出角色
+// @Description 列出全部角色及其权限
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Role
+// @Router /api/v1/admin/roles [get]
+func (h *AdminHandler) ListRoles(c *gin.Context) {
+	roles, err := h.adminService.ListRoles(c.Request.Context())
+	if err != nil {
+		h.handleAdminError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "", roles)
+}
+
+// AssignRole 授予角色
+// @Summary 授予角色
+// @Description 为用户授予角色，用户刷新令牌后获得新权限；授予含管理后台权限的角色或修改管理后台用户需超级管理员
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path int true "用户ID"
+// @Param request body models.AssignRoleRequest true "角色"
+// @Success 200 {object} SuccessResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/admin/users/{user_id}/roles [post]
+func (h *AdminHandler) AssignRole(c *gin.Context) {
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+	operatorID, _ := middleware.GetUserID(c)
+
+	var req models.AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "请求数据格式错误", err.Error())
+		return
+	}
+
+	if err := h.adminService.AssignRole(c.Request.Context(), operatorID, userID, req.Role); err != nil {
+		h.handleAdminError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "角色已授予", nil)
+}
+
+// RevokeRole 撤销角色
+// @Summary 撤销角色
+// @Description 撤销用户的角色，用户当前的访问令牌立即失效；拥有管理后台权限的用户只能由超级管理员修改
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path int true "用户ID"
+// @Param role path string true "角色名"
+// @Success 200 {object} SuccessResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/admin/users/{user_id}/roles/{role} [delete]
+func (h *AdminHandler) RevokeRole(c *gin.Context) {
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+	operatorID, _ := middleware.GetUserID(c)
+
+	if err := h.adminService.RevokeRole(c.Request.Context(), operatorID, userID, c.Param("role")); err != nil {
+		h.handleAdminError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "角色已撤销", nil)
+}
+
+// DeleteCharacter 彻底删除角色
+// @Summary 彻底删除角色
+// @Description 立即彻底删除玩家角色及其全部数据，包括已归档的角色，不可恢复
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "角色ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/admin/characters/{id} [delete]
+func (h *AdminHandler) DeleteCharacter(c *gin.Context) {
+	characterID, ok := characterIDParam(c, "id")
+	if !ok {
+		return
+	}
+	operatorID, _ := middleware.GetUserID(c)
+
+	if err := h.adminService.DeleteCharacter(c.Request.Context(), operatorID, characterID); err != nil {
+		h.handleAdminError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "角色已彻底删除", nil)
+}
+
+// handleAdminError 将管理后台业务错误映射为HTTP响应
+func (h *AdminHandler) handleAdminError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		respondError(c, http.StatusNotFound, "USER_NOT_FOUND", "用户不存在")
+	case errors.Is(err, services.ErrRoleNotFound):
+		respondError(c, http.StatusNotFound, "ROLE_NOT_FOUND", "角色不存在")
+	case errors.Is(err, services.ErrRoleNotAssigned):
+		respondError(c, http.StatusNotFound, "ROLE_NOT_ASSIGNED", "用户未拥有该角色")
+	case errors.Is(err, services.ErrCharacterNotFound):
+		respondError(c, http.StatusNotFound, "CHARACTER_NOT_FOUND", "角色不存在")
+	case errors.Is(err, services.ErrCannotModifySelf):
+		respondError(c, http.StatusForbidden, "CANNOT_MODIFY_SELF", "不能修改自己的账户状态或角色")
+	case errors.Is(err, services.ErrAdminRequired):
+		respondError(c, http.StatusForbidden, "ADMIN_REQUIRED", "涉及管理后台权限的用户或角色仅超级管理员可以修改")
+	default:
+		logrus.WithError(err).WithField("request_id", c.GetString(middleware.RequestIDKey)).Error("Admin request failed")
+		respondError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "服务器内部错误，请稍后重试")
+	}
+}
+
+// userIDParam 解析路径中的用户ID，失败时直接返回400
+func userIDParam(c *gin.Context) (uint64, bool) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil || userID == 0 {
+		respondError(c, http.StatusBadRequest, "INVALID_USER_ID", "用户ID格式错误")
+		return 0, false
+	}
+	return userID, true
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/xuchengvcc/restart-life-api/internal/api/middleware"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/services"
+)
+
+// APIKeyHandler 个人 API 密钥处理器
+type APIKeyHandler struct {
+	apiKeyService *services.APIKeyService
+}
+
+// NewAPIKeyHandler 创建 API 密钥处理器
+func NewAPIKeyHandler(apiKeyService *services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateAPIKey 创建 API 密钥
+// @Summary 创建 API 密钥
+// @Description 创建用于脚本和服务端调用的 API 密钥，通过 X-API-Key 请求头使用；明文密钥只在此返回一次
+// @Description 授权范围可以是 game:read、game:write 或当前用户拥有的权限
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateAPIKeyRequest true "密钥信息"
+// @Success 201 {object} models.CreateAPIKeyResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req models.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "请求数据格式错误", err.Error())
+		return
+	}
+
+	resp, err := h.apiKeyService.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.handleAPIKeyError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusCreated, "API 密钥已创建，请立即妥善保存，之后将无法再次查看", resp)
+}
+
+// ListAPIKeys 列出 API 密钥
+// @Summary 列出 API 密钥
+// @Description 列出当前用户未吊销的 API 密钥，不包含密钥明文
+// @Tags api-keys
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.APIKey
+// @Router /api/v1/auth/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	keys, err := h.apiKeyService.List(c.Request.Context(), userID)
+	if err != nil {
+		h.handleAPIKeyError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "", keys)
+}
+
+// RevokeAPIKey 吊销 API 密钥
+// @Summary 吊销 API 密钥
+// @Description 吊销指定的 API 密钥，立即生效
+// @Tags api-keys
+// @Produce json
+// @Security BearerAuth
+// @Param key_id path int true "密钥ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/api-keys/{key_id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	keyID, err := strconv.ParseUint(c.Param("key_id"), 10, 64)
+	if err != nil || keyID == 0 {
+		respondError(c, http.StatusBadRequest, "INVALID_KEY_ID", "密钥ID格式错误")
+		return
+	}
+
+	if err := h.apiKeyService.Revoke(c.Request.Context(), userID, keyID); err != nil {
+		h.handleAPIKeyError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "API 密钥已吊销", nil)
+}
+
+// handleAPIKeyError 将 API 密钥业务错误映射为HTTP响应
+func (h *APIKeyHandler) handleAPIKeyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrAPIKeyNotFound):
+		respondError(c, http.StatusNotFound, "API_KEY_NOT_FOUND", "API 密钥不存在或已吊销")
+	case errors.Is(err, services.ErrAPIKeyLimitReached):
+		respondError(c, http.StatusConflict, "API_KEY_LIMIT_REACHED", "API 密钥数量已达上限，请先吊销不再使用的密钥")
+	case errors.Is(err, services.ErrInvalidAPIKeyScope):
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_SCOPE", "授权范围无效或超出自身权限", err.Error())
+	case errors.Is(err, services.ErrInvalidAPIKeyRateLimit):
+		respondError(c, http.StatusBadRequest, "INVALID_RATE_LIMIT", "请求频率上限超出允许范围")
+	default:
+		logrus.WithError(err).WithField("request_id", c.GetString(middleware.RequestIDKey)).Error("API key request failed")
+		respondError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "服务器内部错误，请稍后重试")
+	}
+}
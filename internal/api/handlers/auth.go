@@ -0,0 +1,390 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/xuchengvcc/restart-life-api/internal/api/middleware"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/services"
+	"github.com/xuchengvcc/restart-life-api/internal/services/providers"
+)
+
+// AuthHandler 认证处理器
+type AuthHandler struct {
+	authService      *services.AuthService
+	accountService   *services.AccountService
+	twoFactorService *services.TwoFactorService
+}
+
+// NewAuthHandler 创建认证处理器
+func NewAuthHandler(
+	authService *services.AuthService,
+	accountService *services.AccountService,
+	twoFactorService *services.TwoFactorService,
+) *AuthHandler {
+	return &AuthHandler{
+		authService:      authService,
+		accountService:   accountService,
+		twoFactorService: twoFactorService,
+	}
+}
+
+// Register 用户注册
+// @Summary 用户注册
+// @Description 使用用户名、邮箱和密码注册账户
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.RegisterRequest true "注册信息"
+// @Success 201 {object} models.AuthResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/register [post]
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req models.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "请求数据格式错误", err.Error())
+		return
+	}
+
+	resp, err := h.authService.Register(c.Request.Context(), &req, deviceInfoFromRequest(c))
+	if err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+	h.sendVerificationEmail(c, resp.User.UserID)
+
+	respondSuccess(c, http.StatusCreated, "注册成功", resp)
+}
+
+// Login 用户登录
+// @Summary 用户登录
+// @Description 使用用户名或邮箱 + 密码登录，连续失败达到阈值后账户或来源IP会被临时锁定
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.LoginRequest true "登录信息"
+// @Success 200 {object} models.AuthResponse "已启用两步验证时返回 models.TwoFactorChallenge"
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 429 {object} middleware.ErrorResponse "ACCOUNT_LOCKED 或 TOO_MANY_LOGIN_ATTEMPTS，附带 Retry-After"
+// @Router /api/v1/auth/login [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req models.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "请求数据格式错误", err.Error())
+		return
+	}
+
+	resp, challenge, err := h.authService.Login(c.Request.Context(), &req, deviceInfoFromRequest(c))
+	if err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+
+	respondLogin(c, resp, challenge)
+}
+
+// CompleteTwoFactorLogin 两步验证登录
+// @Summary 两步验证登录
+// @Description 提交登录挑战令牌和 TOTP 验证码（或恢复码）完成登录
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.TwoFactorLoginRequest true "挑战令牌与验证码"
+// @Success 200 {object} models.AuthResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/login/2fa [post]
+func (h *AuthHandler) CompleteTwoFactorLogin(c *gin.Context) {
+	var req models.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "请求数据格式错误", err.Error())
+		return
+	}
+
+	resp, err := h.authService.CompleteTwoFactorLogin(c.Request.Context(), &req)
+	if err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "登录成功", resp)
+}
+
+// GuestLogin 游客登录
+// @Summary 游客登录
+// @Description 使用设备ID登录，设备首次登录时自动创建游客账户
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.GuestLoginRequest true "设备信息"
+// @Success 200 {object} models.AuthResponse
+// @Router /api/v1/auth/guest [post]
+func (h *AuthHandler) GuestLogin(c *gin.Context) {
+	var req models.GuestLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "请求数据格式错误", err.Error())
+		return
+	}
+
+	resp, err := h.authService.GuestLogin(c.Request.Context(), &req, deviceInfoFromRequest(c))
+	if err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "登录成功", resp)
+}
+
+// WeChatLogin 微信小程序登录
+// @Summary 微信小程序登录
+// @Description 使用 wx.login 获取的 code 登录，首次登录自动创建账户
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.WeChatLoginRequest true "微信登录凭据"
+// @Success 200 {object} models.AuthResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/wechat/login [post]
+func (h *AuthHandler) WeChatLogin(c *gin.Context) {
+	var req models.WeChatLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "请求数据格式错误", err.Error())
+		return
+	}
+
+	device := deviceInfoFromRequest(c)
+	if c.GetHeader("X-Platform") == "" {
+		device.Platform = "wechat"
+	}
+
+	resp, challenge, err := h.authService.LoginWithProvider(c.Request.Context(), providers.WeChatProviderName, req.Code, device)
+	if err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+
+	respondLogin(c, resp, challenge)
+}
+
+// OAuthAuthorize 发起 OpenID Connect 登录
+// @Summary 发起第三方授权
+// @Description 生成 state 与 PKCE 参数，返回提供方授权页地址
+// @Tags auth
+// @Produce json
+// @Param provider path string true "提供方名称"
+// @Success 200 {object} models.OAuthAuthorizeResponse
+// @Router /api/v1/auth/oauth/{provider}/authorize [get]
+func (h *AuthHandler) OAuthAuthorize(c *gin.Context) {
+	resp, err := h.authService.BeginOAuth(c.Request.Context(), c.Param("provider"), deviceInfoFromRequest(c))
+	if err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "", resp)
+}
+
+// OAuthCallback 完成 OpenID Connect 登录
+// @Summary 第三方授权回调
+// @Description 校验 state，使用授权码换取并校验 ID 令牌后登录，首次登录自动创建账户
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param provider path string true "提供方名称"
+// @Param request body models.OAuthCallbackRequest true "授权回调参数"
+// @Success 200 {object} models.AuthResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/oauth/{provider}/callback [post]
+func (h *AuthHandler) OAuthCallback(c *gin.Context) {
+	var req models.OAuthCallbackRequest
+	if err := c.ShouldBind(&req); err != nil {
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "请求数据格式错误", err.Error())
+		return
+	}
+
+	resp, challenge, err := h.authService.CompleteOAuth(c.Request.Context(), c.Param("provider"), &req, deviceInfoFromRequest(c))
+	if err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+
+	respondLogin(c, resp, challenge)
+}
+
+// UpgradeGuest 游客账户升级
+// @Summary 游客账户升级
+// @Description 为当前游客账户绑定邮箱和密码，升级为正式账户，已有角色全部保留
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UpgradeGuestRequest true "账户信息"
+// @Success 200 {object} models.AuthResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/guest/upgrade [post]
+func (h *AuthHandler) UpgradeGuest(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "UNAUTHORIZED", "请先登录")
+		return
+	}
+
+	var req models.UpgradeGuestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "请求数据格式错误", err.Error())
+		return
+	}
+
+	resp, err := h.authService.UpgradeGuest(c.Request.Context(), userID, &req, deviceInfoFromRequest(c))
+	if err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+	h.sendVerificationEmail(c, userID)
+
+	respondSuccess(c, http.StatusOK, "账户升级成功", resp)
+}
+
+// Refresh 刷新令牌
+// @Summary 刷新令牌
+// @Description 使用刷新令牌换取新的访问令牌和刷新令牌，旧刷新令牌立即失效
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.RefreshRequest true "刷新令牌"
+// @Success 200 {object} models.AuthResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req models.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "请求数据格式错误", err.Error())
+		return
+	}
+
+	resp, err := h.authService.Refresh(c.Request.Context(), req.RefreshToken, deviceInfoFromRequest(c))
+	if err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "令牌刷新成功", resp)
+}
+
+// Logout 登出当前会话
+// @Summary 登出
+// @Description 吊销当前访问令牌及其所属会话的刷新令牌
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SuccessResponse
+// @Router /api/v1/auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	claims, ok := middleware.GetClaims(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "UNAUTHORIZED", "请先登录")
+		return
+	}
+
+	if err := h.authService.Logout(c.Request.Context(), claims); err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "已登出", nil)
+}
+
+// LogoutAll 登出所有设备
+// @Summary 登出所有设备
+// @Description 吊销当前用户已签发的全部访问令牌和刷新令牌
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SuccessResponse
+// @Router /api/v1/auth/logout/all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "UNAUTHORIZED", "请先登录")
+		return
+	}
+
+	if err := h.authService.LogoutAll(c.Request.Context(), userID); err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "已登出所有设备", nil)
+}
+
+// handleAuthError 将认证服务错误映射为HTTP响应
+func (h *AuthHandler) handleAuthError(c *gin.Context, err error) {
+	var locked *services.LoginLockedError
+	if errors.As(err, &locked) {
+		if errors.Is(locked, services.ErrTooManyLoginAttempts) {
+			respondRetryAfter(c, http.StatusTooManyRequests, "TOO_MANY_LOGIN_ATTEMPTS", "登录失败次数过多，请稍后再试", locked.RetryAfter)
+		} else {
+			respondRetryAfter(c, http.StatusTooManyRequests, "ACCOUNT_LOCKED", "登录失败次数过多，账户已被临时锁定", locked.RetryAfter)
+		}
+		return
+	}
+
+	switch {
+	case errors.Is(err, services.ErrInvalidUsername):
+		respondError(c, http.StatusBadRequest, "INVALID_USERNAME", "用户名只能包含字母、数字、下划线、点和连字符，长度3-50")
+	case errors.Is(err, services.ErrPasswordTooLong):
+		respondError(c, http.StatusBadRequest, "PASSWORD_TOO_LONG", "密码过长，最多 72 字节（中文等字符每个占 3 字节）")
+	case errors.Is(err, services.ErrUsernameTaken):
+		respondError(c, http.StatusConflict, "USERNAME_EXISTS", "用户名已被占用")
+	case errors.Is(err, services.ErrEmailTaken):
+		respondError(c, http.StatusConflict, "EMAIL_EXISTS", "邮箱已被注册")
+	case errors.Is(err, services.ErrInvalidCredentials):
+		respondError(c, http.StatusUnauthorized, "INVALID_CREDENTIALS", "用户名或密码错误")
+	case errors.Is(err, services.ErrUserDisabled):
+		respondError(c, http.StatusForbidden, "ACCOUNT_DISABLED", "账户已被禁用")
+	case errors.Is(err, services.ErrInvalidRefreshToken):
+		respondError(c, http.StatusUnauthorized, "INVALID_REFRESH_TOKEN", "刷新令牌无效或已过期")
+	case errors.Is(err, services.ErrRefreshTokenReused):
+		respondError(c, http.StatusUnauthorized, "REFRESH_TOKEN_REUSED", "刷新令牌已被使用，请重新登录")
+	case errors.Is(err, services.ErrEmailNotSet):
+		respondError(c, http.StatusBadRequest, "EMAIL_NOT_SET", "账户尚未绑定邮箱")
+	case errors.Is(err, services.ErrEmailAlreadyVerified):
+		respondError(c, http.StatusConflict, "EMAIL_ALREADY_VERIFIED", "邮箱已验证")
+	case errors.Is(err, services.ErrMailRateLimited):
+		respondError(c, http.StatusTooManyRequests, "TOO_MANY_REQUESTS", "邮件发送过于频繁，请稍后再试")
+	case errors.Is(err, services.ErrInvalidActionToken):
+		respondError(c, http.StatusBadRequest, "INVALID_LINK", "链接无效、已过期或已使用")
+	case errors.Is(err, services.ErrTwoFactorAlreadyEnabled):
+		respondError(c, http.StatusConflict, "TWO_FACTOR_ALREADY_ENABLED", "两步验证已启用")
+	case errors.Is(err, services.ErrTwoFactorNotEnabled):
+		respondError(c, http.StatusConflict, "TWO_FACTOR_NOT_ENABLED", "两步验证未启用")
+	case errors.Is(err, services.ErrTwoFactorSetupRequired):
+		respondError(c, http.StatusConflict, "TWO_FACTOR_SETUP_REQUIRED", "请先生成两步验证密钥")
+	case errors.Is(err, services.ErrGuestTwoFactor):
+		respondError(c, http.StatusForbidden, "GUEST_NOT_ALLOWED", "游客账户请先升级为正式账户")
+	case errors.Is(err, services.ErrInvalidTwoFactorCode):
+		respondError(c, http.StatusUnauthorized, "INVALID_TWO_FACTOR_CODE", "验证码错误")
+	case errors.Is(err, services.ErrInvalidLoginChallenge):
+		respondError(c, http.StatusUnauthorized, "INVALID_LOGIN_CHALLENGE", "登录验证已过期，请重新登录")
+	case errors.Is(err, services.ErrSessionNotFound):
+		respondError(c, http.StatusNotFound, "SESSION_NOT_FOUND", "会话不存在或已结束")
+	case errors.Is(err, services.ErrNotGuest):
+		respondError(c, http.StatusConflict, "NOT_GUEST_ACCOUNT", "当前账户不是游客账户")
+	case errors.Is(err, services.ErrProviderNotSupported):
+		respondError(c, http.StatusBadRequest, "UNSUPPORTED_LOGIN_TYPE", "不支持的登录方式")
+	case errors.Is(err, services.ErrInvalidOAuthState):
+		respondError(c, http.StatusBadRequest, "INVALID_OAUTH_STATE", "授权请求无效或已过期，请重新发起登录")
+	case errors.Is(err, providers.ErrInvalidCredential):
+		respondError(c, http.StatusUnauthorized, "THIRD_PARTY_AUTH_FAILED", "第三方授权失败")
+	case errors.Is(err, providers.ErrProviderUnavailable):
+		logrus.WithError(err).Warn("Login provider unavailable")
+		respondError(c, http.StatusBadGateway, "PROVIDER_UNAVAILABLE", "第三方登录服务暂不可用，请稍后重试")
+	case errors.Is(err, services.ErrUserNotFound):
+		respondError(c, http.StatusNotFound, "USER_NOT_FOUND", "用户不存在")
+	default:
+		logrus.WithError(err).WithField("request_id", c.GetString("request_id")).Error("Auth request failed")
+		respondError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "服务器内部错误，请稍后重试")
+	}
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuchengvcc/restart-life-api/internal/catalog"
+)
+
+// CatalogHandler 参考数据处理器
+type CatalogHandler struct {
+	catalog *catalog.Catalog
+}
+
+// NewCatalogHandler 创建参考数据处理器
+func NewCatalogHandler(catalog *catalog.Catalog) *CatalogHandler {
+	return &CatalogHandler{catalog: catalog}
+}
+
+// CountryListResponse 国家列表响应
+type CountryListResponse struct {
+	Version   string             `json:"version"`
+	Regions   []catalog.Region   `json:"regions"`
+	Countries []*catalog.Country `json:"countries"`
+}
+
+// EraListResponse 时代列表响应
+type EraListResponse struct {
+	Version string        `json:"version"`
+	MinYear int           `json:"min_year"`
+	MaxYear int           `json:"max_year"`
+	Eras    []catalog.Era `json:"eras"`
+}
+
+// ListCountries 国家列表
+// @Summary 国家列表
+// @Description 返回可选的出生国家/地区，包含多语言名称、所属地区和存在年份；指定 year 时只返回该年份存在的国家
+// @Tags catalog
+// @Produce json
+// @Param region query string false "地区代码，如 asia"
+// @Param year query int false "出生年份"
+// @Success 200 {object} CountryListResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Router /api/v1/catalog/countries [get]
+func (h *CatalogHandler) ListCountries(c *gin.Context) {
+	region := c.Query("region")
+	if region != "" && !h.catalog.HasRegion(region) {
+		respondError(c, http.StatusBadRequest, "INVALID_REGION", "地区不存在")
+		return
+	}
+
+	year := 0
+	if raw := c.Query("year"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || !h.catalog.InRange(parsed) {
+			respondError(c, http.StatusBadRequest, "INVALID_BIRTH_YEAR", "出生年份超出游戏支持的范围")
+			return
+		}
+		year = parsed
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	respondSuccess(c, http.StatusOK, "", CountryListResponse{
+		Version:   h.catalog.Version,
+		Regions:   h.catalog.Regions,
+		Countries: h.catalog.CountriesIn(region, year),
+	})
+}
+
+// ListEras 时代列表
+// @Summary 时代列表
+// @Description 返回游戏支持的出生年份范围及各时代的起止年份
+// @Tags catalog
+// @Produce json
+// @Success 200 {object} EraListResponse
+// @Router /api/v1/catalog/eras [get]
+func (h *CatalogHandler) ListEras(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=3600")
+	respondSuccess(c, http.StatusOK, "", EraListResponse{
+		Version: h.catalog.Version,
+		MinYear: h.catalog.MinYear,
+		MaxYear: h.catalog.MaxYear,
+		Eras:    h.catalog.Eras,
+	})
+}
@@ -0,0 +1,412 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/xuchengvcc/restart-life-api/internal/api/middleware"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/pagination"
+	"github.com/xuchengvcc/restart-life-api/internal/services"
+)
+
+// CharacterHandler 角色处理器
+type CharacterHandler struct {
+	characterService *services.CharacterService
+}
+
+// NewCharacterHandler 创建角色处理器
+func NewCharacterHandler(characterService *services.CharacterService) *CharacterHandler {
+	return &CharacterHandler{
+		characterService: characterService,
+	}
+}
+
+// CreateDraft 生成角色草稿
+// @Summary 生成角色草稿
+// @Description 按出生国家和年份生成候选角色，草稿暂存一段时间，确认前不会保存；可多次重新生成后再确认
+// @Description 创建角色只能通过草稿确认完成，先天条件的种子由服务端选取
+// @Tags characters
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateCharacterRequest true "角色信息"
+// @Success 201 {object} models.CharacterDraftResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Router /api/v1/characters/drafts [post]
+func (h *CharacterHandler) CreateDraft(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req models.CreateCharacterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "请求数据格式错误", err.Error())
+		return
+	}
+
+	draft, err := h.characterService.CreateDraft(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.handleCharacterError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusCreated, "角色草稿已生成", draft)
+}
+
+// GetDraft 获取角色草稿
+// @Summary 获取角色草稿
+// @Description 获取未过期的角色草稿及其角色预览
+// @Tags characters
+// @Produce json
+// @Security BearerAuth
+// @Param draft_id path string true "草稿ID"
+// @Success 200 {object} models.CharacterDraftResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/characters/drafts/{draft_id} [get]
+func (h *CharacterHandler) GetDraft(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	draftID, ok := draftIDParam(c)
+	if !ok {
+		return
+	}
+
+	draft, err := h.characterService.GetDraft(c.Request.Context(), userID, draftID)
+	if err != nil {
+		h.handleCharacterError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "", draft)
+}
+
+// RerollDraft 重新生成角色草稿
+// @Summary 重新生成角色草稿
+// @Description 保留角色名、出生国家和年份，以新的随机种子重新生成全部随机属性，每个草稿的重新生成次数有限
+// @Tags characters
+// @Produce json
+// @Security BearerAuth
+// @Param draft_id path string true "草稿ID"
+// @Success 200 {object} models.CharacterDraftResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Router /api/v1/characters/drafts/{draft_id}/reroll [post]
+func (h *CharacterHandler) RerollDraft(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	draftID, ok := draftIDParam(c)
+	if !ok {
+		return
+	}
+
+	draft, err := h.characterService.RerollDraft(c.Request.Context(), userID, draftID)
+	if err != nil {
+		h.handleCharacterError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "角色草稿已重新生成", draft)
+}
+
+// ConfirmDraft 确认角色草稿
+// @Summary 确认角色草稿
+// @Description 将草稿保存为正式角色，草稿随即失效
+// @Tags characters
+// @Produce json
+// @Security BearerAuth
+// @Param draft_id path string true "草稿ID"
+// @Success 201 {object} models.Character
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/characters/drafts/{draft_id}/confirm [post]
+func (h *CharacterHandler) ConfirmDraft(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	draftID, ok := draftIDParam(c)
+	if !ok {
+		return
+	}
+
+	character, err := h.characterService.ConfirmDraft(c.Request.Context(), userID, draftID)
+	if err != nil {
+		h.handleCharacterError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusCreated, "角色已创建", character)
+}
+
+// DeleteDraft 放弃角色草稿
+// @Summary 放弃角色草稿
+// @Description 删除未确认的角色草稿
+// @Tags characters
+// @Produce json
+// @Security BearerAuth
+// @Param draft_id path string true "草稿ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/characters/drafts/{draft_id} [delete]
+func (h *CharacterHandler) DeleteDraft(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	draftID, ok := draftIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.characterService.DeleteDraft(c.Request.Context(), userID, draftID); err != nil {
+		h.handleCharacterError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "角色草稿已删除", nil)
+}
+
+// ListCharacters 列出角色
+// @Summary 列出角色
+// @Description 分页列出当前用户的角色，默认列出全部未删除的角色并按创建时间倒序；status 可选 active（进行中）、completed（人生已结束）、archived（已删除，可恢复）
+// @Description 翻页时将上一页返回的 next_cursor 作为 cursor 传入，排序和筛选参数需保持不变；按 final_age 排序时人生未结束的角色视为最小
+// @Tags characters
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "角色状态" Enums(active, completed, archived)
+// @Param birth_country query string false "出生国家代码"
+// @Param birth_year_min query int false "出生年份下限（含）"
+// @Param birth_year_max query int false "出生年份上限（含）"
+// @Param game_completed query bool false "人生是否已结束"
+// @Param life_stage query string false "人生阶段"
+// @Param sort query string false "排序字段，默认 created_at" Enums(created_at, final_age, money)
+// @Param order query string false "排序方向，默认 desc" Enums(asc, desc)
+// @Param cursor query string false "分页游标"
+// @Param limit query int false "每页数量，默认 20，最大 100"
+// @Success 200 {object} pagination.Page[models.Character]
+// @Failure 400 {object} middleware.ErrorResponse
+// @Router /api/v1/characters [get]
+func (h *CharacterHandler) ListCharacters(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var query models.CharacterListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "查询参数格式错误", err.Error())
+		return
+	}
+
+	page, err := h.characterService.List(c.Request.Context(), userID, &query)
+	if err != nil {
+		h.handleCharacterError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "", page)
+}
+
+// GetCharacter 获取角色
+// @Summary 获取角色
+// @Description 获取当前用户的指定角色，包含出生时的家庭和性格倾向；其他用户的角色同样返回 404
+// @Tags characters
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "角色ID"
+// @Success 200 {object} models.Character
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/characters/{id} [get]
+func (h *CharacterHandler) GetCharacter(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	characterID, ok := characterIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	character, err := h.characterService.Get(c.Request.Context(), userID, characterID)
+	if err != nil {
+		h.handleCharacterError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "", character)
+}
+
+// UpdateCharacter 更新角色
+// @Summary 更新角色
+// @Description 修改角色名、当前位置和当前活动，未提供的字段保持不变
+// @Tags characters
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "角色ID"
+// @Param request body models.UpdateCharacterRequest true "角色信息"
+// @Success 200 {object} models.Character
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/characters/{id} [put]
+func (h *CharacterHandler) UpdateCharacter(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	characterID, ok := characterIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateCharacterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "请求数据格式错误", err.Error())
+		return
+	}
+
+	character, err := h.characterService.Update(c.Request.Context(), userID, characterID, &req)
+	if err != nil {
+		h.handleCharacterError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "角色已更新", character)
+}
+
+// DeleteCharacter 删除角色
+// @Summary 删除角色
+// @Description 删除当前用户的指定角色；角色先归档，purge_at 之前可以恢复，之后彻底删除
+// @Tags characters
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "角色ID"
+// @Success 200 {object} models.Character
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Router /api/v1/characters/{id} [delete]
+func (h *CharacterHandler) DeleteCharacter(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	characterID, ok := characterIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	character, err := h.characterService.Delete(c.Request.Context(), userID, characterID)
+	if err != nil {
+		h.handleCharacterError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "角色已删除，可在保留期内恢复", character)
+}
+
+// RestoreCharacter 恢复角色
+// @Summary 恢复角色
+// @Description 恢复保留期内删除的角色
+// @Tags characters
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "角色ID"
+// @Success 200 {object} models.Character
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Router /api/v1/characters/{id}/restore [post]
+func (h *CharacterHandler) RestoreCharacter(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	characterID, ok := characterIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	character, err := h.characterService.Restore(c.Request.Context(), userID, characterID)
+	if err != nil {
+		h.handleCharacterError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "角色已恢复", character)
+}
+
+// TrainSkill 训练技能
+// @Summary 训练技能
+// @Description 训练角色的一项技能（academic、social、athletic、artistic、business），提升幅度取决于相关属性，技能越高提升越慢，上限随年龄增长；同一角色两次训练之间有冷却期；训练作为生活事件会使性格倾向略微变化
+// @Tags characters
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "角色ID"
+// @Param skill path string true "技能"
+// @Success 200 {object} models.TrainSkillResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 429 {object} middleware.ErrorResponse
+// @Router /api/v1/characters/{id}/skills/{skill}/train [post]
+func (h *CharacterHandler) TrainSkill(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	characterID, ok := characterIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	result, err := h.characterService.TrainSkill(c.Request.Context(), userID, characterID, c.Param("skill"))
+	if err != nil {
+		h.handleCharacterError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "训练完成", result)
+}
+
+// handleCharacterError 将角色业务错误映射为HTTP响应
+func (h *CharacterHandler) handleCharacterError(c *gin.Context, err error) {
+	var cooldown *services.SkillTrainingCooldownError
+	if errors.As(err, &cooldown) {
+		respondRetryAfter(c, http.StatusTooManyRequests, "SKILL_TRAINING_COOLDOWN", "训练过于频繁，请稍后再试", cooldown.RetryAfter)
+		return
+	}
+
+	switch {
+	case errors.Is(err, services.ErrCharacterNotFound):
+		respondError(c, http.StatusNotFound, "CHARACTER_NOT_FOUND", "角色不存在")
+	case errors.Is(err, services.ErrInvalidCharacterName):
+		respondError(c, http.StatusBadRequest, "INVALID_CHARACTER_NAME", "角色名不能为空")
+	case errors.Is(err, services.ErrInvalidCountry):
+		respondError(c, http.StatusBadRequest, "INVALID_COUNTRY", "出生国家不存在，请从国家列表中选择")
+	case errors.Is(err, services.ErrInvalidBirthYear):
+		respondError(c, http.StatusBadRequest, "INVALID_BIRTH_YEAR", "出生年份超出游戏支持的范围")
+	case errors.Is(err, services.ErrCountryNotAvailable):
+		respondError(c, http.StatusBadRequest, "COUNTRY_NOT_AVAILABLE", "该国家在所选出生年份不存在")
+	case errors.Is(err, services.ErrCharacterDraftNotFound):
+		respondError(c, http.StatusNotFound, "CHARACTER_DRAFT_NOT_FOUND", "角色草稿不存在或已过期")
+	case errors.Is(err, services.ErrRerollLimitReached):
+		respondError(c, http.StatusConflict, "REROLL_LIMIT_REACHED", "重新生成次数已用完")
+	case errors.Is(err, services.ErrUnknownSkill):
+		respondError(c, http.StatusBadRequest, "INVALID_SKILL", "技能不存在")
+	case errors.Is(err, services.ErrSkillAtCap):
+		respondError(c, http.StatusConflict, "SKILL_AT_CAP", "技能已达到当前年龄的上限")
+	case errors.Is(err, services.ErrCharacterEnded):
+		respondError(c, http.StatusConflict, "CHARACTER_ENDED", "角色的人生已结束")
+	case errors.Is(err, services.ErrCharacterArchived):
+		respondError(c, http.StatusConflict, "CHARACTER_ARCHIVED", "角色已删除，请先恢复")
+	case errors.Is(err, services.ErrCharacterNotArchived):
+		respondError(c, http.StatusConflict, "CHARACTER_NOT_ARCHIVED", "角色未被删除，无需恢复")
+	case errors.Is(err, services.ErrInvalidCharacterStatus):
+		respondError(c, http.StatusBadRequest, "INVALID_STATUS", "角色状态应为 active、completed 或 archived")
+	case errors.Is(err, services.ErrInvalidCharacterSort):
+		respondError(c, http.StatusBadRequest, "INVALID_SORT", "排序字段应为 created_at、final_age 或 money，排序方向应为 asc 或 desc")
+	case errors.Is(err, services.ErrInvalidBirthYearRange):
+		respondError(c, http.StatusBadRequest, "INVALID_BIRTH_YEAR_RANGE", "出生年份下限不能大于上限")
+	case errors.Is(err, pagination.ErrInvalidCursor):
+		respondError(c, http.StatusBadRequest, "INVALID_CURSOR", "分页游标无效，请从第一页重新查询")
+	case errors.Is(err, services.ErrSkillTrainingConflict):
+		respondError(c, http.StatusConflict, "SKILL_TRAINING_CONFLICT", "训练过于频繁，请稍后重试")
+	default:
+		logrus.WithError(err).WithField("request_id", c.GetString(middleware.RequestIDKey)).Error("Character request failed")
+		respondError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "服务器内部错误，请稍后重试")
+	}
+}
+
+// characterIDParam 解析路径中的角色ID，格式错误时直接返回 404，与角色不存在不作区分
+func characterIDParam(c *gin.Context, name string) (string, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		respondError(c, http.StatusNotFound, "CHARACTER_NOT_FOUND", "角色不存在")
+		return "", false
+	}
+	return id.String(), true
+}
+
+// draftIDParam 解析路径中的草稿ID，格式错误时直接返回 404
+func draftIDParam(c *gin.Context) (string, bool) {
+	id, err := uuid.Parse(c.Param("draft_id"))
+	if err != nil {
+		respondError(c, http.StatusNotFound, "CHARACTER_DRAFT_NOT_FOUND", "角色草稿不存在或已过期")
+		return "", false
+	}
+	return id.String(), true
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/xuchengvcc/restart-life-api/internal/utils"
+)
+
+// JWKSHandler 公钥集处理器
+type JWKSHandler struct {
+	jwt *utils.JWTManager
+}
+
+// NewJWKSHandler 创建公钥集处理器
+func NewJWKSHandler(jwt *utils.JWTManager) *JWKSHandler {
+	return &JWKSHandler{jwt: jwt}
+}
+
+// JWKS 访问令牌公钥集
+// @Summary 访问令牌公钥集
+// @Description 返回校验访问令牌所需的公钥（JWK Set），其他服务可据此独立校验令牌
+// @Tags health
+// @Produce json
+// @Success 200 {object} utils.JSONWebKeySet
+// @Router /.well-known/jwks.json [get]
+func (h *JWKSHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.jwt.JWKS())
+}
+
+// RegisterJWKSRoutes 注册公钥集路由
+func RegisterJWKSRoutes(r *gin.Engine, jwt *utils.JWTManager) {
+	handler := NewJWKSHandler(jwt)
+
+	r.GET("/.well-known/jwks.json", handler.JWKS)
+
+	logrus.Info("JWKS routes registered")
+}
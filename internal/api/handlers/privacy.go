@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/xuchengvcc/restart-life-api/internal/api/middleware"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/services"
+)
+
+// PrivacyHandler 个人数据处理器（数据导出与账户注销）
+type PrivacyHandler struct {
+	privacyService *services.PrivacyService
+}
+
+// NewPrivacyHandler 创建个人数据处理器
+func NewPrivacyHandler(privacyService *services.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{
+		privacyService: privacyService,
+	}
+}
+
+// RequestExport 申请导出个人数据
+// @Summary 申请导出个人数据
+// @Description 在后台生成包含账户信息、角色及游戏记录的 ZIP 文件，完成后可通过导出任务下载
+// @Tags privacy
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} models.DataExport
+// @Failure 429 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/account/export [post]
+func (h *PrivacyHandler) RequestExport(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	export, err := h.privacyService.RequestExport(c.Request.Context(), userID)
+	if err != nil {
+		h.handlePrivacyError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusAccepted, "导出任务已创建", export)
+}
+
+// GetExport 查询导出任务
+// @Summary 查询导出任务
+// @Description 查询个人数据导出任务的状态
+// @Tags privacy
+// @Produce json
+// @Security BearerAuth
+// @Param export_id path string true "导出任务ID"
+// @Success 200 {object} models.DataExport
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/account/export/{export_id} [get]
+func (h *PrivacyHandler) GetExport(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	export, err := h.privacyService.GetExport(c.Request.Context(), userID, c.Param("export_id"))
+	if err != nil {
+		h.handlePrivacyError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "", export)
+}
+
+// DownloadExport 下载导出文件
+// @Summary 下载导出文件
+// @Description 下载已完成的个人数据导出文件
+// @Tags privacy
+// @Produce application/zip
+// @Security BearerAuth
+// @Param export_id path string true "导出任务ID"
+// @Success 200 {file} file
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/account/export/{export_id}/download [get]
+func (h *PrivacyHandler) DownloadExport(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	exportID := c.Param("export_id")
+
+	data, err := h.privacyService.DownloadExport(c.Request.Context(), userID, exportID)
+	if err != nil {
+		h.handlePrivacyError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="restart-life-export-%s.zip"`, exportID))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/zip", data)
+}
+
+// DeleteAccount 申请注销账户
+// @Summary 申请注销账户
+// @Description 冷静期结束后删除账户及全部数据，申请后所有会话立即失效；冷静期内登录并撤销即可保留账户
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.DeleteAccountRequest true "身份确认"
+// @Success 200 {object} models.AccountDeletionResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/account/delete [post]
+func (h *PrivacyHandler) DeleteAccount(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req models.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "请求数据格式错误", err.Error())
+		return
+	}
+
+	resp, err := h.privacyService.ScheduleDeletion(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.handlePrivacyError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "注销申请已提交", resp)
+}
+
+// CancelDeletion 撤销注销申请
+// @Summary 撤销注销申请
+// @Description 冷静期内撤销账户注销申请
+// @Tags privacy
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SuccessResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/account/delete/cancel [post]
+func (h *PrivacyHandler) CancelDeletion(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.privacyService.CancelDeletion(c.Request.Context(), userID); err != nil {
+		h.handlePrivacyError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "注销申请已撤销", nil)
+}
+
+// handlePrivacyError 将个人数据业务错误映射为HTTP响应
+func (h *PrivacyHandler) handlePrivacyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		respondError(c, http.StatusNotFound, "USER_NOT_FOUND", "用户不存在")
+	case errors.Is(err, services.ErrExportNotFound):
+		respondError(c, http.StatusNotFound, "EXPORT_NOT_FOUND", "导出任务不存在或已过期")
+	case errors.Is(err, services.ErrExportNotReady):
+		respondError(c, http.StatusConflict, "EXPORT_NOT_READY", "导出文件尚未生成")
+	case errors.Is(err, services.ErrExportRateLimited):
+		respondError(c, http.StatusTooManyRequests, "EXPORT_RATE_LIMITED", "导出过于频繁，请稍后再试")
+	case errors.Is(err, services.ErrInvalidCredentials):
+		respondError(c, http.StatusUnauthorized, "INVALID_CREDENTIALS", "密码错误")
+	case errors.Is(err, services.ErrInvalidTwoFactorCode):
+		respondError(c, http.StatusUnauthorized, "INVALID_TWO_FACTOR_CODE", "验证码错误")
+	case errors.Is(err, services.ErrDeletionAlreadyScheduled):
+		respondError(c, http.StatusConflict, "DELETION_ALREADY_SCHEDULED", "已申请注销")
+	case errors.Is(err, services.ErrDeletionNotScheduled):
+		respondError(c, http.StatusConflict, "DELETION_NOT_SCHEDULED", "未申请注销")
+	default:
+		logrus.WithError(err).WithField("request_id", c.GetString(middleware.RequestIDKey)).Error("Privacy request failed")
+		respondError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "服务器内部错误，请稍后重试")
+	}
+}
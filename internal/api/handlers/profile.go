@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/xuchengvcc/restart-life-api/internal/api/middleware"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/services"
+)
+
+// avatarFormField 头像上传的表单字段名
+const avatarFormField = "avatar"
+
+// ProfileHandler 用户资料处理器
+type ProfileHandler struct {
+	profileService *services.ProfileService
+}
+
+// NewProfileHandler 创建用户资料处理器
+func NewProfileHandler(profileService *services.ProfileService) *ProfileHandler {
+	return &ProfileHandler{
+		profileService: profileService,
+	}
+}
+
+// GetProfile 获取当前用户资料
+// @Summary 获取用户资料
+// @Description 获取当前登录用户的资料
+// @Tags profile
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.UserProfile
+// @Failure 401 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/profile [get]
+func (h *ProfileHandler) GetProfile(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	profile, err := h.profileService.GetProfile(c.Request.Context(), userID)
+	if err != nil {
+		h.handleProfileError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "", profile)
+}
+
+// UpdateProfile 更新当前用户资料
+// @Summary 更新用户资料
+// @Description 部分更新用户资料，未提供的字段保持不变，空字符串表示清空
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UpdateProfileRequest true "用户资料"
+// @Success 200 {object} models.UserProfile
+// @Failure 400 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/profile [patch]
+func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req models.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "请求数据格式错误", err.Error())
+		return
+	}
+
+	profile, err := h.profileService.UpdateProfile(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.handleProfileError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "资料已更新", profile)
+}
+
+// UploadAvatar 上传头像
+// @Summary 上传头像
+// @Description 支持 JPEG、PNG、GIF、WebP，图片会被居中裁剪并缩放为 256×256 的 JPEG
+// @Tags profile
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param avatar formData file true "头像图片，不超过 5MB"
+// @Success 200 {object} models.UserProfile
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 413 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/profile/avatar [post]
+func (h *ProfileHandler) UploadAvatar(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	// 为 multipart 边界等开销预留少量空间
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxAvatarUploadSize+64<<10)
+
+	header, err := c.FormFile(avatarFormField)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondError(c, http.StatusRequestEntityTooLarge, "FILE_TOO_LARGE", "头像文件不能超过5MB")
+			return
+		}
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "请上传头像文件", err.Error())
+		return
+	}
+	if header.Size > services.MaxAvatarUploadSize {
+		respondError(c, http.StatusRequestEntityTooLarge, "FILE_TOO_LARGE", "头像文件不能超过5MB")
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		h.handleProfileError(c, err)
+		return
+	}
+	defer file.Close()
+
+	profile, err := h.profileService.UpdateAvatar(c.Request.Context(), userID, file)
+	if err != nil {
+		h.handleProfileError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "头像已更新", profile)
+}
+
+// DeleteAvatar 删除头像
+// @Summary 删除头像
+// @Description 删除当前用户的头像
+// @Tags profile
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.UserProfile
+// @Router /api/v1/auth/profile/avatar [delete]
+func (h *ProfileHandler) DeleteAvatar(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	profile, err := h.profileService.DeleteAvatar(c.Request.Context(), userID)
+	if err != nil {
+		h.handleProfileError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "头像已删除", profile)
+}
+
+// handleProfileError 将用户资料业务错误映射为HTTP响应
+func (h *ProfileHandler) handleProfileError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		respondError(c, http.StatusNotFound, "USER_NOT_FOUND", "用户不存在")
+	case errors.Is(err, services.ErrInvalidBirthDate):
+		respondError(c, http.StatusBadRequest, "INVALID_BIRTH_DATE", "生日格式应为 YYYY-MM-DD，且在 1900-01-01 至今天之间")
+	case errors.Is(err, services.ErrInvalidGender):
+		respondError(c, http.StatusBadRequest, "INVALID_GENDER", "性别只能为 male、female 或 other")
+	case errors.Is(err, services.ErrInvalidTimezone):
+		respondError(c, http.StatusBadRequest, "INVALID_TIMEZONE", "时区无效，请使用 IANA 时区名称，如 Asia/Shanghai")
+	case errors.Is(err, services.ErrUnsupportedImage):
+		respondError(c, http.StatusBadRequest, "UNSUPPORTED_IMAGE", "仅支持 JPEG、PNG、GIF、WebP 格式的图片，且像素不能过大")
+	default:
+		logrus.WithError(err).WithField("request_id", c.GetString(middleware.RequestIDKey)).Error("Profile request failed")
+		respondError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "服务器内部错误，请稍后重试")
+	}
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuchengvcc/restart-life-api/internal/api/middleware"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+)
+
+// SuccessResponse 统一成功响应结构
+type SuccessResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// respondSuccess 返回成功响应
+func respondSuccess(c *gin.Context, statusCode int, message string, data interface{}) {
+	c.JSON(statusCode, SuccessResponse{
+		Success: true,
+		Message: message,
+		Data:    data,
+	})
+}
+
+// respondError 返回错误响应
+func respondError(c *gin.Context, statusCode int, code, message string) {
+	c.JSON(statusCode, middleware.ErrorResponse{
+		Success: false,
+		Code:    code,
+		Message: message,
+	})
+}
+
+// respondErrorWithDetails 返回带详情的错误响应
+func respondErrorWithDetails(c *gin.Context, statusCode int, code, message, details string) {
+	c.JSON(statusCode, middleware.ErrorResponse{
+		Success: false,
+		Code:    code,
+		Message: message,
+		Details: details,
+	})
+}
+
+// respondRetryAfter 返回需要稍后重试的错误响应，同时设置 Retry-After 响应头
+func respondRetryAfter(c *gin.Context, statusCode int, code, message string, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(statusCode, middleware.ErrorResponse{
+		Success:    false,
+		Code:       code,
+		Message:    message,
+		RetryAfter: seconds,
+	})
+}
+
+// respondLogin 返回登录结果，需要两步验证时返回登录挑战
+func respondLogin(c *gin.Context, resp *models.AuthResponse, challenge *models.TwoFactorChallenge) {
+	if challenge != nil {
+		respondSuccess(c, http.StatusOK, "请完成两步验证", challenge)
+		return
+	}
+	respondSuccess(c, http.StatusOK, "登录成功", resp)
+}
+
+// deviceInfoFromRequest 从请求头中提取设备信息
+func deviceInfoFromRequest(c *gin.Context) models.DeviceInfo {
+	platform := c.GetHeader("X-Platform")
+	if platform == "" {
+		platform = "web"
+	}
+
+	return models.DeviceInfo{
+		Platform:  platform,
+		Version:   c.GetHeader("X-Version"),
+		UserAgent: c.Request.UserAgent(),
+		IP:        c.ClientIP(),
+	}
+}
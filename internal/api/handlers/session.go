@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuchengvcc/restart-life-api/internal/api/middleware"
+)
+
+// ListSessions 获取登录会话列表
+// @Summary 获取登录会话列表
+// @Description 列出当前用户在各设备上的登录会话，current 标记发起请求的会话
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Session
+// @Router /api/v1/auth/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	claims, ok := middleware.GetClaims(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "UNAUTHORIZED", "请先登录")
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	sessions, err := h.authService.ListSessions(c.Request.Context(), userID, claims.SessionID)
+	if err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "", sessions)
+}
+
+// RevokeSession 吊销登录会话
+// @Summary 吊销登录会话
+// @Description 远程登出指定设备，该会话的刷新令牌和访问令牌立即失效
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param session_id path string true "会话ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/sessions/{session_id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "UNAUTHORIZED", "请先登录")
+		return
+	}
+
+	if err := h.authService.RevokeSession(c.Request.Context(), userID, c.Param("session_id")); err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "会话已吊销", nil)
+}
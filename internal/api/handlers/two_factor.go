@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuchengvcc/restart-life-api/internal/api/middleware"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+)
+
+// SetupTOTP 生成 TOTP 密钥
+// @Summary 生成两步验证密钥
+// @Description 生成新的 TOTP 密钥和 otpauth 地址，需提交验证码确认后才会启用；重复调用会替换未确认的密钥
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.TOTPSetupResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/2fa/totp/setup [post]
+func (h *AuthHandler) SetupTOTP(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "UNAUTHORIZED", "请先登录")
+		return
+	}
+
+	user, err := h.authService.GetUser(c.Request.Context(), userID)
+	if err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+
+	resp, err := h.twoFactorService.BeginSetup(c.Request.Context(), user)
+	if err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "", resp)
+}
+
+// ConfirmTOTP 确认启用两步验证
+// @Summary 确认启用两步验证
+// @Description 提交验证器 App 生成的验证码启用两步验证，返回的恢复码仅展示这一次
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.TwoFactorCodeRequest true "TOTP 验证码"
+// @Success 200 {object} models.RecoveryCodesResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/2fa/totp/confirm [post]
+func (h *AuthHandler) ConfirmTOTP(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "UNAUTHORIZED", "请先登录")
+		return
+	}
+
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "请求数据格式错误", err.Error())
+		return
+	}
+
+	codes, err := h.twoFactorService.Confirm(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "两步验证已启用", models.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTOTP 关闭两步验证
+// @Summary 关闭两步验证
+// @Description 提交 TOTP 验证码或恢复码关闭两步验证
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.TwoFactorCodeRequest true "验证码或恢复码"
+// @Success 200 {object} SuccessResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/2fa/totp/disable [post]
+func (h *AuthHandler) DisableTOTP(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "UNAUTHORIZED", "请先登录")
+		return
+	}
+
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "请求数据格式错误", err.Error())
+		return
+	}
+
+	if err := h.twoFactorService.Disable(c.Request.Context(), userID, req.Code); err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "两步验证已关闭", nil)
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+// @Summary 重新生成恢复码
+// @Description 提交 TOTP 验证码或恢复码后生成新的恢复码，旧恢复码全部作废
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.TwoFactorCodeRequest true "验证码或恢复码"
+// @Success 200 {object} models.RecoveryCodesResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Router /api/v1/auth/2fa/recovery-codes [post]
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "UNAUTHORIZED", "请先登录")
+		return
+	}
+
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorWithDetails(c, http.StatusBadRequest, "INVALID_REQUEST", "请求数据格式错误", err.Error())
+		return
+	}
+
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		h.handleAuthError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, "恢复码已重新生成", models.RecoveryCodesResponse{RecoveryCodes: codes})
+}
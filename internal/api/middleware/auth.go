@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/xuchengvcc/restart-life-api/internal/utils"
+)
+
+const (
+	// UserIDKey 在gin.Context中存储用户ID的键
+	UserIDKey = "user_id"
+	// UsernameKey 在gin.Context中存储用户名的键
+	UsernameKey = "username"
+	// PlatformKey 在gin.Context中存储令牌平台的键
+	PlatformKey = "platform"
+	// ClaimsKey 在gin.Context中存储完整令牌载荷的键
+	ClaimsKey = "auth_claims"
+	// AuthMethodKey 在gin.Context中存储认证方式的键
+	AuthMethodKey = "auth_method"
+)
+
+// 认证方式
+const (
+	AuthMethodBearer = "bearer"
+	AuthMethodAPIKey = "api_key"
+)
+
+// APIKeyHeader 携带 API 密钥的请求头
+const APIKeyHeader = "X-API-Key"
+
+// TokenValidator 访问令牌校验接口
+type TokenValidator interface {
+	ValidateAccessToken(ctx context.Context, token string) (*utils.Claims, error)
+}
+
+// APIKeyValidator API 密钥校验接口，校验的同时执行限流
+type APIKeyValidator interface {
+	ValidateAPIKey(ctx context.Context, key, clientIP string) (*utils.Claims, error)
+}
+
+// AuthConfig 认证中间件配置
+type AuthConfig struct {
+	Validator TokenValidator  // 令牌校验器
+	APIKeys   APIKeyValidator // API 密钥校验器，为空时不接受 API 密钥
+}
+
+// AuthMiddleware 认证中间件，校验 Bearer 令牌（或配置允许时的 API 密钥）并写入用户信息
+func AuthMiddleware(config AuthConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := extractBearerToken(c.GetHeader("Authorization"))
+		if token == "" && config.APIKeys != nil {
+			if apiKey := strings.TrimSpace(c.GetHeader(APIKeyHeader)); apiKey != "" {
+				authenticateAPIKey(c, config.APIKeys, apiKey)
+				return
+			}
+		}
+		if token == "" {
+			abortUnauthorized(c, "UNAUTHORIZED", "请先登录")
+			return
+		}
+
+		claims, err := config.Validator.ValidateAccessToken(c.Request.Context(), token)
+		if err != nil {
+			switch {
+			case errors.Is(err, utils.ErrTokenExpired):
+				abortUnauthorized(c, "TOKEN_EXPIRED", "访问令牌已过期")
+			case errors.Is(err, utils.ErrTokenRevoked):
+				abortUnauthorized(c, "TOKEN_REVOKED", "访问令牌已失效，请重新登录")
+			case errors.Is(err, utils.ErrTokenInvalid):
+				abortUnauthorized(c, "INVALID_TOKEN", "访问令牌无效")
+			default:
+				logrus.WithError(err).WithField("request_id", c.GetString(RequestIDKey)).Error("Token validation failed")
+				c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{
+					Success: false,
+					Code:    "INTERNAL_SERVER_ERROR",
+					Message: "服务器内部错误，请稍后重试",
+				})
+			}
+			return
+		}
+
+		setAuthContext(c, claims, AuthMethodBearer)
+		c.Next()
+	}
+}
+
+// authenticateAPIKey 校验 API 密钥并写入用户信息
+func authenticateAPIKey(c *gin.Context, validator APIKeyValidator, apiKey string) {
+	claims, err := validator.ValidateAPIKey(c.Request.Context(), apiKey, c.ClientIP())
+	if err != nil {
+		var rateLimitErr *utils.RateLimitError
+		switch {
+		case errors.As(err, &rateLimitErr):
+			retryAfter := int(math.Ceil(rateLimitErr.RetryAfter.Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, ErrorResponse{
+				Success:    false,
+				Code:       "RATE_LIMITED",
+				Message:    "请求过于频繁，请稍后再试",
+				RetryAfter: retryAfter,
+			})
+		case errors.Is(err, utils.ErrAPIKeyInvalid):
+			abortUnauthorized(c, "INVALID_API_KEY", "API 密钥无效、已过期或已吊销")
+		default:
+			logrus.WithError(err).WithField("request_id", c.GetString(RequestIDKey)).Error("API key validation failed")
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{
+				Success: false,
+				Code:    "INTERNAL_SERVER_ERROR",
+				Message: "服务器内部错误，请稍后重试",
+			})
+		}
+		return
+	}
+
+	setAuthContext(c, claims, AuthMethodAPIKey)
+	c.Next()
+}
+
+// GetUserID 从gin.Context中获取当前用户ID
+func GetUserID(c *gin.Context) (uint64, bool) {
+	userID, err := strconv.ParseUint(c.GetString(UserIDKey), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return userID, true
+}
+
+// GetClaims 从gin.Context中获取当前令牌载荷
+func GetClaims(c *gin.Context) (*utils.Claims, bool) {
+	if value, exists := c.Get(ClaimsKey); exists {
+		if claims, ok := value.(*utils.Claims); ok {
+			return claims, true
+		}
+	}
+	return nil, false
+}
+
+// setAuthContext 将令牌信息写入上下文
+func setAuthContext(c *gin.Context, claims *utils.Claims, method string) {
+	c.Set(UserIDKey, claims.UserID)
+	c.Set(UsernameKey, claims.Username)
+	c.Set(PlatformKey, claims.Platform)
+	c.Set(ClaimsKey, claims)
+	c.Set(AuthMethodKey, method)
+}
+
+// extractBearerToken 从 Authorization 头部提取 Bearer 令牌
+func extractBearerToken(header string) string {
+	const prefix = "Bearer "
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(header[len(prefix):])
+}
+
+// abortUnauthorized 返回401并终止请求
+func abortUnauthorized(c *gin.Context, code, message string) {
+	c.Header("WWW-Authenticate", `Bearer realm="restart-life-api"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{
+		Success: false,
+		Code:    code,
+		Message: message,
+	})
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// RequirePermission 权限校验中间件，需在 AuthMiddleware 之后使用
+// 当前令牌必须包含全部指定权限，否则返回 403
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := GetClaims(c)
+		if !ok {
+			abortUnauthorized(c, "UNAUTHORIZED", "请先登录")
+			return
+		}
+
+		for _, permission := range permissions {
+			if !claims.HasPermission(permission) {
+				logrus.WithFields(logrus.Fields{
+					"request_id": c.GetString(RequestIDKey),
+					"user_id":    claims.UserID,
+					"permission": permission,
+					"path":       c.FullPath(),
+				}).Warn("Permission denied")
+
+				c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{
+					Success: false,
+					Code:    "FORBIDDEN",
+					Message: "权限不足",
+				})
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// RequireAPIKeyScope API 密钥授权范围校验中间件，需在 AuthMiddleware 之后使用
+// 只限制通过 API 密钥认证的请求：读请求（GET/HEAD/OPTIONS）需要 readScope，其余需要 writeScope
+func RequireAPIKeyScope(readScope, writeScope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString(AuthMethodKey) != AuthMethodAPIKey {
+			c.Next()
+			return
+		}
+
+		scope := writeScope
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			scope = readScope
+		}
+
+		claims, ok := GetClaims(c)
+		if !ok || !claims.HasPermission(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{
+				Success: false,
+				Code:    "INSUFFICIENT_SCOPE",
+				Message: "API 密钥未授权该操作",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
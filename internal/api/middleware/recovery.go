@@ -25,10 +25,11 @@ func DefaultRecoveryConfig() RecoveryConfig {
 
 // ErrorResponse 错误响应结构
 type ErrorResponse struct {
-	Success bool   `json:"success"`
-	Code    string `json:"code"`
-	Message string `json:"message"`
-	Details string `json:"details,omitempty"`
+	Success    bool   `json:"success"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	Details    string `json:"details,omitempty"`
+	RetryAfter int    `json:"retry_after,omitempty"` // 需要等待的秒数，同时通过 Retry-After 响应头返回
 }
 
 // RecoveryMiddleware 异常恢复中间件
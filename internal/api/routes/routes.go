@@ -1,15 +1,30 @@
 package routes
 
 import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"github.com/xuchengvcc/restart-life-api/internal/api/handlers"
 	"github.com/xuchengvcc/restart-life-api/internal/api/middleware"
+	"github.com/xuchengvcc/restart-life-api/internal/catalog"
 	"github.com/xuchengvcc/restart-life-api/internal/config"
+	"github.com/xuchengvcc/restart-life-api/internal/database"
+	"github.com/xuchengvcc/restart-life-api/internal/mailer"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository/mysql"
+	redisrepo "github.com/xuchengvcc/restart-life-api/internal/repository/redis"
+	"github.com/xuchengvcc/restart-life-api/internal/services"
+	"github.com/xuchengvcc/restart-life-api/internal/services/providers"
+	"github.com/xuchengvcc/restart-life-api/internal/storage"
+	"github.com/xuchengvcc/restart-life-api/internal/utils"
 )
 
 // SetupRoutes 设置所有路由和中间件
-func SetupRoutes(cfg *config.Config) *gin.Engine {
+func SetupRoutes(cfg *config.Config, db *database.MySQLDB, redisDB *database.RedisDB, mail mailer.Mailer, blobs storage.BlobStore) *gin.Engine {
 	// 设置Gin模式
 	gin.SetMode(cfg.Server.Mode)
 
@@ -19,11 +34,23 @@ func SetupRoutes(cfg *config.Config) *gin.Engine {
 	// 注册全局中间件
 	setupMiddleware(r, cfg)
 
+	// 访问令牌签名
+	jwtManager, err := newJWTManager(cfg)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to initialize JWT signing keys")
+	}
+
 	// 注册健康检查路由
 	handlers.RegisterHealthRoutes(r, "v0.1.0")
+	handlers.RegisterJWKSRoutes(r, jwtManager)
+
+	// 本地存储的头像由本服务提供静态访问，导出文件等私有对象只能通过接口下载
+	if local, ok := blobs.(*storage.LocalStore); ok {
+		r.Static(local.URLPrefix()+"/avatars", filepath.Join(local.Dir(), "avatars"))
+	}
 
 	// 注册API路由
-	setupAPIRoutes(r, cfg)
+	setupAPIRoutes(r, cfg, db, redisDB, mail, blobs, jwtManager)
 
 	logrus.Info("All routes setup completed")
 	return r
@@ -54,34 +81,200 @@ func setupMiddleware(r *gin.Engine, cfg *config.Config) {
 }
 
 // setupAPIRoutes 设置API路由
-func setupAPIRoutes(r *gin.Engine, cfg *config.Config) {
+func setupAPIRoutes(
+	r *gin.Engine,
+	cfg *config.Config,
+	db *database.MySQLDB,
+	redisDB *database.RedisDB,
+	mail mailer.Mailer,
+	blobs storage.BlobStore,
+	jwtManager *utils.JWTManager,
+) {
+	// 数据访问层
+	userRepo := mysql.NewUserRepository(db)
+	identityRepo := mysql.NewIdentityRepository(db)
+	totpRepo := mysql.NewTOTPRepository(db)
+	roleRepo := mysql.NewRoleRepository(db)
+	apiKeyRepo := mysql.NewAPIKeyRepository(db)
+	characterRepo := mysql.NewCharacterRepository(db)
+	dataExportRepo := mysql.NewDataExportRepository(db)
+	refreshTokenRepo := redisrepo.NewRefreshTokenRepository(redisDB)
+	tokenRevocationRepo := redisrepo.NewTokenRevocationRepository(redisDB)
+	oauthStateRepo := redisrepo.NewOAuthStateRepository(redisDB)
+	sessionRepo := redisrepo.NewSessionRepository(redisDB)
+	actionTokenRepo := redisrepo.NewActionTokenRepository(redisDB)
+	twoFactorRepo := redisrepo.NewTwoFactorRepository(redisDB)
+	loginAttemptRepo := redisrepo.NewLoginAttemptRepository(redisDB)
+	userPurgeRepo := redisrepo.NewUserPurgeRepository(redisDB)
+	apiKeyRateRepo := redisrepo.NewAPIKeyRateRepository(redisDB)
+	characterDraftRepo := redisrepo.NewCharacterDraftRepository(redisDB)
+	skillTrainingRepo := redisrepo.NewSkillTrainingRepository(redisDB)
+
+	// 服务层
+	tokenService := services.NewTokenService(jwtManager, refreshTokenRepo, tokenRevocationRepo, sessionRepo, roleRepo, cfg.Auth.RefreshExpiry)
+	tokenService.SetSessionLimits(cfg.Auth.SessionLimits)
+	totpSecrets, err := utils.NewSecretBox(cfg.Auth.JWTSecret, "totp")
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to initialize TOTP secret encryption")
+	}
+	twoFactorService := services.NewTwoFactorService(totpRepo, twoFactorRepo, totpSecrets)
+	loginGuard := services.NewLoginGuard(loginAttemptRepo, services.LoginGuardConfig{
+		MaxAccountFailures: cfg.Auth.LoginGuard.MaxAccountFailures,
+		MaxIPFailures:      cfg.Auth.LoginGuard.MaxIPFailures,
+		FailureWindow:      cfg.Auth.LoginGuard.FailureWindow,
+		BaseLockout:        cfg.Auth.LoginGuard.BaseLockout,
+		MaxLockout:         cfg.Auth.LoginGuard.MaxLockout,
+	})
+	authService := services.NewAuthService(userRepo, identityRepo, oauthStateRepo, tokenService, twoFactorService, loginGuard)
+	if cfg.Auth.WeChat.AppID != "" {
+		authService.RegisterProvider(providers.NewWeChatProvider(providers.WeChatConfig{
+			AppID:           cfg.Auth.WeChat.AppID,
+			AppSecret:       cfg.Auth.WeChat.AppSecret,
+			Code2SessionURL: cfg.Auth.WeChat.Code2SessionURL,
+			Timeout:         cfg.Auth.WeChat.Timeout,
+		}))
+	}
+	for name, provider := range cfg.Auth.OAuthProviders {
+		authService.RegisterRedirectProvider(providers.NewOIDCProvider(name, providers.OIDCConfig{
+			ClientID:     provider.ClientID,
+			ClientSecret: provider.ClientSecret,
+			AuthURL:      provider.AuthURL,
+			TokenURL:     provider.TokenURL,
+			JWKSURL:      provider.JWKSURL,
+			Issuer:       provider.Issuer,
+			RedirectURL:  provider.RedirectURL,
+			Scopes:       provider.Scopes,
+			Timeout:      provider.Timeout,
+		}))
+	}
+
+	accountService := services.NewAccountService(userRepo, actionTokenRepo,
+		utils.NewActionTokenSigner(cfg.Auth.JWTSecret), mail, tokenService,
+		services.AccountConfig{
+			EmailVerifyURL:      cfg.Auth.EmailVerifyURL,
+			PasswordResetURL:    cfg.Auth.PasswordResetURL,
+			EmailVerifyExpiry:   cfg.Auth.EmailVerifyExpiry,
+			PasswordResetExpiry: cfg.Auth.PasswordResetExpiry,
+		})
+	adminService := services.NewAdminService(userRepo, roleRepo, characterRepo, tokenService)
+	profileService := services.NewProfileService(userRepo, blobs)
+	privacyService := services.NewPrivacyService(userRepo, dataExportRepo, userPurgeRepo, blobs,
+		tokenService, twoFactorService,
+		services.PrivacyConfig{
+			DeletionGracePeriod: cfg.Privacy.DeletionGracePeriod,
+			ExportExpiry:        cfg.Privacy.ExportExpiry,
+			ExportCooldown:      cfg.Privacy.ExportCooldown,
+			PurgeInterval:       cfg.Privacy.PurgeInterval,
+		})
+
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo, userRepo, roleRepo, apiKeyRateRepo, services.APIKeyConfig{
+		MaxKeysPerUser:   cfg.Auth.APIKeys.MaxPerUser,
+		DefaultRateLimit: cfg.Auth.APIKeys.DefaultRateLimit,
+		MaxRateLimit:     cfg.Auth.APIKeys.MaxRateLimit,
+	})
+
+	referenceCatalog := catalog.Default()
+	characterService := services.NewCharacterService(characterRepo, characterDraftRepo, skillTrainingRepo, referenceCatalog,
+		services.CharacterConfig{
+			DraftTTL:         cfg.Game.CharacterDraftTTL,
+			MaxRerolls:       cfg.Game.MaxRerolls,
+			ArchiveRetention: cfg.Game.CharacterArchiveRetention,
+			PurgeInterval:    cfg.Game.CharacterPurgeInterval,
+			TrainingCooldown: cfg.Game.SkillTrainingCooldown,
+		})
+
+	// 后台清理到期注销的账户和过期的导出文件
+	go privacyService.RunPurgeWorker(context.Background())
+	// 后台彻底删除超过保留期的归档角色
+	go characterService.RunPurgeWorker(context.Background())
+
+	// 处理器
+	authHandler := handlers.NewAuthHandler(authService, accountService, twoFactorService)
+	adminHandler := handlers.NewAdminHandler(adminService)
+	profileHandler := handlers.NewProfileHandler(profileService)
+	privacyHandler := handlers.NewPrivacyHandler(privacyService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	characterHandler := handlers.NewCharacterHandler(characterService)
+	catalogHandler := handlers.NewCatalogHandler(referenceCatalog)
+
+	// 认证中间件
+	// requireAuth 只接受登录令牌，用于账户与凭据管理；requireClient 还接受 API 密钥，用于业务接口
+	requireAuth := middleware.AuthMiddleware(middleware.AuthConfig{
+		Validator: tokenService,
+	})
+	requireClient := middleware.AuthMiddleware(middleware.AuthConfig{
+		Validator: tokenService,
+		APIKeys:   apiKeyService,
+	})
+	requireGameScope := middleware.RequireAPIKeyScope(models.ScopeGameRead, models.ScopeGameWrite)
+
 	// API v1 路由组
 	v1 := r.Group("/api/v1")
 	{
 		// 认证相关路由
 		auth := v1.Group("/auth")
 		{
-			// TODO: 添加认证路由
-			auth.POST("/register", placeholderHandler("register"))
-			auth.POST("/login", placeholderHandler("login"))
-			auth.POST("/logout", placeholderHandler("logout"))
-			auth.POST("/refresh", placeholderHandler("refresh"))
-			auth.GET("/profile", placeholderHandler("profile"))
+			auth.POST("/register", authHandler.Register)
+			auth.POST("/login", authHandler.Login)
+			auth.POST("/login/2fa", authHandler.CompleteTwoFactorLogin)
+			auth.POST("/guest", authHandler.GuestLogin)
+			auth.POST("/wechat/login", authHandler.WeChatLogin)
+			auth.GET("/oauth/:provider/authorize", authHandler.OAuthAuthorize)
+			auth.GET("/oauth/:provider/callback", authHandler.OAuthCallback)
+			auth.POST("/oauth/:provider/callback", authHandler.OAuthCallback)
+			auth.POST("/guest/upgrade", requireAuth, authHandler.UpgradeGuest)
+			auth.POST("/logout", requireAuth, authHandler.Logout)
+			auth.POST("/logout/all", requireAuth, authHandler.LogoutAll)
+			auth.GET("/sessions", requireAuth, authHandler.ListSessions)
+			auth.DELETE("/sessions/:session_id", requireAuth, authHandler.RevokeSession)
+			auth.POST("/email/verification", requireAuth, authHandler.SendVerificationEmail)
+			auth.POST("/email/verify", authHandler.VerifyEmail)
+			auth.POST("/password/forgot", authHandler.ForgotPassword)
+			auth.POST("/password/reset", authHandler.ResetPassword)
+			auth.POST("/2fa/totp/setup", requireAuth, authHandler.SetupTOTP)
+			auth.POST("/2fa/totp/confirm", requireAuth, authHandler.ConfirmTOTP)
+			auth.POST("/2fa/totp/disable", requireAuth, authHandler.DisableTOTP)
+			auth.POST("/2fa/recovery-codes", requireAuth, authHandler.RegenerateRecoveryCodes)
+			auth.POST("/refresh", authHandler.Refresh)
+			auth.GET("/profile", requireAuth, profileHandler.GetProfile)
+			auth.PATCH("/profile", requireAuth, profileHandler.UpdateProfile)
+			auth.POST("/profile/avatar", requireAuth, profileHandler.UploadAvatar)
+			auth.DELETE("/profile/avatar", requireAuth, profileHandler.DeleteAvatar)
+			auth.POST("/account/export", requireAuth, privacyHandler.RequestExport)
+			auth.GET("/account/export/:export_id", requireAuth, privacyHandler.GetExport)
+			auth.GET("/account/export/:export_id/download", requireAuth, privacyHandler.DownloadExport)
+			auth.POST("/account/delete", requireAuth, privacyHandler.DeleteAccount)
+			auth.POST("/account/delete/cancel", requireAuth, privacyHandler.CancelDeletion)
+			auth.POST("/api-keys", requireAuth, apiKeyHandler.CreateAPIKey)
+			auth.GET("/api-keys", requireAuth, apiKeyHandler.ListAPIKeys)
+			auth.DELETE("/api-keys/:key_id", requireAuth, apiKeyHandler.RevokeAPIKey)
+		}
+
+		// 参考数据路由，无需登录
+		catalogGroup := v1.Group("/catalog")
+		{
+			catalogGroup.GET("/countries", catalogHandler.ListCountries)
+			catalogGroup.GET("/eras", catalogHandler.ListEras)
 		}
 
 		// 角色相关路由
-		characters := v1.Group("/characters")
+		characters := v1.Group("/characters", requireClient, requireGameScope)
 		{
-			// TODO: 添加角色路由
-			characters.POST("", placeholderHandler("create character"))
-			characters.GET("", placeholderHandler("list characters"))
-			characters.GET("/:id", placeholderHandler("get character"))
-			characters.PUT("/:id", placeholderHandler("update character"))
-			characters.DELETE("/:id", placeholderHandler("delete character"))
+			characters.GET("", characterHandler.ListCharacters)
+			characters.POST("/drafts", characterHandler.CreateDraft)
+			characters.GET("/drafts/:draft_id", characterHandler.GetDraft)
+			characters.POST("/drafts/:draft_id/reroll", characterHandler.RerollDraft)
+			characters.POST("/drafts/:draft_id/confirm", characterHandler.ConfirmDraft)
+			characters.DELETE("/drafts/:draft_id", characterHandler.DeleteDraft)
+			characters.GET("/:id", characterHandler.GetCharacter)
+			characters.PUT("/:id", characterHandler.UpdateCharacter)
+			characters.DELETE("/:id", characterHandler.DeleteCharacter)
+			characters.POST("/:id/restore", characterHandler.RestoreCharacter)
+			characters.POST("/:id/skills/:skill/train", characterHandler.TrainSkill)
 		}
 
 		// 游戏相关路由
-		game := v1.Group("/game")
+		game := v1.Group("/game", requireClient, requireGameScope)
 		{
 			// TODO: 添加游戏路由
 			game.POST("/start/:character_id", placeholderHandler("start game"))
@@ -91,7 +284,7 @@ func setupAPIRoutes(r *gin.Engine, cfg *config.Config) {
 		}
 
 		// 成就相关路由
-		achievements := v1.Group("/achievements")
+		achievements := v1.Group("/achievements", requireClient, requireGameScope)
 		{
 			// TODO: 添加成就路由
 			achievements.GET("/:character_id", placeholderHandler("get achievements"))
@@ -99,7 +292,7 @@ func setupAPIRoutes(r *gin.Engine, cfg *config.Config) {
 		}
 
 		// 关系相关路由
-		relationships := v1.Group("/relationships")
+		relationships := v1.Group("/relationships", requireClient, requireGameScope)
 		{
 			// TODO: 添加关系路由
 			relationships.GET("/:character_id", placeholderHandler("get relationships"))
@@ -107,17 +300,59 @@ func setupAPIRoutes(r *gin.Engine, cfg *config.Config) {
 		}
 
 		// 统计相关路由
-		stats := v1.Group("/stats")
+		stats := v1.Group("/stats", requireClient, requireGameScope)
 		{
 			// TODO: 添加统计路由
 			stats.GET("/:character_id", placeholderHandler("get character stats"))
 			stats.GET("/:character_id/timeline", placeholderHandler("get timeline"))
 		}
+
+		// 管理后台路由，需具备后台访问权限，各接口再按需校验细分权限
+		admin := v1.Group("/admin", requireClient, middleware.RequirePermission(models.PermissionAdminAccess))
+		{
+			admin.GET("/users/:user_id", middleware.RequirePermission(models.PermissionUsersRead), adminHandler.GetUser)
+			admin.PUT("/users/:user_id/status", middleware.RequirePermission(models.PermissionUsersWrite), adminHandler.UpdateUserStatus)
+			admin.POST("/users/:user_id/roles", middleware.RequirePermission(models.PermissionRolesWrite), adminHandler.AssignRole)
+			admin.DELETE("/users/:user_id/roles/:role", middleware.RequirePermission(models.PermissionRolesWrite), adminHandler.RevokeRole)
+			admin.GET("/roles", middleware.RequirePermission(models.PermissionRolesRead), adminHandler.ListRoles)
+			admin.DELETE("/characters/:id", middleware.RequirePermission(models.PermissionCharactersDelete), adminHandler.DeleteCharacter)
+
+			// TODO: 添加内容管理路由
+			admin.GET("/content", middleware.RequirePermission(models.PermissionContentRead), placeholderHandler("list content"))
+			admin.PUT("/content/:id", middleware.RequirePermission(models.PermissionContentWrite), placeholderHandler("update content"))
+		}
 	}
 
 	logrus.Info("API routes setup completed")
 }
 
+// newJWTManager 根据配置创建访问令牌管理器，未配置签名密钥集时回退为 HS256
+func newJWTManager(cfg *config.Config) (*utils.JWTManager, error) {
+	if len(cfg.Auth.SigningKeys) == 0 {
+		logrus.Warn("No JWT signing keys configured, falling back to HS256 shared secret")
+		return utils.NewJWTManager(cfg.Auth.JWTSecret, cfg.Auth.JWTExpiry), nil
+	}
+
+	keys := make([]*utils.SigningKey, 0, len(cfg.Auth.SigningKeys))
+	for _, keyCfg := range cfg.Auth.SigningKeys {
+		var retiredAt time.Time
+		if keyCfg.RetiredAt != "" {
+			t, err := time.Parse(time.RFC3339, keyCfg.RetiredAt)
+			if err != nil {
+				return nil, fmt.Errorf("signing key %s: invalid retired_at: %w", keyCfg.KeyID, err)
+			}
+			retiredAt = t
+		}
+
+		key, err := utils.LoadSigningKey(keyCfg.KeyID, keyCfg.Algorithm, keyCfg.PrivateKeyFile, keyCfg.PublicKeyFile, retiredAt)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return utils.NewJWTManagerWithKeys(keys, cfg.Auth.ActiveKeyID, cfg.Auth.JWTExpiry)
+}
+
 // placeholderHandler 占位符处理器，用于未实现的路由
 func placeholderHandler(action string) gin.HandlerFunc {
 	return func(c *gin.Context) {
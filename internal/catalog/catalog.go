@@ -0,0 +1,242 @@
+// Package catalog 提供国家/地区与时代参考数据
+// 数据随程序嵌入并带有版本号，用于校验角色的出生国家与年份
+package catalog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+//go:embed data/catalog.json
+var catalogData []byte
+
+// 出生条件校验错误
+var (
+	// ErrUnknownCountry 国家代码未收录
+	ErrUnknownCountry = errors.New("unknown country")
+	// ErrYearOutOfRange 年份超出游戏支持的范围
+	ErrYearOutOfRange = errors.New("year out of range")
+	// ErrCountryNotExisted 国家在该年份尚未成立或已经解体
+	ErrCountryNotExisted = errors.New("country did not exist in that year")
+)
+
+// LocalizedNames 多语言名称，键为语言代码（en、zh）
+type LocalizedNames map[string]string
+
+// Region 地区
+type Region struct {
+	Code  string         `json:"code"`
+	Names LocalizedNames `json:"names"`
+}
+
+// Country 国家或地区
+// From/To 为存在年份范围（含端点），为空表示在游戏年份范围内一直存在
+// SucceededBy 为解体后取而代之的国家，同一地区在任一年份只能对应其中一方；分离独立而原国家继续存在的情况不在此列
+// SeparatedFrom 为成立前领土所属、此后继续存在的国家（殖民宗主国、分离前的母国），成立前在该地出生时可选择这些国家
+// MergedInto 为并入的国家，解体后在该地出生时可选择该国家
+type Country struct {
+	Code          string         `json:"code"` // ISO 3166-1 alpha-2，已解体国家使用 ISO 3166-3 保留代码，无对应代码的历史国家和北极使用 X 开头的自定义代码
+	Names         LocalizedNames `json:"names"`
+	Region        string         `json:"region"`
+	From          *int           `json:"from,omitempty"`
+	SeparatedFrom []string       `json:"separated_from,omitempty"`
+	To            *int           `json:"to,omitempty"`
+	SucceededBy   []string       `json:"succeeded_by,omitempty"`
+	MergedInto    string         `json:"merged_into,omitempty"`
+}
+
+// ExistsIn 国家在指定年份是否存在
+func (c *Country) ExistsIn(year int) bool {
+	if c.From != nil && year < *c.From {
+		return false
+	}
+	if c.To != nil && year > *c.To {
+		return false
+	}
+	return true
+}
+
+// Era 时代
+type Era struct {
+	Code      string         `json:"code"`
+	Names     LocalizedNames `json:"names"`
+	StartYear int            `json:"start_year"`
+	EndYear   int            `json:"end_year"`
+}
+
+// Catalog 参考数据集
+type Catalog struct {
+	Version   string     `json:"version"`
+	MinYear   int        `json:"min_year"`
+	MaxYear   int        `json:"max_year"`
+	Regions   []Region   `json:"regions"`
+	Countries []*Country `json:"countries"`
+	Eras      []Era      `json:"eras"`
+
+	byCode map[string]*Country
+}
+
+var (
+	defaultCatalog *Catalog
+	defaultOnce    sync.Once
+)
+
+// Default 返回嵌入的参考数据集，数据有误属于构建问题，直接 panic
+func Default() *Catalog {
+	defaultOnce.Do(func() {
+		c, err := Parse(catalogData)
+		if err != nil {
+			panic(fmt.Sprintf("catalog: invalid embedded data: %v", err))
+		}
+		defaultCatalog = c
+	})
+	return defaultCatalog
+}
+
+// Parse 解析并校验参考数据
+func Parse(data []byte) (*Catalog, error) {
+	var c Catalog
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	if c.Version == "" {
+		return nil, errors.New("missing version")
+	}
+	if c.MinYear >= c.MaxYear {
+		return nil, fmt.Errorf("invalid year range %d-%d", c.MinYear, c.MaxYear)
+	}
+
+	regions := make(map[string]bool, len(c.Regions))
+	for _, r := range c.Regions {
+		regions[r.Code] = true
+	}
+
+	c.byCode = make(map[string]*Country, len(c.Countries))
+	for _, country := range c.Countries {
+		if _, dup := c.byCode[country.Code]; dup {
+			return nil, fmt.Errorf("duplicate country %s", country.Code)
+		}
+		if !regions[country.Region] {
+			return nil, fmt.Errorf("country %s has unknown region %q", country.Code, country.Region)
+		}
+		if country.From != nil && country.To != nil && *country.From > *country.To {
+			return nil, fmt.Errorf("country %s has invalid existence range", country.Code)
+		}
+		c.byCode[country.Code] = country
+	}
+
+	// 前后继国家的存在年份不能重叠，否则同一出生地在同一年份会有两个可选国家
+	for _, country := range c.Countries {
+		if len(country.SucceededBy) > 0 && country.To == nil {
+			return nil, fmt.Errorf("country %s has successors but no end year", country.Code)
+		}
+		for _, code := range country.SucceededBy {
+			successor, ok := c.byCode[code]
+			if !ok {
+				return nil, fmt.Errorf("country %s has unknown successor %s", country.Code, code)
+			}
+			if successor.From == nil || *successor.From <= *country.To {
+				return nil, fmt.Errorf("country %s overlaps its predecessor %s", code, country.Code)
+			}
+		}
+
+		// 成立前一年和解体后一年，该地仍需有可选的国家
+		for _, code := range country.SeparatedFrom {
+			parent, ok := c.byCode[code]
+			if !ok {
+				return nil, fmt.Errorf("country %s separated from unknown country %s", country.Code, code)
+			}
+			if country.From == nil || !parent.ExistsIn(*country.From-1) {
+				return nil, fmt.Errorf("country %s separated from %s, which did not exist before it", country.Code, code)
+			}
+		}
+		if country.MergedInto != "" {
+			target, ok := c.byCode[country.MergedInto]
+			if !ok {
+				return nil, fmt.Errorf("country %s merged into unknown country %s", country.Code, country.MergedInto)
+			}
+			if country.To == nil || !target.ExistsIn(*country.To+1) {
+				return nil, fmt.Errorf("country %s merged into %s, which did not exist after it", country.Code, country.MergedInto)
+			}
+		}
+	}
+
+	// 时代需按年份首尾相接，覆盖整个年份范围
+	next := c.MinYear
+	for _, era := range c.Eras {
+		if era.StartYear != next || era.EndYear < era.StartYear {
+			return nil, fmt.Errorf("era %s does not continue from %d", era.Code, next)
+		}
+		next = era.EndYear + 1
+	}
+	if next != c.MaxYear+1 {
+		return nil, fmt.Errorf("eras do not cover %d-%d", c.MinYear, c.MaxYear)
+	}
+
+	return &c, nil
+}
+
+// Country 按代码查找国家，代码不区分大小写
+func (c *Catalog) Country(code string) (*Country, bool) {
+	country, ok := c.byCode[strings.ToUpper(strings.TrimSpace(code))]
+	return country, ok
+}
+
+// CountriesIn 筛选国家，region 为空表示不限地区，year 为 0 表示不限年份
+func (c *Catalog) CountriesIn(region string, year int) []*Country {
+	countries := make([]*Country, 0, len(c.Countries))
+	for _, country := range c.Countries {
+		if region != "" && country.Region != region {
+			continue
+		}
+		if year != 0 && !country.ExistsIn(year) {
+			continue
+		}
+		countries = append(countries, country)
+	}
+	return countries
+}
+
+// HasRegion 地区代码是否收录
+func (c *Catalog) HasRegion(code string) bool {
+	for _, r := range c.Regions {
+		if r.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// InRange 年份是否在游戏支持的范围内
+func (c *Catalog) InRange(year int) bool {
+	return year >= c.MinYear && year <= c.MaxYear
+}
+
+// EraOf 返回年份所属时代
+func (c *Catalog) EraOf(year int) (*Era, bool) {
+	for i := range c.Eras {
+		if year >= c.Eras[i].StartYear && year <= c.Eras[i].EndYear {
+			return &c.Eras[i], true
+		}
+	}
+	return nil, false
+}
+
+// ValidateBirth 校验出生国家与年份，返回规范化后的国家代码
+func (c *Catalog) ValidateBirth(code string, year int) (string, error) {
+	country, ok := c.Country(code)
+	if !ok {
+		return "", ErrUnknownCountry
+	}
+	if !c.InRange(year) {
+		return "", ErrYearOutOfRange
+	}
+	if !country.ExistsIn(year) {
+		return "", ErrCountryNotExisted
+	}
+	return country.Code, nil
+}
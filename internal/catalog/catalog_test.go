@@ -0,0 +1,142 @@
+package catalog
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDefaultSuccession(t *testing.T) {
+	c := Default()
+
+	tests := []struct {
+		code string
+		year int
+		want error
+	}{
+		{"XR", 1900, nil},
+		{"RU", 1900, ErrCountryNotExisted},
+		{"SU", 1950, nil},
+		{"RU", 1950, ErrCountryNotExisted},
+		{"UA", 1991, ErrCountryNotExisted},
+		{"SU", 1992, ErrCountryNotExisted},
+		{"RU", 1992, nil},
+		{"XS", 1850, nil},
+		{"RS", 1950, ErrCountryNotExisted},
+		{"YU", 2006, ErrCountryNotExisted},
+		{"RS", 2006, nil},
+		{"XI", 1900, nil},
+		{"IN", 1900, ErrCountryNotExisted},
+		{"XI", 1947, ErrCountryNotExisted},
+		{"XJ", 1900, nil},
+		{"XC", 1930, nil},
+		{"KR", 1930, ErrCountryNotExisted},
+		{"XG", 1850, nil},
+		{"DE", 1850, ErrCountryNotExisted},
+		{"XP", 1871, ErrCountryNotExisted},
+	}
+	for _, tt := range tests {
+		if _, err := c.ValidateBirth(tt.code, tt.year); !errors.Is(err, tt.want) {
+			t.Errorf("ValidateBirth(%s, %d) error = %v, want %v", tt.code, tt.year, err, tt.want)
+		}
+	}
+}
+
+func TestParseRejectsOverlappingSuccessor(t *testing.T) {
+	data := `{
+		"version": "test",
+		"min_year": 1800,
+		"max_year": 2050,
+		"regions": [{"code": "europe", "names": {"en": "Europe"}}],
+		"countries": [
+			{"code": "SU", "names": {"en": "Soviet Union"}, "region": "europe", "from": 1922, "to": 1991, "succeeded_by": ["RU"]},
+			{"code": "RU", "names": {"en": "Russia"}, "region": "europe"}
+		],
+		"eras": [{"code": "all", "names": {"en": "All"}, "start_year": 1800, "end_year": 2050}]
+	}`
+
+	if _, err := Parse([]byte(data)); err == nil || !strings.Contains(err.Error(), "overlaps its predecessor") {
+		t.Fatalf("Parse() error = %v, want overlap error", err)
+	}
+
+	fixed := strings.Replace(data, `"region": "europe"}`, `"region": "europe", "from": 1992}`, 1)
+	if _, err := Parse([]byte(fixed)); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+}
+
+// 南极洲 1978 年前无人出生，成立前不需要可选的国家
+var unpopulatedBefore = map[string]bool{"AQ": true}
+
+// selectable 在指定年份，该国领土是否有可选的国家：国家本身，或沿前身、后继找到的国家
+func selectable(c *Catalog, country *Country, year int) bool {
+	if country.ExistsIn(year) {
+		return true
+	}
+	var next []string
+	if country.From != nil && year < *country.From {
+		next = append(next, country.SeparatedFrom...)
+		for _, other := range c.Countries {
+			if slices.Contains(other.SucceededBy, country.Code) {
+				next = append(next, other.Code)
+			}
+		}
+	} else {
+		next = append(next, country.SucceededBy...)
+		if country.MergedInto != "" {
+			next = append(next, country.MergedInto)
+		}
+	}
+	for _, code := range next {
+		if other, ok := c.Country(code); ok && selectable(c, other, year) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDefaultCoversEveryTerritoryInEveryEra(t *testing.T) {
+	c := Default()
+
+	for _, era := range c.Eras {
+		for year := era.StartYear; year <= era.EndYear; year++ {
+			for _, region := range c.Regions {
+				if len(c.CountriesIn(region.Code, year)) == 0 {
+					t.Errorf("region %s has no country in %d (%s)", region.Code, year, era.Code)
+				}
+			}
+			for _, country := range c.Countries {
+				if unpopulatedBefore[country.Code] && year < *country.From {
+					continue
+				}
+				if !selectable(c, country, year) {
+					t.Errorf("territory of %s has no selectable country in %d (%s)", country.Code, year, era.Code)
+				}
+			}
+		}
+	}
+}
+
+func TestParseRejectsMissingPredecessor(t *testing.T) {
+	data := `{
+		"version": "test",
+		"min_year": 1800,
+		"max_year": 2050,
+		"regions": [{"code": "asia", "names": {"en": "Asia"}}],
+		"countries": [
+			{"code": "GB", "names": {"en": "United Kingdom"}, "region": "asia", "from": 1950},
+			{"code": "IN", "names": {"en": "India"}, "region": "asia", "from": 1947, "separated_from": ["GB"]}
+		],
+		"eras": [{"code": "all", "names": {"en": "All"}, "start_year": 1800, "end_year": 2050}]
+	}`
+
+	if _, err := Parse([]byte(data)); err == nil || !strings.Contains(err.Error(), "did not exist before it") {
+		t.Fatalf("Parse() error = %v, want missing predecessor error", err)
+	}
+
+	fixed := strings.Replace(data, `"region": "asia", "from": 1950}`, `"region": "asia"}`, 1)
+	if _, err := Parse([]byte(fixed)); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+}
@@ -13,6 +13,10 @@ type Config struct {
 	Database DatabaseConfig `mapstructure:"database"`
 	Redis    RedisConfig    `mapstructure:"redis"`
 	Auth     AuthConfig     `mapstructure:"auth"`
+	Mail     MailConfig     `mapstructure:"mail"`
+	Storage  StorageConfig  `mapstructure:"storage"`
+	Privacy  PrivacyConfig  `mapstructure:"privacy"`
+	Game     GameConfig     `mapstructure:"game"`
 	CORS     CORSConfig     `mapstructure:"cors"`
 	Logging  LoggingConfig  `mapstructure:"logging"`
 }
@@ -57,9 +61,128 @@ type RedisConfig struct {
 
 // AuthConfig 认证配置
 type AuthConfig struct {
-	JWTSecret     string        `mapstructure:"jwt_secret"`
-	JWTExpiry     time.Duration `mapstructure:"jwt_expiry"`
-	RefreshExpiry time.Duration `mapstructure:"refresh_expiry"`
+	JWTSecret      string                         `mapstructure:"jwt_secret"`
+	JWTExpiry      time.Duration                  `mapstructure:"jwt_expiry"`
+	SigningKeys    []SigningKeyConfig             `mapstructure:"signing_keys"`  // 非对称签名密钥集，为空时访问令牌使用 jwt_secret 以 HS256 签名
+	ActiveKeyID    string                         `mapstructure:"active_key_id"` // 当前签名密钥，为空时使用第一个可签名的密钥
+	RefreshExpiry  time.Duration                  `mapstructure:"refresh_expiry"`
+	WeChat         WeChatConfig                   `mapstructure:"wechat"`
+	OAuthProviders map[string]OAuthProviderConfig `mapstructure:"oauth_providers"` // 键为提供方名称，如 google、apple
+	SessionLimits  map[string]int                 `mapstructure:"session_limits"`  // 各平台同时在线会话上限，未配置的平台不限制
+	LoginGuard     LoginGuardConfig               `mapstructure:"login_guard"`
+	APIKeys        APIKeysConfig                  `mapstructure:"api_keys"`
+
+	EmailVerifyURL      string        `mapstructure:"email_verify_url"`   // 验证邮件中的链接地址，令牌以 token 参数附加
+	PasswordResetURL    string        `mapstructure:"password_reset_url"` // 重置密码邮件中的链接地址，令牌以 token 参数附加
+	EmailVerifyExpiry   time.Duration `mapstructure:"email_verify_expiry"`
+	PasswordResetExpiry time.Duration `mapstructure:"password_reset_expiry"`
+}
+
+// SigningKeyConfig 访问令牌签名密钥配置
+type SigningKeyConfig struct {
+	KeyID          string `mapstructure:"kid"`
+	Algorithm      string `mapstructure:"algorithm"`        // RS256 | EdDSA
+	PrivateKeyFile string `mapstructure:"private_key_file"` // PEM 格式私钥，PKCS#8 或 PKCS#1
+	PublicKeyFile  string `mapstructure:"public_key_file"`  // PEM 格式公钥，只提供公钥的密钥仅用于校验
+	RetiredAt      string `mapstructure:"retired_at"`       // RFC3339 时间，之后不再签名，旧令牌过期后不再被接受
+}
+
+// LoginGuardConfig 登录防暴力破解配置
+type LoginGuardConfig struct {
+	MaxAccountFailures int           `mapstructure:"max_account_failures"` // 单个账户连续失败次数阈值
+	MaxIPFailures      int           `mapstructure:"max_ip_failures"`      // 单个IP连续失败次数阈值
+	FailureWindow      time.Duration `mapstructure:"failure_window"`       // 失败计数保留时间
+	BaseLockout        time.Duration `mapstructure:"base_lockout"`         // 首次锁定时长，之后每次失败翻倍
+	MaxLockout         time.Duration `mapstructure:"max_lockout"`          // 锁定时长上限
+}
+
+// APIKeysConfig 个人 API 密钥配置
+type APIKeysConfig struct {
+	MaxPerUser       int `mapstructure:"max_per_user"`       // 每个用户可同时持有的有效密钥数
+	DefaultRateLimit int `mapstructure:"default_rate_limit"` // 未指定时每个密钥每分钟请求上限
+	MaxRateLimit     int `mapstructure:"max_rate_limit"`     // 创建密钥时可指定的每分钟请求上限
+}
+
+// WeChatConfig 微信小程序登录配置
+type WeChatConfig struct {
+	AppID           string        `mapstructure:"app_id"`
+	AppSecret       string        `mapstructure:"app_secret"`
+	Code2SessionURL string        `mapstructure:"code2session_url"`
+	Timeout         time.Duration `mapstructure:"timeout"`
+}
+
+// OAuthProviderConfig OpenID Connect 提供方配置
+type OAuthProviderConfig struct {
+	ClientID     string        `mapstructure:"client_id"`
+	ClientSecret string        `mapstructure:"client_secret"`
+	AuthURL      string        `mapstructure:"auth_url"`
+	TokenURL     string        `mapstructure:"token_url"`
+	JWKSURL      string        `mapstructure:"jwks_url"`
+	Issuer       string        `mapstructure:"issuer"`
+	RedirectURL  string        `mapstructure:"redirect_url"`
+	Scopes       []string      `mapstructure:"scopes"`
+	Timeout      time.Duration `mapstructure:"timeout"`
+}
+
+// MailConfig 邮件配置
+type MailConfig struct {
+	Driver   string     `mapstructure:"driver"` // smtp | log | file
+	From     string     `mapstructure:"from"`
+	FromName string     `mapstructure:"from_name"`
+	SMTP     SMTPConfig `mapstructure:"smtp"`
+	FileDir  string     `mapstructure:"file_dir"` // file 方式的输出目录
+}
+
+// SMTPConfig SMTP 服务器配置
+type SMTPConfig struct {
+	Host       string        `mapstructure:"host"`
+	Port       int           `mapstructure:"port"`
+	Username   string        `mapstructure:"username"`
+	Password   string        `mapstructure:"password"`
+	Encryption string        `mapstructure:"encryption"` // none | starttls | tls
+	Timeout    time.Duration `mapstructure:"timeout"`
+}
+
+// StorageConfig 对象存储配置（用户头像等上传文件）
+type StorageConfig struct {
+	Driver string             `mapstructure:"driver"` // local | s3
+	Local  LocalStorageConfig `mapstructure:"local"`
+	S3     S3StorageConfig    `mapstructure:"s3"`
+}
+
+// LocalStorageConfig 本地文件系统存储配置
+type LocalStorageConfig struct {
+	Dir       string `mapstructure:"dir"`
+	URLPrefix string `mapstructure:"url_prefix"` // 以 / 开头的路径，由本服务提供静态访问
+}
+
+// PrivacyConfig 个人数据导出与账户注销配置
+type PrivacyConfig struct {
+	DeletionGracePeriod time.Duration `mapstructure:"deletion_grace_period"` // 申请注销后的冷静期，期满后删除账户及全部数据
+	ExportExpiry        time.Duration `mapstructure:"export_expiry"`         // 导出文件保留时间
+	ExportCooldown      time.Duration `mapstructure:"export_cooldown"`       // 两次导出的最小间隔
+	PurgeInterval       time.Duration `mapstructure:"purge_interval"`        // 清理任务执行间隔
+}
+
+// GameConfig 游戏配置
+type GameConfig struct {
+	CharacterDraftTTL         time.Duration `mapstructure:"character_draft_ttl"`         // 角色草稿保留时间，过期未确认即丢弃
+	MaxRerolls                int           `mapstructure:"max_rerolls"`                 // 每个角色草稿可重新生成的次数
+	CharacterArchiveRetention time.Duration `mapstructure:"character_archive_retention"` // 删除的角色保留多久后彻底删除，期间可以恢复
+	CharacterPurgeInterval    time.Duration `mapstructure:"character_purge_interval"`    // 清理归档角色的间隔
+	SkillTrainingCooldown     time.Duration `mapstructure:"skill_training_cooldown"`     // 同一角色两次技能训练的最短间隔
+}
+
+// S3StorageConfig S3 兼容存储配置
+type S3StorageConfig struct {
+	Endpoint        string        `mapstructure:"endpoint"`
+	Region          string        `mapstructure:"region"`
+	Bucket          string        `mapstructure:"bucket"`
+	AccessKeyID     string        `mapstructure:"access_key_id"`
+	SecretAccessKey string        `mapstructure:"secret_access_key"`
+	UsePathStyle    bool          `mapstructure:"use_path_style"`  // MinIO 等自建服务通常需要开启
+	PublicBaseURL   string        `mapstructure:"public_base_url"` // 对象公开访问地址前缀（如 CDN）
+	Timeout         time.Duration `mapstructure:"timeout"`
 }
 
 // CORSConfig CORS配置
@@ -144,6 +267,52 @@ func setDefaults() {
 	viper.SetDefault("auth.jwt_secret", "your-dev-jwt-secret-key")
 	viper.SetDefault("auth.jwt_expiry", "24h")
 	viper.SetDefault("auth.refresh_expiry", "168h")
+	viper.SetDefault("auth.active_key_id", "")
+	viper.SetDefault("auth.wechat.app_id", "")
+	viper.SetDefault("auth.wechat.app_secret", "")
+	viper.SetDefault("auth.wechat.code2session_url", "https://api.weixin.qq.com/sns/jscode2session")
+	viper.SetDefault("auth.wechat.timeout", "5s")
+	viper.SetDefault("auth.login_guard.max_account_failures", 5)
+	viper.SetDefault("auth.login_guard.max_ip_failures", 20)
+	viper.SetDefault("auth.login_guard.failure_window", "1h")
+	viper.SetDefault("auth.login_guard.base_lockout", "1m")
+	viper.SetDefault("auth.login_guard.max_lockout", "1h")
+	viper.SetDefault("auth.api_keys.max_per_user", 10)
+	viper.SetDefault("auth.api_keys.default_rate_limit", 60)
+	viper.SetDefault("auth.api_keys.max_rate_limit", 600)
+	viper.SetDefault("auth.email_verify_url", "http://localhost:3000/verify-email")
+	viper.SetDefault("auth.password_reset_url", "http://localhost:3000/reset-password")
+	viper.SetDefault("auth.email_verify_expiry", "24h")
+	viper.SetDefault("auth.password_reset_expiry", "30m")
+
+	// Mail defaults
+	viper.SetDefault("mail.driver", "log")
+	viper.SetDefault("mail.from", "no-reply@restart-life.local")
+	viper.SetDefault("mail.from_name", "Restart Life")
+	viper.SetDefault("mail.smtp.port", 587)
+	viper.SetDefault("mail.smtp.encryption", "starttls")
+	viper.SetDefault("mail.smtp.timeout", "10s")
+	viper.SetDefault("mail.file_dir", "tmp/mail")
+
+	// Storage defaults
+	viper.SetDefault("storage.driver", "local")
+	viper.SetDefault("storage.local.dir", "data/uploads")
+	viper.SetDefault("storage.local.url_prefix", "/uploads")
+	viper.SetDefault("storage.s3.region", "us-east-1")
+	viper.SetDefault("storage.s3.timeout", "30s")
+
+	// Privacy defaults
+	viper.SetDefault("privacy.deletion_grace_period", "720h")
+	viper.SetDefault("privacy.export_expiry", "168h")
+	viper.SetDefault("privacy.export_cooldown", "1h")
+	viper.SetDefault("privacy.purge_interval", "1h")
+
+	// Game defaults
+	viper.SetDefault("game.character_draft_ttl", "30m")
+	viper.SetDefault("game.max_rerolls", 10)
+	viper.SetDefault("game.character_archive_retention", "720h")
+	viper.SetDefault("game.character_purge_interval", "1h")
+	viper.SetDefault("game.skill_training_cooldown", "1h")
 
 	// Logging defaults
 	viper.SetDefault("logging.level", "debug")
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
@@ -115,6 +116,26 @@ func (m *MySQLDB) QueryRow(query string, args ...interface{}) *sql.Row {
 	return m.DB.QueryRow(query, args...)
 }
 
+// BeginTx 开始带上下文的事务
+func (m *MySQLDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return m.DB.BeginTx(ctx, opts)
+}
+
+// ExecContext 执行带上下文的SQL语句
+func (m *MySQLDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return m.DB.ExecContext(ctx, query, args...)
+}
+
+// QueryContext 带上下文查询数据
+func (m *MySQLDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return m.DB.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext 带上下文查询单行数据
+func (m *MySQLDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return m.DB.QueryRowContext(ctx, query, args...)
+}
+
 // InitMySQLFromConfig 根据全局配置初始化 MySQL 连接
 func InitMySQLFromConfig(cfg *config.Config) (*MySQLDB, error) {
 	return NewMySQLDB(&MySQLConfig{
@@ -0,0 +1,176 @@
+package generator
+
+import "strings"
+
+// 人种/族裔分类
+const (
+	RaceEastAsian      = "east_asian"
+	RaceSoutheastAsian = "southeast_asian"
+	RaceSouthAsian     = "south_asian"
+	RaceWhite          = "white"
+	RaceBlack          = "black"
+	RaceHispanic       = "hispanic"
+	RaceMiddleEastern  = "middle_eastern"
+	RaceIndigenous     = "indigenous"
+	RaceMixed          = "mixed"
+)
+
+// weighted 带权重的候选值
+type weighted struct {
+	Value  string
+	Weight float64
+}
+
+// devPoint 发展水平关键帧，年份之间线性插值
+type devPoint struct {
+	Year  int
+	Level float64 // 0~1，综合反映营养、医疗与家庭经济水平
+}
+
+// countryProfile 国家生成参数
+type countryProfile struct {
+	Races       []weighted
+	Development []devPoint
+	// BirthWeightBase 发展水平为 0 时的平均出生体重（千克）
+	BirthWeightBase float64
+	// BirthLengthBase 发展水平为 0 时的平均出生身长（厘米）
+	BirthLengthBase float64
+}
+
+// defaultProfile 未收录国家使用的通用参数
+var defaultProfile = countryProfile{
+	Races: []weighted{
+		{RaceWhite, 0.3}, {RaceEastAsian, 0.2}, {RaceSouthAsian, 0.15}, {RaceBlack, 0.15},
+		{RaceHispanic, 0.1}, {RaceMiddleEastern, 0.05}, {RaceMixed, 0.05},
+	},
+	Development:     []devPoint{{1900, 0.15}, {1950, 0.25}, {2000, 0.5}, {2030, 0.65}},
+	BirthWeightBase: 2.95,
+	BirthLengthBase: 48.5,
+}
+
+// countryProfiles 各国生成参数，键为 ISO 3166-1 alpha-2 国家代码
+var countryProfiles = map[string]countryProfile{
+	"CN": {
+		Races:           []weighted{{RaceEastAsian, 0.99}, {RaceMixed, 0.01}},
+		Development:     []devPoint{{1900, 0.08}, {1950, 0.1}, {1980, 0.25}, {2000, 0.5}, {2020, 0.75}},
+		BirthWeightBase: 2.9,
+		BirthLengthBase: 48.5,
+	},
+	"JP": {
+		Races:           []weighted{{RaceEastAsian, 0.98}, {RaceMixed, 0.02}},
+		Development:     []devPoint{{1900, 0.15}, {1945, 0.15}, {1970, 0.65}, {1990, 0.85}, {2020, 0.9}},
+		BirthWeightBase: 2.85,
+		BirthLengthBase: 48.3,
+	},
+	"KR": {
+		Races:           []weighted{{RaceEastAsian, 0.98}, {RaceMixed, 0.02}},
+		Development:     []devPoint{{1900, 0.08}, {1953, 0.08}, {1980, 0.4}, {2000, 0.75}, {2020, 0.9}},
+		BirthWeightBase: 2.9,
+		BirthLengthBase: 48.5,
+	},
+	"IN": {
+		Races:           []weighted{{RaceSouthAsian, 0.99}, {RaceMixed, 0.01}},
+		Development:     []devPoint{{1900, 0.05}, {1950, 0.08}, {1990, 0.2}, {2020, 0.45}},
+		BirthWeightBase: 2.6,
+		BirthLengthBase: 47.5,
+	},
+	"US": {
+		Races: []weighted{
+			{RaceWhite, 0.6}, {RaceHispanic, 0.18}, {RaceBlack, 0.12}, {RaceEastAsian, 0.04},
+			{RaceSouthAsian, 0.02}, {RaceIndigenous, 0.01}, {RaceMixed, 0.03},
+		},
+		Development:     []devPoint{{1900, 0.35}, {1930, 0.35}, {1950, 0.6}, {1980, 0.8}, {2020, 0.9}},
+		BirthWeightBase: 3.05,
+		BirthLengthBase: 49,
+	},
+	"GB": {
+		Races:           []weighted{{RaceWhite, 0.82}, {RaceSouthAsian, 0.08}, {RaceBlack, 0.04}, {RaceEastAsian, 0.02}, {RaceMixed, 0.04}},
+		Development:     []devPoint{{1900, 0.35}, {1945, 0.4}, {1970, 0.7}, {2000, 0.85}, {2020, 0.9}},
+		BirthWeightBase: 3.05,
+		BirthLengthBase: 49,
+	},
+	"DE": {
+		Races:           []weighted{{RaceWhite, 0.9}, {RaceMiddleEastern, 0.05}, {RaceMixed, 0.05}},
+		Development:     []devPoint{{1900, 0.3}, {1945, 0.2}, {1970, 0.7}, {2000, 0.85}, {2020, 0.9}},
+		BirthWeightBase: 3.1,
+		BirthLengthBase: 49.5,
+	},
+	"FR": {
+		Races:           []weighted{{RaceWhite, 0.85}, {RaceMiddleEastern, 0.07}, {RaceBlack, 0.05}, {RaceMixed, 0.03}},
+		Development:     []devPoint{{1900, 0.3}, {1945, 0.3}, {1975, 0.7}, {2000, 0.85}, {2020, 0.9}},
+		BirthWeightBase: 3.05,
+		BirthLengthBase: 49,
+	},
+	"RU": {
+		Races:           []weighted{{RaceWhite, 0.85}, {RaceEastAsian, 0.05}, {RaceMiddleEastern, 0.05}, {RaceMixed, 0.05}},
+		Development:     []devPoint{{1900, 0.12}, {1950, 0.25}, {1980, 0.5}, {1995, 0.4}, {2020, 0.65}},
+		BirthWeightBase: 3,
+		BirthLengthBase: 49,
+	},
+	"BR": {
+		Races:           []weighted{{RaceMixed, 0.45}, {RaceWhite, 0.43}, {RaceBlack, 0.1}, {RaceIndigenous, 0.01}, {RaceEastAsian, 0.01}},
+		Development:     []devPoint{{1900, 0.1}, {1950, 0.15}, {1990, 0.35}, {2020, 0.55}},
+		BirthWeightBase: 2.9,
+		BirthLengthBase: 48.5,
+	},
+	"MX": {
+		Races:           []weighted{{RaceHispanic, 0.62}, {RaceIndigenous, 0.21}, {RaceWhite, 0.1}, {RaceMixed, 0.07}},
+		Development:     []devPoint{{1900, 0.1}, {1950, 0.15}, {1990, 0.35}, {2020, 0.55}},
+		BirthWeightBase: 2.9,
+		BirthLengthBase: 48.5,
+	},
+	"NG": {
+		Races:           []weighted{{RaceBlack, 0.99}, {RaceMixed, 0.01}},
+		Development:     []devPoint{{1900, 0.04}, {1960, 0.06}, {2000, 0.12}, {2020, 0.25}},
+		BirthWeightBase: 2.75,
+		BirthLengthBase: 48,
+	},
+	"EG": {
+		Races:           []weighted{{RaceMiddleEastern, 0.98}, {RaceBlack, 0.01}, {RaceMixed, 0.01}},
+		Development:     []devPoint{{1900, 0.06}, {1950, 0.1}, {1990, 0.25}, {2020, 0.4}},
+		BirthWeightBase: 2.85,
+		BirthLengthBase: 48.5,
+	},
+	"ZA": {
+		Races:           []weighted{{RaceBlack, 0.8}, {RaceMixed, 0.09}, {RaceWhite, 0.08}, {RaceSouthAsian, 0.03}},
+		Development:     []devPoint{{1900, 0.1}, {1950, 0.15}, {1994, 0.25}, {2020, 0.4}},
+		BirthWeightBase: 2.8,
+		BirthLengthBase: 48.5,
+	},
+	"AU": {
+		Races:           []weighted{{RaceWhite, 0.76}, {RaceEastAsian, 0.1}, {RaceSouthAsian, 0.05}, {RaceIndigenous, 0.03}, {RaceMixed, 0.06}},
+		Development:     []devPoint{{1900, 0.35}, {1945, 0.45}, {1970, 0.75}, {2000, 0.88}, {2020, 0.92}},
+		BirthWeightBase: 3.1,
+		BirthLengthBase: 49.5,
+	},
+	"CA": {
+		Races:           []weighted{{RaceWhite, 0.7}, {RaceEastAsian, 0.1}, {RaceSouthAsian, 0.07}, {RaceIndigenous, 0.05}, {RaceBlack, 0.04}, {RaceMixed, 0.04}},
+		Development:     []devPoint{{1900, 0.35}, {1945, 0.45}, {1970, 0.75}, {2000, 0.88}, {2020, 0.92}},
+		BirthWeightBase: 3.1,
+		BirthLengthBase: 49.5,
+	},
+}
+
+// lookupProfile 获取国家生成参数，国家代码不区分大小写，未收录时返回通用参数
+func lookupProfile(country string) countryProfile {
+	if profile, ok := countryProfiles[strings.ToUpper(strings.TrimSpace(country))]; ok {
+		return profile
+	}
+	return defaultProfile
+}
+
+// developmentAt 计算指定年份的发展水平，超出关键帧范围时取端点值
+func (p countryProfile) developmentAt(year int) float64 {
+	points := p.Development
+	if year <= points[0].Year {
+		return points[0].Level
+	}
+	for i := 1; i < len(points); i++ {
+		if year <= points[i].Year {
+			prev, next := points[i-1], points[i]
+			ratio := float64(year-prev.Year) / float64(next.Year-prev.Year)
+			return prev.Level + (next.Level-prev.Level)*ratio
+		}
+	}
+	return points[len(points)-1].Level
+}
@@ -0,0 +1,243 @@
+package generator
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+)
+
+// 职业
+const (
+	OccupationFarmer             = "farmer"
+	OccupationConstructionWorker = "construction_worker"
+	OccupationFactoryWorker      = "factory_worker"
+	OccupationCraftsman          = "craftsman"
+	OccupationShopkeeper         = "shopkeeper"
+	OccupationClerk              = "clerk"
+	OccupationDriver             = "driver"
+	OccupationTeacher            = "teacher"
+	OccupationNurse              = "nurse"
+	OccupationEngineer           = "engineer"
+	OccupationDoctor             = "doctor"
+	OccupationCivilServant       = "civil_servant"
+	OccupationManager            = "manager"
+	OccupationBusinessOwner      = "business_owner"
+	OccupationLandowner          = "landowner"
+	OccupationHomemaker          = "homemaker"
+	OccupationUnemployed         = "unemployed"
+	OccupationRetired            = "retired"
+)
+
+// 职业层级
+const (
+	tierManual       = "manual"
+	tierSkilled      = "skilled"
+	tierProfessional = "professional"
+	tierElite        = "elite"
+)
+
+// 家庭参数
+const (
+	retirementAge  = 60
+	maxOlderSibs   = 8
+	siblingGapMean = 2.5
+)
+
+// tierWeights 各家庭背景下父母职业层级的分布
+var tierWeights = map[string][]weighted{
+	FamilyPoor:     {{tierManual, 0.75}, {tierSkilled, 0.2}, {tierProfessional, 0.05}},
+	FamilyWorking:  {{tierManual, 0.5}, {tierSkilled, 0.4}, {tierProfessional, 0.1}},
+	FamilyMiddle:   {{tierManual, 0.15}, {tierSkilled, 0.45}, {tierProfessional, 0.35}, {tierElite, 0.05}},
+	FamilyAffluent: {{tierManual, 0.05}, {tierSkilled, 0.25}, {tierProfessional, 0.5}, {tierElite, 0.2}},
+	FamilyWealthy:  {{tierSkilled, 0.1}, {tierProfessional, 0.4}, {tierElite, 0.5}},
+}
+
+// occupationWeights 层级内的职业分布，发展水平越高农业和手工业越少
+func occupationWeights(tier string, development float64) []weighted {
+	switch tier {
+	case tierManual:
+		return []weighted{
+			{OccupationFarmer, 1.05 - development},
+			{OccupationConstructionWorker, 0.5},
+			{OccupationFactoryWorker, 0.2 + development},
+		}
+	case tierSkilled:
+		return []weighted{
+			{OccupationCraftsman, 1.1 - development},
+			{OccupationShopkeeper, 0.6},
+			{OccupationClerk, 0.2 + development},
+			{OccupationDriver, 0.1 + 0.5*development},
+		}
+	case tierProfessional:
+		return []weighted{
+			{OccupationTeacher, 1},
+			{OccupationNurse, 0.5 + 0.5*development},
+			{OccupationEngineer, 0.2 + development},
+			{OccupationDoctor, 0.4},
+			{OccupationCivilServant, 0.6},
+		}
+	default:
+		return []weighted{
+			{OccupationBusinessOwner, 1},
+			{OccupationManager, 0.3 + development},
+			{OccupationLandowner, 1.05 - development},
+		}
+	}
+}
+
+// generateFamily 生成角色出生时的家庭
+// 父母年龄、兄弟姐妹数量、祖辈在世概率和职业结构随发展水平变化，职业层级取决于家庭背景
+func generateFamily(rng *rand.Rand, development float64, background string) *models.Family {
+	family := &models.Family{IncomeClass: background}
+
+	// 父母
+	motherAge := int(clamp(math.Round(normal(rng, 22+8*development, 4.5)), 16, 45))
+	fatherAge := int(clamp(math.Round(normal(rng, float64(motherAge)+3, 3)), 17, 60))
+	father := &models.FamilyMember{
+		Relation:   models.RelationFather,
+		Gender:     GenderMale,
+		AgeAtBirth: fatherAge,
+		IsAlive:    true,
+		Occupation: parentOccupation(rng, development, background, 0.03),
+		Closeness:  closeness(rng, 70),
+	}
+	mother := &models.FamilyMember{
+		Relation:   models.RelationMother,
+		Gender:     GenderFemale,
+		AgeAtBirth: motherAge,
+		IsAlive:    true,
+		Closeness:  closeness(rng, 75),
+	}
+	if rng.Float64() < 0.1+0.7*(1-development) {
+		mother.Occupation = OccupationHomemaker
+	} else {
+		mother.Occupation = parentOccupation(rng, development, background, 0.03)
+	}
+
+	// 祖辈：与父母的年龄差随发展水平增大，在世概率取决于年龄与当时的预期寿命
+	lifeExpectancy := 40 + 40*development
+	grandparents := []*models.FamilyMember{
+		grandparent(rng, models.RelationPaternalGrandfather, GenderMale, fatherAge, development, lifeExpectancy),
+		grandparent(rng, models.RelationPaternalGrandmother, GenderFemale, fatherAge, development, lifeExpectancy),
+		grandparent(rng, models.RelationMaternalGrandfather, GenderMale, motherAge, development, lifeExpectancy),
+		grandparent(rng, models.RelationMaternalGrandmother, GenderFemale, motherAge, development, lifeExpectancy),
+	}
+
+	// 兄弟姐妹：只有年长的兄弟姐妹在角色出生时已存在，数量受生育率和母亲年龄限制
+	fertility := 1.3 + 5*(1-development)*(1-development)
+	olderCount := poisson(rng, (fertility-1)/2)
+	if limit := (motherAge - 17) / 2; olderCount > limit {
+		olderCount = limit
+	}
+	if olderCount > maxOlderSibs {
+		olderCount = maxOlderSibs
+	}
+	siblings := make([]*models.FamilyMember, 0, olderCount)
+	age := 0
+	for i := 0; i < olderCount; i++ {
+		age += 1 + int(clamp(math.Round(normal(rng, siblingGapMean-1, 1)), 0, 4))
+		if age > motherAge-16 {
+			break
+		}
+		sibling := &models.FamilyMember{
+			Relation:   models.RelationOlderSister,
+			Gender:     GenderFemale,
+			AgeAtBirth: age,
+			IsAlive:    true,
+			Closeness:  closeness(rng, 60),
+		}
+		if rng.Float64() < maleBirthRatio {
+			sibling.Relation = models.RelationOlderBrother
+			sibling.Gender = GenderMale
+		}
+		siblings = append(siblings, sibling)
+	}
+
+	// 居住地与住房：务农家庭多在农村，发展水平越高城市家庭越多
+	urban := 0.1 + 0.75*development
+	switch background {
+	case FamilyPoor:
+		urban -= 0.1
+	case FamilyAffluent, FamilyWealthy:
+		urban += 0.15
+	}
+	urban = clamp(urban, 0.02, 0.98)
+	if father.Occupation == OccupationFarmer || father.Occupation == OccupationLandowner {
+		urban *= 0.1
+	}
+	family.HomeRegion = pick(rng, []weighted{
+		{models.HomeRegionUrban, urban},
+		{models.HomeRegionTown, 0.25},
+		{models.HomeRegionRural, 1 - urban},
+	})
+	family.Housing = pick(rng, housingWeights(background))
+
+	family.Members = make([]*models.FamilyMember, 0, 6+len(siblings))
+	family.Members = append(family.Members, grandparents...)
+	family.Members = append(family.Members, father, mother)
+	family.Members = append(family.Members, siblings...)
+	return family
+}
+
+// parentOccupation 按家庭背景抽取父母职业
+func parentOccupation(rng *rand.Rand, development float64, background string, unemployment float64) string {
+	if rng.Float64() < unemployment {
+		return OccupationUnemployed
+	}
+	tier := pick(rng, tierWeights[background])
+	return pick(rng, occupationWeights(tier, development))
+}
+
+// grandparent 生成祖辈，已故或退休的祖辈不再抽取职业
+func grandparent(rng *rand.Rand, relation, gender string, childAge int, development, lifeExpectancy float64) *models.FamilyMember {
+	age := childAge + int(clamp(math.Round(normal(rng, 25+5*development, 4)), 16, 45))
+	member := &models.FamilyMember{
+		Relation:   relation,
+		Gender:     gender,
+		AgeAtBirth: age,
+		IsAlive:    rng.Float64() < 1/(1+math.Exp((float64(age)-lifeExpectancy)/6)),
+	}
+	if !member.IsAlive {
+		return member
+	}
+	member.Closeness = closeness(rng, 55)
+	if age >= retirementAge {
+		member.Occupation = OccupationRetired
+	} else {
+		member.Occupation = pick(rng, occupationWeights(tierManual, development))
+	}
+	return member
+}
+
+// housingWeights 住房情况分布，家庭越富裕自有住房越多
+func housingWeights(background string) []weighted {
+	switch background {
+	case FamilyPoor:
+		return []weighted{{models.HousingOwned, 0.2}, {models.HousingRented, 0.4}, {models.HousingShared, 0.4}}
+	case FamilyWorking:
+		return []weighted{{models.HousingOwned, 0.4}, {models.HousingRented, 0.4}, {models.HousingShared, 0.2}}
+	case FamilyMiddle:
+		return []weighted{{models.HousingOwned, 0.6}, {models.HousingRented, 0.3}, {models.HousingShared, 0.1}}
+	default:
+		return []weighted{{models.HousingOwned, 0.9}, {models.HousingRented, 0.08}, {models.HousingShared, 0.02}}
+	}
+}
+
+// closeness 抽取 0~100 的亲密程度
+func closeness(rng *rand.Rand, mean float64) int {
+	return int(clamp(math.Round(normal(rng, mean, 15)), 0, 100))
+}
+
+// poisson 泊松分布抽样
+func poisson(rng *rand.Rand, lambda float64) int {
+	if lambda <= 0 {
+		return 0
+	}
+	limit := math.Exp(-lambda)
+	count := 0
+	for p := rng.Float64(); p > limit; p *= rng.Float64() {
+		count++
+	}
+	return count
+}
@@ -0,0 +1,181 @@
+// Package generator 根据出生国家、出生年份和随机种子生成角色的先天条件
+// 相同的输入与生成器版本总能得到相同的角色，修改生成规则时需提升 Version
+package generator
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+)
+
+// Version 生成规则版本，与种子一起保存，用于复现角色
+// 版本 2 增加了家庭生成，版本 3 增加了性格倾向
+const Version = 3
+
+// 性别，出生性别比约为 105:100
+const (
+	GenderMale   = "male"
+	GenderFemale = "female"
+
+	maleBirthRatio = 105.0 / 205.0
+)
+
+// 家庭背景
+const (
+	FamilyPoor     = "poor"
+	FamilyWorking  = "working"
+	FamilyMiddle   = "middle"
+	FamilyAffluent = "affluent"
+	FamilyWealthy  = "wealthy"
+)
+
+// 属性分布参数
+const (
+	attributeMean   = 50
+	attributeStdDev = 15
+)
+
+// Input 生成参数
+type Input struct {
+	Country   string // ISO 3166-1 alpha-2 国家代码
+	BirthYear int
+	Seed      int64
+}
+
+// Result 生成结果
+type Result struct {
+	Gender           string
+	Race             string
+	HeightCM         float64 // 出生身长
+	WeightKG         float64 // 出生体重
+	FamilyBackground string
+
+	Intelligence          int
+	EmotionalIntelligence int
+	Memory                int
+	Imagination           int
+	PhysicalFitness       int
+	Appearance            int
+
+	Personality models.Personality
+	Family      *models.Family
+	Traits      models.Traits
+}
+
+// Generate 生成角色先天条件
+// 抽样顺序是生成规则的一部分，调整顺序等同于修改规则
+func Generate(input Input) *Result {
+	rng := rand.New(rand.NewSource(input.Seed))
+	profile := lookupProfile(input.Country)
+	development := profile.developmentAt(input.BirthYear)
+
+	result := &Result{}
+
+	result.Gender = GenderFemale
+	if rng.Float64() < maleBirthRatio {
+		result.Gender = GenderMale
+	}
+	result.Race = pick(rng, profile.Races)
+	result.FamilyBackground = pick(rng, familyWeights(development))
+
+	// 出生身长体重随营养水平提高，男婴略高于女婴
+	lengthMean := profile.BirthLengthBase + 1.5*development
+	weightMean := profile.BirthWeightBase + 0.35*development
+	if result.Gender == GenderMale {
+		lengthMean += 0.7
+		weightMean += 0.12
+	}
+	result.HeightCM = round(clamp(normal(rng, lengthMean, 2.2), 40, 58), 1)
+	result.WeightKG = round(clamp(normal(rng, weightMean, 0.45), 1.5, 5.5), 2)
+
+	// 先天属性：体质受营养与医疗条件影响，其余属性与国家和年代无关
+	result.Intelligence = attribute(rng, 0)
+	result.EmotionalIntelligence = attribute(rng, 0)
+	result.Memory = attribute(rng, 0)
+	result.Imagination = attribute(rng, 0)
+	result.PhysicalFitness = attribute(rng, (development-0.5)*10)
+	result.Appearance = attribute(rng, 0)
+
+	result.Personality = models.Personality{
+		Openness:          attribute(rng, 0),
+		Conscientiousness: attribute(rng, 0),
+		Extraversion:      attribute(rng, 0),
+		Agreeableness:     attribute(rng, 0),
+		Neuroticism:       attribute(rng, 0),
+	}
+
+	result.Family = generateFamily(rng, development, result.FamilyBackground)
+	result.Traits = generateTraits(rng, result.Personality)
+
+	return result
+}
+
+// Apply 将生成结果写入角色
+func (r *Result) Apply(character *models.Character) {
+	character.Gender = r.Gender
+	character.Race = r.Race
+	character.HeightCM = r.HeightCM
+	character.WeightKG = r.WeightKG
+	character.FamilyBackground = r.FamilyBackground
+	character.Intelligence = r.Intelligence
+	character.EmotionalIntelligence = r.EmotionalIntelligence
+	character.Memory = r.Memory
+	character.Imagination = r.Imagination
+	character.PhysicalFitness = r.PhysicalFitness
+	character.Appearance = r.Appearance
+	personality := r.Personality
+	character.Personality = &personality
+	character.Family = r.Family
+	traits := r.Traits
+	character.Traits = &traits
+}
+
+// familyWeights 家庭背景分布，发展水平越高中产及以上家庭越多
+func familyWeights(development float64) []weighted {
+	return []weighted{
+		{FamilyPoor, 0.05 + 0.55*(1-development)},
+		{FamilyWorking, 0.3},
+		{FamilyMiddle, 0.1 + 0.35*development},
+		{FamilyAffluent, 0.03 + 0.12*development},
+		{FamilyWealthy, 0.01 + 0.03*development},
+	}
+}
+
+// pick 按权重抽取
+func pick(rng *rand.Rand, candidates []weighted) string {
+	total := 0.0
+	for _, c := range candidates {
+		total += c.Weight
+	}
+	target := rng.Float64() * total
+	for _, c := range candidates {
+		target -= c.Weight
+		if target < 0 {
+			return c.Value
+		}
+	}
+	return candidates[len(candidates)-1].Value
+}
+
+// attribute 抽取 0~100 的属性值
+func attribute(rng *rand.Rand, shift float64) int {
+	value := normal(rng, attributeMean+shift, attributeStdDev)
+	return int(clamp(math.Round(value), models.MinAttributeValue, models.MaxAttributeValue))
+}
+
+// normal 正态分布抽样
+func normal(rng *rand.Rand, mean, stdDev float64) float64 {
+	return mean + rng.NormFloat64()*stdDev
+}
+
+// clamp 限制取值范围
+func clamp(value, lower, upper float64) float64 {
+	return math.Max(lower, math.Min(upper, value))
+}
+
+// round 保留指定位数小数
+func round(value float64, digits int) float64 {
+	factor := math.Pow(10, float64(digits))
+	return math.Round(value*factor) / factor
+}
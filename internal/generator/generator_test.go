@@ -0,0 +1,169 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+)
+
+// v1Fields 版本 1 生成的字段，之后的版本只能在其后追加抽样，这些字段不得变化
+type v1Fields struct {
+	Gender           string
+	Race             string
+	HeightCM         float64
+	WeightKG         float64
+	FamilyBackground string
+
+	Intelligence          int
+	EmotionalIntelligence int
+	Memory                int
+	Imagination           int
+	PhysicalFitness       int
+	Appearance            int
+
+	Personality models.Personality
+}
+
+// goldenCases 已保存角色的复现基准
+// v1 由版本 1 生成，family 由版本 2 生成，traits 由版本 3 生成，提升版本时只能追加新字段的期望值
+var goldenCases = []struct {
+	input  Input
+	v1     v1Fields
+	family *models.Family
+	traits models.Traits
+}{
+	{
+		input: Input{Country: "CN", BirthYear: 1990, Seed: 42},
+		v1: v1Fields{
+			Gender: GenderMale, Race: "east_asian", HeightCM: 52.5, WeightKG: 3.21, FamilyBackground: FamilyWorking,
+			Intelligence: 68, EmotionalIntelligence: 41, Memory: 59, Imagination: 73, PhysicalFitness: 36, Appearance: 30,
+			Personality: models.Personality{Openness: 63, Conscientiousness: 69, Extraversion: 55, Agreeableness: 40, Neuroticism: 77},
+		},
+		family: &models.Family{
+			IncomeClass: FamilyWorking, HomeRegion: models.HomeRegionRural, Housing: models.HousingShared,
+			Members: []*models.FamilyMember{
+				{Relation: models.RelationPaternalGrandfather, Gender: GenderMale, AgeAtBirth: 46, IsAlive: true, Occupation: OccupationFarmer, Closeness: 52},
+				{Relation: models.RelationPaternalGrandmother, Gender: GenderFemale, AgeAtBirth: 54, IsAlive: true, Occupation: OccupationFactoryWorker, Closeness: 67},
+				{Relation: models.RelationMaternalGrandfather, Gender: GenderMale, AgeAtBirth: 48, IsAlive: false},
+				{Relation: models.RelationMaternalGrandmother, Gender: GenderFemale, AgeAtBirth: 51, IsAlive: false},
+				{Relation: models.RelationFather, Gender: GenderMale, AgeAtBirth: 22, IsAlive: true, Occupation: OccupationTeacher, Closeness: 83},
+				{Relation: models.RelationMother, Gender: GenderFemale, AgeAtBirth: 23, IsAlive: true, Occupation: OccupationHomemaker, Closeness: 81},
+				{Relation: models.RelationOlderSister, Gender: GenderFemale, AgeAtBirth: 1, IsAlive: true, Closeness: 53},
+				{Relation: models.RelationOlderSister, Gender: GenderFemale, AgeAtBirth: 4, IsAlive: true, Closeness: 63},
+			},
+		},
+		traits: models.Traits{Extraversion: -0.16, Optimism: -0.483, Bravery: -0.17},
+	},
+	{
+		input: Input{Country: "US", BirthYear: 1850, Seed: 7},
+		v1: v1Fields{
+			Gender: GenderFemale, Race: "white", HeightCM: 48.2, WeightKG: 3, FamilyBackground: FamilyPoor,
+			Intelligence: 60, EmotionalIntelligence: 89, Memory: 67, Imagination: 52, PhysicalFitness: 21, Appearance: 88,
+			Personality: models.Personality{Openness: 63, Conscientiousness: 61, Extraversion: 40, Agreeableness: 60, Neuroticism: 57},
+		},
+		family: &models.Family{
+			IncomeClass: FamilyPoor, HomeRegion: models.HomeRegionRural, Housing: models.HousingShared,
+			Members: []*models.FamilyMember{
+				{Relation: models.RelationPaternalGrandfather, Gender: GenderMale, AgeAtBirth: 55, IsAlive: false},
+				{Relation: models.RelationPaternalGrandmother, Gender: GenderFemale, AgeAtBirth: 51, IsAlive: false},
+				{Relation: models.RelationMaternalGrandfather, Gender: GenderMale, AgeAtBirth: 44, IsAlive: true, Occupation: OccupationFarmer, Closeness: 51},
+				{Relation: models.RelationMaternalGrandmother, Gender: GenderFemale, AgeAtBirth: 45, IsAlive: false},
+				{Relation: models.RelationFather, Gender: GenderMale, AgeAtBirth: 21, IsAlive: true, Occupation: OccupationFactoryWorker, Closeness: 90},
+				{Relation: models.RelationMother, Gender: GenderFemale, AgeAtBirth: 19, IsAlive: true, Occupation: OccupationHomemaker, Closeness: 68},
+				{Relation: models.RelationOlderBrother, Gender: GenderMale, AgeAtBirth: 2, IsAlive: true, Closeness: 77},
+			},
+		},
+		traits: models.Traits{Extraversion: -0.378, Optimism: -0.334, Bravery: 0.216},
+	},
+	{
+		input: Input{Country: "NG", BirthYear: 2030, Seed: -1234567890123},
+		v1: v1Fields{
+			Gender: GenderFemale, Race: "black", HeightCM: 46.4, WeightKG: 3.07, FamilyBackground: FamilyPoor,
+			Intelligence: 40, EmotionalIntelligence: 64, Memory: 51, Imagination: 88, PhysicalFitness: 78, Appearance: 37,
+			Personality: models.Personality{Openness: 42, Conscientiousness: 24, Extraversion: 70, Agreeableness: 35, Neuroticism: 36},
+		},
+		family: &models.Family{
+			IncomeClass: FamilyPoor, HomeRegion: models.HomeRegionTown, Housing: models.HousingShared,
+			Members: []*models.FamilyMember{
+				{Relation: models.RelationPaternalGrandfather, Gender: GenderMale, AgeAtBirth: 54, IsAlive: false},
+				{Relation: models.RelationPaternalGrandmother, Gender: GenderFemale, AgeAtBirth: 47, IsAlive: true, Occupation: OccupationFarmer, Closeness: 52},
+				{Relation: models.RelationMaternalGrandfather, Gender: GenderMale, AgeAtBirth: 45, IsAlive: true, Occupation: OccupationFarmer, Closeness: 30},
+				{Relation: models.RelationMaternalGrandmother, Gender: GenderFemale, AgeAtBirth: 53, IsAlive: false},
+				{Relation: models.RelationFather, Gender: GenderMale, AgeAtBirth: 26, IsAlive: true, Occupation: OccupationFarmer, Closeness: 78},
+				{Relation: models.RelationMother, Gender: GenderFemale, AgeAtBirth: 23, IsAlive: true, Occupation: OccupationHomemaker, Closeness: 93},
+				{Relation: models.RelationOlderSister, Gender: GenderFemale, AgeAtBirth: 3, IsAlive: true, Closeness: 62},
+				{Relation: models.RelationOlderBrother, Gender: GenderMale, AgeAtBirth: 5, IsAlive: true, Closeness: 61},
+			},
+		},
+		traits: models.Traits{Extraversion: 0.474, Optimism: 0.037, Bravery: 0.112},
+	},
+}
+
+func TestGenerateGolden(t *testing.T) {
+	if Version != 3 {
+		t.Fatalf("Version = %d, add the fields introduced by the new version to goldenCases", Version)
+	}
+
+	for _, tc := range goldenCases {
+		result := Generate(tc.input)
+
+		v1 := v1Fields{
+			Gender:                result.Gender,
+			Race:                  result.Race,
+			HeightCM:              result.HeightCM,
+			WeightKG:              result.WeightKG,
+			FamilyBackground:      result.FamilyBackground,
+			Intelligence:          result.Intelligence,
+			EmotionalIntelligence: result.EmotionalIntelligence,
+			Memory:                result.Memory,
+			Imagination:           result.Imagination,
+			PhysicalFitness:       result.PhysicalFitness,
+			Appearance:            result.Appearance,
+			Personality:           result.Personality,
+		}
+		if v1 != tc.v1 {
+			t.Errorf("%+v: version 1 fields changed\n got %+v\nwant %+v", tc.input, v1, tc.v1)
+		}
+		if !reflect.DeepEqual(result.Family, tc.family) {
+			t.Errorf("%+v: version 2 family changed\n got %s\nwant %s", tc.input, describeFamily(result.Family), describeFamily(tc.family))
+		}
+		if result.Traits != tc.traits {
+			t.Errorf("%+v: version 3 traits changed\n got %+v\nwant %+v", tc.input, result.Traits, tc.traits)
+		}
+
+		var character models.Character
+		result.Apply(&character)
+		if character.Gender != tc.v1.Gender || character.HeightCM != tc.v1.HeightCM ||
+			character.FamilyBackground != tc.v1.FamilyBackground || character.Appearance != tc.v1.Appearance ||
+			*character.Personality != tc.v1.Personality || character.Family != result.Family || *character.Traits != tc.traits {
+			t.Errorf("%+v: Apply() did not copy the generated fields to the character", tc.input)
+		}
+	}
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	input := Input{Country: "JP", BirthYear: 1975, Seed: 20260101}
+	if !reflect.DeepEqual(Generate(input), Generate(input)) {
+		t.Fatal("Generate() is not deterministic for the same input")
+	}
+
+	other := input
+	other.Seed++
+	if reflect.DeepEqual(Generate(input), Generate(other)) {
+		t.Fatal("Generate() ignores the seed")
+	}
+}
+
+// describeFamily 展开家庭成员以便比较失败时查看
+func describeFamily(family *models.Family) string {
+	if family == nil {
+		return "<nil>"
+	}
+	out := family.IncomeClass + " " + family.HomeRegion + " " + family.Housing
+	for _, member := range family.Members {
+		out += fmt.Sprintf("\n  %+v", *member)
+	}
+	return out
+}
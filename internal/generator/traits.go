@@ -0,0 +1,29 @@
+package generator
+
+import (
+	"math/rand"
+
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+)
+
+// traitNoise 性格倾向相对大五人格推导值的随机偏差
+const traitNoise = 0.15
+
+// generateTraits 由大五人格推导性格倾向
+// 外向性对应外向/内向，情绪稳定性与外向性决定乐观程度，开放性与情绪稳定性决定勇敢程度
+func generateTraits(rng *rand.Rand, personality models.Personality) models.Traits {
+	extraversion := float64(personality.Extraversion-50) / 50
+	stability := float64(50-personality.Neuroticism) / 50
+	openness := float64(personality.Openness-50) / 50
+
+	return models.Traits{
+		Extraversion: trait(rng, extraversion),
+		Optimism:     trait(rng, 0.7*stability+0.3*extraversion),
+		Bravery:      trait(rng, 0.6*openness+0.4*stability),
+	}
+}
+
+// trait 在推导值上叠加随机偏差，保留三位小数
+func trait(rng *rand.Rand, base float64) float64 {
+	return round(clamp(normal(rng, base, traitNoise), models.MinTraitScore, models.MaxTraitScore), 3)
+}
@@ -0,0 +1,52 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/xuchengvcc/restart-life-api/internal/utils"
+)
+
+const (
+	// defaultMailDir 默认邮件输出目录
+	defaultMailDir = "tmp/mail"
+	// defaultFileMailFrom 未配置发件人时使用的地址
+	defaultFileMailFrom = "no-reply@localhost"
+)
+
+// FileMailer 将邮件以 .eml 文件写入本地目录，用于开发和集成测试
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer 创建文件邮件发送器
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	if dir == "" {
+		dir = defaultMailDir
+	}
+	if from == "" {
+		from = defaultFileMailFrom
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+// Send 写入邮件文件，文件名按时间排序
+func (m *FileMailer) Send(ctx context.Context, msg *Message) error {
+	data, err := buildMessage(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), utils.RandomHex(4))
+	if err := os.WriteFile(filepath.Join(m.dir, name), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write mail file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogMailer 将邮件写入日志而不实际发送，用于本地开发
+type LogMailer struct{}
+
+// NewLogMailer 创建日志邮件发送器
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+// Send 输出邮件内容到日志
+func (m *LogMailer) Send(ctx context.Context, msg *Message) error {
+	logrus.WithFields(logrus.Fields{
+		"to":      msg.To,
+		"subject": msg.Subject,
+		"body":    msg.TextBody,
+	}).Info("Mail sent to log sink")
+	return nil
+}
@@ -0,0 +1,55 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/xuchengvcc/restart-life-api/internal/config"
+)
+
+// 邮件发送方式
+const (
+	DriverSMTP = "smtp"
+	DriverLog  = "log"
+	DriverFile = "file"
+)
+
+// Message 邮件内容
+type Message struct {
+	To       string
+	Subject  string
+	TextBody string
+	HTMLBody string // 可选，为空时只发送纯文本
+}
+
+// Mailer 邮件发送接口
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// NewFromConfig 根据全局配置创建邮件发送器
+// 未配置发送方式时使用日志输出，便于本地开发
+func NewFromConfig(cfg *config.Config) (Mailer, error) {
+	mail := cfg.Mail
+
+	switch strings.ToLower(mail.Driver) {
+	case DriverSMTP:
+		return NewSMTPMailer(&SMTPConfig{
+			Host:       mail.SMTP.Host,
+			Port:       mail.SMTP.Port,
+			Username:   mail.SMTP.Username,
+			Password:   mail.SMTP.Password,
+			Encryption: mail.SMTP.Encryption,
+			Timeout:    mail.SMTP.Timeout,
+			From:       mail.From,
+			FromName:   mail.FromName,
+		})
+	case DriverFile:
+		return NewFileMailer(mail.FileDir, mail.From)
+	case DriverLog, "":
+		return NewLogMailer(), nil
+	default:
+		return nil, fmt.Errorf("unsupported mail driver %q", mail.Driver)
+	}
+}
@@ -0,0 +1,69 @@
+package mailer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/xuchengvcc/restart-life-api/internal/utils"
+)
+
+// buildMessage 构造 RFC 5322 邮件，正文使用 UTF-8 + base64 编码
+func buildMessage(from string, msg *Message) ([]byte, error) {
+	if _, err := mail.ParseAddress(msg.To); err != nil {
+		return nil, fmt.Errorf("invalid recipient address: %w", err)
+	}
+	if strings.ContainsAny(msg.Subject, "\r\n") {
+		return nil, errors.New("mail subject must not contain line breaks")
+	}
+
+	var buf bytes.Buffer
+	writeHeader(&buf, "From", from)
+	writeHeader(&buf, "To", msg.To)
+	writeHeader(&buf, "Subject", mime.BEncoding.Encode("UTF-8", msg.Subject))
+	writeHeader(&buf, "Date", time.Now().Format(time.RFC1123Z))
+	writeHeader(&buf, "Message-ID", fmt.Sprintf("<%s@restart-life-api>", utils.RandomHex(16)))
+	writeHeader(&buf, "MIME-Version", "1.0")
+
+	if msg.HTMLBody == "" {
+		writePart(&buf, "text/plain", msg.TextBody)
+		return buf.Bytes(), nil
+	}
+
+	boundary := "b_" + utils.RandomHex(12)
+	writeHeader(&buf, "Content-Type", fmt.Sprintf(`multipart/alternative; boundary="%s"`, boundary))
+	buf.WriteString("\r\n")
+	fmt.Fprintf(&buf, "--%s\r\n", boundary)
+	writePart(&buf, "text/plain", msg.TextBody)
+	fmt.Fprintf(&buf, "--%s\r\n", boundary)
+	writePart(&buf, "text/html", msg.HTMLBody)
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	return buf.Bytes(), nil
+}
+
+// writeHeader 写入邮件头
+func writeHeader(buf *bytes.Buffer, name, value string) {
+	fmt.Fprintf(buf, "%s: %s\r\n", name, value)
+}
+
+// writePart 写入一段 base64 编码的正文，每行 76 个字符
+func writePart(buf *bytes.Buffer, contentType, body string) {
+	writeHeader(buf, "Content-Type", contentType+"; charset=UTF-8")
+	writeHeader(buf, "Content-Transfer-Encoding", "base64")
+	buf.WriteString("\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(body))
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76])
+		buf.WriteString("\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded)
+	buf.WriteString("\r\n")
+}
@@ -0,0 +1,144 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTP 连接加密方式
+const (
+	EncryptionNone     = "none"
+	EncryptionSTARTTLS = "starttls"
+	EncryptionTLS      = "tls" // 隐式 TLS，通常为 465 端口
+)
+
+// SMTPConfig SMTP 配置
+type SMTPConfig struct {
+	Host       string
+	Port       int
+	Username   string
+	Password   string
+	Encryption string
+	Timeout    time.Duration
+	From       string
+	FromName   string
+}
+
+// SMTPMailer 通过 SMTP 服务器发送邮件
+type SMTPMailer struct {
+	config *SMTPConfig
+	from   string
+}
+
+// NewSMTPMailer 创建 SMTP 邮件发送器
+func NewSMTPMailer(config *SMTPConfig) (*SMTPMailer, error) {
+	if config.Host == "" {
+		return nil, errors.New("smtp host is required")
+	}
+	if config.From == "" {
+		return nil, errors.New("mail from address is required")
+	}
+
+	// 设置默认值
+	if config.Port == 0 {
+		config.Port = 587
+	}
+	if config.Encryption == "" {
+		config.Encryption = EncryptionSTARTTLS
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	from := (&mail.Address{Name: config.FromName, Address: config.From}).String()
+	return &SMTPMailer{config: config, from: from}, nil
+}
+
+// Send 发送邮件
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	data, err := buildMessage(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	client, err := m.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if m.config.Username != "" {
+		auth := smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("smtp auth failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(m.config.From); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return fmt.Errorf("smtp RCPT TO failed: %w", err)
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return fmt.Errorf("failed to write mail body: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to finish mail body: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// dial 建立 SMTP 连接并按配置完成 TLS 协商
+func (m *SMTPMailer) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	tlsConfig := &tls.Config{ServerName: m.config.Host}
+
+	ctx, cancel := context.WithTimeout(ctx, m.config.Timeout)
+	defer cancel()
+
+	var (
+		conn net.Conn
+		err  error
+	)
+	dialer := &net.Dialer{}
+	if strings.EqualFold(m.config.Encryption, EncryptionTLS) {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	// 整个会话共用一个超时，避免服务器无响应时阻塞
+	conn.SetDeadline(time.Now().Add(m.config.Timeout))
+
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create smtp client: %w", err)
+	}
+
+	if strings.EqualFold(m.config.Encryption, EncryptionSTARTTLS) {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("smtp STARTTLS failed: %w", err)
+		}
+	}
+	return client, nil
+}
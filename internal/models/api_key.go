@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// PlatformAPIKey 使用 API 密钥认证时令牌载荷中的平台标识
+const PlatformAPIKey = "api_key"
+
+// API 密钥专用授权范围，其余授权范围为用户通过角色拥有的权限名
+const (
+	ScopeGameRead  = "game:read"
+	ScopeGameWrite = "game:write"
+)
+
+// APIKey 个人 API 密钥
+type APIKey struct {
+	KeyID      uint64     `json:"key_id"`
+	UserID     uint64     `json:"-"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	RateLimit  int        `json:"rate_limit"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP string     `json:"last_used_ip,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateAPIKeyRequest 创建 API 密钥请求
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1,dive,required,max=100"`
+	RateLimit     int      `json:"rate_limit" binding:"omitempty,min=1"`              // 每分钟请求上限，不填使用默认值
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1,max=365"` // 有效天数，不填表示永不过期
+}
+
+// CreateAPIKeyResponse 创建 API 密钥结果，明文密钥只返回这一次
+type CreateAPIKeyResponse struct {
+	*APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,67 @@
+package models
+
+// RegisterRequest 用户注册请求
+// 密码的 max 按字符数校验，bcrypt 的 72 字节上限由服务层校验
+type RegisterRequest struct {
+	Username string `json:"username" binding:"required,min=3,max=50"`
+	Email    string `json:"email" binding:"required,email,max=255"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+}
+
+// LoginRequest 用户登录请求，username 字段同时支持用户名或邮箱
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// GuestLoginRequest 游客登录请求
+type GuestLoginRequest struct {
+	DeviceID string `json:"device_id" binding:"required,min=8,max=128"`
+}
+
+// UpgradeGuestRequest 游客账户升级请求，username 为空时保留系统生成的用户名
+type UpgradeGuestRequest struct {
+	Username string `json:"username" binding:"omitempty,min=3,max=50"`
+	Email    string `json:"email" binding:"required,email,max=255"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+}
+
+// VerifyEmailRequest 邮箱验证请求
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ForgotPasswordRequest 找回密码请求
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email,max=255"`
+}
+
+// ResetPasswordRequest 重置密码请求
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8,max=72"`
+}
+
+// DeviceInfo 设备信息
+type DeviceInfo struct {
+	DeviceID  string `json:"device_id,omitempty"`
+	Platform  string `json:"platform"`
+	Version   string `json:"version,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	IP        string `json:"ip,omitempty"`
+}
+
+// TokenPair 令牌信息
+type TokenPair struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshExpiresIn int64  `json:"refresh_expires_in"`
+}
+
+// AuthResponse 注册/登录响应
+type AuthResponse struct {
+	TokenPair
+	User UserInfo `json:"user"`
+}
@@ -0,0 +1,150 @@
+package models
+
+import "time"
+
+// 角色属性和状态取值范围
+const (
+	MinAttributeValue = 0
+	MaxAttributeValue = 100
+)
+
+// 角色初始状态，与 characters 表默认值一致
+const (
+	DefaultLifeStage      = "birth"
+	DefaultCurrentStatus  = "healthy"
+	DefaultHappinessLevel = 50
+	DefaultHealthLevel    = 100
+)
+
+// 角色列表筛选状态
+const (
+	CharacterStatusActive    = "active"    // 进行中
+	CharacterStatusCompleted = "completed" // 人生已结束
+	CharacterStatusArchived  = "archived"  // 已删除，保留期内可恢复
+)
+
+// Character 游戏角色
+type Character struct {
+	CharacterID  string    `json:"character_id" db:"character_id"`
+	UserID       uint64    `json:"-" db:"user_id"`
+	Name         string    `json:"character_name" db:"character_name"`
+	BirthCountry string    `json:"birth_country" db:"birth_country"`
+	BirthYear    int       `json:"birth_year" db:"birth_year"`
+	CurrentAge   int       `json:"current_age" db:"current_age"`
+	Gender       string    `json:"gender" db:"gender"`
+	Race         string    `json:"race" db:"race"`
+	IsActive     bool      `json:"is_active" db:"is_active"` // 未归档
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+
+	// 归档：玩家删除的角色在 PurgeAt 之前可以恢复
+	ArchivedAt *time.Time `json:"archived_at,omitempty" db:"archived_at"`
+	PurgeAt    *time.Time `json:"purge_at,omitempty" db:"-"`
+
+	// 随机生成的先天条件，旧角色没有生成记录
+	Seed             *int64       `json:"seed,omitempty" db:"generation_seed"`
+	GeneratorVersion int          `json:"generator_version,omitempty" db:"generator_version"`
+	HeightCM         float64      `json:"height_cm,omitempty" db:"height_cm"`
+	WeightKG         float64      `json:"weight_kg,omitempty" db:"weight_kg"`
+	FamilyBackground string       `json:"family_background,omitempty" db:"family_background"`
+	Personality      *Personality `json:"personality,omitempty" db:"personality"`
+	Family           *Family      `json:"family,omitempty" db:"-"` // 角色列表中不返回
+	Traits           *Traits      `json:"traits,omitempty" db:"-"` // 角色列表中不返回
+
+	// 角色属性
+	Intelligence          int `json:"intelligence" db:"intelligence"`
+	EmotionalIntelligence int `json:"emotional_intelligence" db:"emotional_intelligence"`
+	Memory                int `json:"memory" db:"memory"`
+	Imagination           int `json:"imagination" db:"imagination"`
+	PhysicalFitness       int `json:"physical_fitness" db:"physical_fitness"`
+	Appearance            int `json:"appearance" db:"appearance"`
+
+	// 技能，以及按产品文档命名换算的基础属性
+	Skills     Skills         `json:"skills"`
+	Attributes CoreAttributes `json:"attributes" db:"-"`
+
+	// 游戏状态
+	LifeStage      string `json:"life_stage" db:"life_stage"`
+	CurrentStatus  string `json:"current_status" db:"current_status"`
+	HappinessLevel int    `json:"happiness_level" db:"happiness_level"`
+	HealthLevel    int    `json:"health_level" db:"health_level"`
+	Money          int    `json:"money" db:"money"`
+
+	// 当前位置和活动
+	CurrentLocation string `json:"current_location,omitempty" db:"current_location"`
+	CurrentActivity string `json:"current_activity,omitempty" db:"current_activity"`
+
+	// 游戏进度
+	TotalPlaytime int    `json:"total_playtime" db:"total_playtime"` // 分钟
+	GameCompleted bool   `json:"game_completed" db:"game_completed"`
+	FinalAge      *int   `json:"final_age,omitempty" db:"final_age"`
+	DeathCause    string `json:"death_cause,omitempty" db:"death_cause"`
+}
+
+// Personality 大五人格，各项取值 0~100
+type Personality struct {
+	Openness          int `json:"openness"`
+	Conscientiousness int `json:"conscientiousness"`
+	Extraversion      int `json:"extraversion"`
+	Agreeableness     int `json:"agreeableness"`
+	Neuroticism       int `json:"neuroticism"`
+}
+
+// CreateCharacterRequest 创建角色请求
+// 玩家只需选择出生国家和年份，性别、种族、身体条件、家庭背景、属性和性格均随机生成
+// 种子只由服务端选取，客户端不能指定，否则可离线挑选结果并绕过重新生成次数限制
+type CreateCharacterRequest struct {
+	Name         string `json:"character_name" binding:"required,max=100"`
+	BirthCountry string `json:"birth_country" binding:"required,max=100"` // 参考数据中的国家代码，见 /api/v1/catalog/countries
+	BirthYear    int    `json:"birth_year" binding:"required"`
+}
+
+// CharacterDraft 角色草稿，临时保存在 Redis 中，确认后才写入数据库
+// 角色先天条件由种子和生成器版本决定，草稿只记录生成参数
+type CharacterDraft struct {
+	DraftID          string    `json:"draft_id"`
+	Name             string    `json:"character_name"`
+	BirthCountry     string    `json:"birth_country"`
+	BirthYear        int       `json:"birth_year"`
+	Seed             int64     `json:"seed"`
+	GeneratorVersion int       `json:"generator_version"`
+	Rerolls          int       `json:"rerolls"` // 已重新生成次数
+	CreatedAt        time.Time `json:"created_at"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+// CharacterDraftResponse 角色草稿响应，附带按草稿生成的角色预览
+type CharacterDraftResponse struct {
+	*CharacterDraft
+	RerollsRemaining int        `json:"rerolls_remaining"`
+	Character        *Character `json:"character"`
+}
+
+// UpdateCharacterRequest 更新角色请求，只允许修改展示类字段，未提供的字段保持不变
+// 属性和游戏状态由游戏进程维护，不能直接修改
+type UpdateCharacterRequest struct {
+	Name            *string `json:"character_name" binding:"omitempty,min=1,max=100"`
+	CurrentLocation *string `json:"current_location" binding:"omitempty,max=200"`
+	CurrentActivity *string `json:"current_activity" binding:"omitempty,max=200"`
+}
+
+// 角色列表排序字段
+const (
+	CharacterSortCreatedAt = "created_at"
+	CharacterSortFinalAge  = "final_age" // 人生未结束的角色视为最小
+	CharacterSortMoney     = "money"
+)
+
+// CharacterListQuery 角色列表查询条件，分页游标与排序方式和筛选条件绑定，翻页时需保持不变
+type CharacterListQuery struct {
+	Status        string `form:"status"`
+	BirthCountry  string `form:"birth_country" binding:"max=100"`
+	BirthYearMin  *int   `form:"birth_year_min"`
+	BirthYearMax  *int   `form:"birth_year_max"`
+	GameCompleted *bool  `form:"game_completed"`
+	LifeStage     string `form:"life_stage" binding:"max=50"`
+	Sort          string `form:"sort"`
+	Order         string `form:"order"`
+	Cursor        string `form:"cursor"`
+	Limit         int    `form:"limit" binding:"min=0"`
+}
@@ -0,0 +1,45 @@
+package models
+
+// 家庭成员与角色的关系
+const (
+	RelationFather              = "father"
+	RelationMother              = "mother"
+	RelationOlderBrother        = "older_brother"
+	RelationOlderSister         = "older_sister"
+	RelationPaternalGrandfather = "paternal_grandfather"
+	RelationPaternalGrandmother = "paternal_grandmother"
+	RelationMaternalGrandfather = "maternal_grandfather"
+	RelationMaternalGrandmother = "maternal_grandmother"
+)
+
+// 家庭居住地类型
+const (
+	HomeRegionUrban = "urban"
+	HomeRegionTown  = "town"
+	HomeRegionRural = "rural"
+)
+
+// 家庭住房情况
+const (
+	HousingOwned  = "owned"
+	HousingRented = "rented"
+	HousingShared = "shared" // 与亲属合住
+)
+
+// Family 角色出生时的家庭情况
+type Family struct {
+	IncomeClass string          `json:"income_class"` // 与角色的 family_background 一致
+	HomeRegion  string          `json:"home_region"`
+	Housing     string          `json:"housing"`
+	Members     []*FamilyMember `json:"members"`
+}
+
+// FamilyMember 家庭成员，年龄与在世情况均以角色出生时为准
+type FamilyMember struct {
+	Relation   string `json:"relation" db:"relation"`
+	Gender     string `json:"gender" db:"gender"`
+	AgeAtBirth int    `json:"age_at_birth" db:"age_at_birth"`
+	IsAlive    bool   `json:"is_alive" db:"is_alive"`
+	Occupation string `json:"occupation,omitempty" db:"occupation"`
+	Closeness  int    `json:"closeness" db:"closeness"` // 与角色的亲密程度 0~100
+}
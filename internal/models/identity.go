@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// UserIdentity 第三方账户与用户的关联
+type UserIdentity struct {
+	IdentityID      uint64     `json:"identity_id" db:"identity_id"`
+	UserID          uint64     `json:"user_id" db:"user_id"`
+	Provider        string     `json:"provider" db:"provider"`
+	ProviderSubject string     `json:"-" db:"provider_subject"`
+	UnionID         string     `json:"-" db:"union_id"`
+	Email           string     `json:"email,omitempty" db:"email"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	LastUsedAt      *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+}
+
+// WeChatLoginRequest 微信小程序登录请求
+type WeChatLoginRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// OAuthState OAuth 授权请求上下文，回调时用于校验 state 并完成 PKCE
+type OAuthState struct {
+	Provider     string `json:"provider"`
+	CodeVerifier string `json:"code_verifier"`
+	Nonce        string `json:"nonce"`
+	Platform     string `json:"platform"`
+}
+
+// OAuthAuthorizeResponse 发起 OAuth 授权响应
+type OAuthAuthorizeResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+	ExpiresIn        int64  `json:"expires_in"`
+}
+
+// OAuthCallbackRequest OAuth 授权回调请求，支持查询参数与 JSON 两种形式
+type OAuthCallbackRequest struct {
+	Code  string `json:"code" form:"code" binding:"required"`
+	State string `json:"state" form:"state" binding:"required"`
+}
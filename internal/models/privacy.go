@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// 数据导出任务状态
+const (
+	ExportStatusPending = "pending"
+	ExportStatusReady   = "ready"
+	ExportStatusFailed  = "failed"
+)
+
+// DataExport 个人数据导出任务
+type DataExport struct {
+	ExportID     string     `json:"export_id"`
+	UserID       uint64     `json:"-"`
+	Status       string     `json:"status"`
+	BlobKey      string     `json:"-"`
+	SizeBytes    int64      `json:"size_bytes,omitempty"`
+	ErrorMessage string     `json:"error,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+}
+
+// DeleteAccountRequest 注销账户请求
+// 设置了密码的账户需提供密码，启用两步验证的账户还需提供验证码
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+// AccountDeletionResponse 注销申请结果
+type AccountDeletionResponse struct {
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
+}
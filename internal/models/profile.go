@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// BirthDateLayout 生日的日期格式
+const BirthDateLayout = "2006-01-02"
+
+// UserProfile 用户资料
+type UserProfile struct {
+	UserID        uint64     `json:"user_id"`
+	Username      string     `json:"username"`
+	Email         string     `json:"email,omitempty"`
+	EmailVerified bool       `json:"email_verified"`
+	IsGuest       bool       `json:"is_guest"`
+	AvatarURL     string     `json:"avatar_url,omitempty"`
+	Bio           string     `json:"bio"`
+	BirthDate     string     `json:"birth_date,omitempty"` // YYYY-MM-DD
+	Gender        string     `json:"gender,omitempty"`
+	Country       string     `json:"country,omitempty"`
+	Timezone      string     `json:"timezone"`
+	CreatedAt     time.Time  `json:"created_at"`
+	LastLogin     *time.Time `json:"last_login,omitempty"`
+}
+
+// UpdateProfileRequest 更新用户资料请求，未提供的字段保持不变，空字符串表示清空
+type UpdateProfileRequest struct {
+	Bio       *string `json:"bio" binding:"omitempty,max=500"`
+	BirthDate *string `json:"birth_date" binding:"omitempty,max=10"` // YYYY-MM-DD
+	Gender    *string `json:"gender" binding:"omitempty,max=20"`     // male | female | other
+	Country   *string `json:"country" binding:"omitempty,max=100"`
+	Timezone  *string `json:"timezone" binding:"omitempty,max=50"` // IANA 时区，如 Asia/Shanghai
+}
+
+// ToProfile 转换为用户资料
+func (u *User) ToProfile() UserProfile {
+	profile := UserProfile{
+		UserID:        u.UserID,
+		Username:      u.Username,
+		Email:         u.Email,
+		EmailVerified: u.EmailVerifiedAt != nil,
+		IsGuest:       u.IsGuest,
+		AvatarURL:     u.AvatarURL,
+		Bio:           u.Bio,
+		Gender:        u.Gender,
+		Country:       u.Country,
+		Timezone:      u.Timezone,
+		CreatedAt:     u.CreatedAt,
+		LastLogin:     u.LastLogin,
+	}
+	if u.BirthDate != nil {
+		profile.BirthDate = u.BirthDate.Format(BirthDateLayout)
+	}
+	return profile
+}
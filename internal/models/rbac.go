@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// 内置角色
+const (
+	RoleAdmin    = "admin"
+	RoleOperator = "operator"
+)
+
+// 权限名，格式为 资源:操作
+const (
+	PermissionAdminAccess  = "admin:access"
+	PermissionUsersRead    = "users:read"
+	PermissionUsersWrite   = "users:write"
+	PermissionRolesRead    = "roles:read"
+	PermissionRolesWrite   = "roles:write"
+	PermissionContentRead  = "content:read"
+	PermissionContentWrite = "content:write"
+	// PermissionCharactersDelete 彻底删除玩家角色，不可恢复
+	PermissionCharactersDelete = "characters:delete"
+)
+
+// Role 角色及其权限
+type Role struct {
+	RoleID      uint64    `json:"role_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AdminUserDetail 管理后台查看的用户详情
+type AdminUserDetail struct {
+	*User
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
+
+// AssignRoleRequest 授予角色请求
+type AssignRoleRequest struct {
+	Role string `json:"role" binding:"required,max=50"`
+}
+
+// UpdateUserStatusRequest 启用或禁用用户请求
+type UpdateUserStatusRequest struct {
+	IsActive *bool `json:"is_active" binding:"required"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Session 登录会话，一次登录对应一个会话，与刷新令牌家族一一对应
+type Session struct {
+	SessionID  string    `json:"session_id"`
+	UserID     uint64    `json:"-"`
+	Platform   string    `json:"platform"`
+	Version    string    `json:"version,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	IP         string    `json:"ip,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"` // 是否为发起请求的会话
+}
@@ -0,0 +1,122 @@
+package models
+
+import "math"
+
+// 技能
+const (
+	SkillAcademic = "academic"
+	SkillSocial   = "social"
+	SkillAthletic = "athletic"
+	SkillArtistic = "artistic"
+	SkillBusiness = "business"
+)
+
+// Skills 角色技能，各项取值 0~100，出生时为 0
+type Skills struct {
+	Academic int `json:"academic" db:"academic_skill"`
+	Social   int `json:"social" db:"social_skill"`
+	Athletic int `json:"athletic" db:"athletic_skill"`
+	Artistic int `json:"artistic" db:"artistic_skill"`
+	Business int `json:"business" db:"business_skill"`
+}
+
+// Get 获取指定技能的值，技能不存在时返回 false
+func (s *Skills) Get(skill string) (int, bool) {
+	switch skill {
+	case SkillAcademic:
+		return s.Academic, true
+	case SkillSocial:
+		return s.Social, true
+	case SkillAthletic:
+		return s.Athletic, true
+	case SkillArtistic:
+		return s.Artistic, true
+	case SkillBusiness:
+		return s.Business, true
+	}
+	return 0, false
+}
+
+// Set 设置指定技能的值，技能不存在时忽略
+func (s *Skills) Set(skill string, value int) {
+	switch skill {
+	case SkillAcademic:
+		s.Academic = value
+	case SkillSocial:
+		s.Social = value
+	case SkillAthletic:
+		s.Athletic = value
+	case SkillArtistic:
+		s.Artistic = value
+	case SkillBusiness:
+		s.Business = value
+	}
+}
+
+// CoreAttributes 产品文档中的五项基础属性，由角色表字段换算得出，不单独存储
+//
+//	intelligence = intelligence
+//	constitution = physical_fitness
+//	charisma     = (appearance + emotional_intelligence) / 2
+//	willpower    = (尽责性 + (100 - 神经质)) / 2，没有大五人格记录的角色为 50
+//	creativity   = imagination
+type CoreAttributes struct {
+	Intelligence int `json:"intelligence"`
+	Constitution int `json:"constitution"`
+	Charisma     int `json:"charisma"`
+	Willpower    int `json:"willpower"`
+	Creativity   int `json:"creativity"`
+}
+
+// CoreAttributes 按映射规则换算产品文档中的基础属性
+func (c *Character) CoreAttributes() CoreAttributes {
+	willpower := 50
+	if c.Personality != nil {
+		willpower = average(c.Personality.Conscientiousness, MaxAttributeValue-c.Personality.Neuroticism)
+	}
+	return CoreAttributes{
+		Intelligence: c.Intelligence,
+		Constitution: c.PhysicalFitness,
+		Charisma:     average(c.Appearance, c.EmotionalIntelligence),
+		Willpower:    willpower,
+		Creativity:   c.Imagination,
+	}
+}
+
+// SkillAptitude 角色对技能的天赋，即训练时依据的属性，取值 0~100
+//
+//	academic = (intelligence + memory) / 2
+//	social   = charisma
+//	athletic = constitution
+//	artistic = creativity
+//	business = (intelligence + emotional_intelligence) / 2
+func (c *Character) SkillAptitude(skill string) int {
+	switch skill {
+	case SkillAcademic:
+		return average(c.Intelligence, c.Memory)
+	case SkillSocial:
+		return average(c.Appearance, c.EmotionalIntelligence)
+	case SkillAthletic:
+		return c.PhysicalFitness
+	case SkillArtistic:
+		return c.Imagination
+	case SkillBusiness:
+		return average(c.Intelligence, c.EmotionalIntelligence)
+	}
+	return 0
+}
+
+// TrainSkillResponse 技能训练结果
+type TrainSkillResponse struct {
+	Skill  string  `json:"skill"`
+	Gain   int     `json:"gain"`
+	Value  int     `json:"value"`
+	Cap    int     `json:"cap"` // 当前年龄可达到的技能上限
+	Skills Skills  `json:"skills"`
+	Traits *Traits `json:"traits,omitempty"` // 训练后的性格倾向，每次训练会使性格略微变化
+}
+
+// average 两项属性的平均值，四舍五入
+func average(a, b int) int {
+	return int(math.Round(float64(a+b) / 2))
+}
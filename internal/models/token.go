@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// RefreshToken 刷新令牌记录（仅保存令牌哈希，不保存明文）
+// 同一次登录产生的刷新令牌共享同一个 FamilyID，轮换时沿用
+type RefreshToken struct {
+	TokenHash string    `json:"-"`
+	UserID    uint64    `json:"user_id"`
+	FamilyID  string    `json:"family_id"`
+	Platform  string    `json:"platform"`
+	IssuedAt  time.Time `json:"issued_at"`
+}
+
+// RefreshRequest 刷新令牌请求
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "math"
+
+// 性格倾向取值范围，负值与正值分别对应维度的两端
+const (
+	MinTraitScore = -1.0
+	MaxTraitScore = 1.0
+	// MaxTraitDrift 单次生活事件对每个维度的最大影响
+	MaxTraitDrift = 0.05
+)
+
+// Traits 角色性格倾向，各维度为 -1 ~ 1 的连续分值
+// 出生时由大五人格推导生成，之后随生活事件缓慢变化，供事件权重计算使用
+type Traits struct {
+	Extraversion float64 `json:"extraversion" db:"extraversion"` // -1 内向 ~ 1 外向
+	Optimism     float64 `json:"optimism" db:"optimism"`         // -1 悲观 ~ 1 乐观
+	Bravery      float64 `json:"bravery" db:"bravery"`           // -1 谨慎 ~ 1 勇敢
+}
+
+// TraitDelta 一次生活事件对性格倾向的影响，各分量超出 ±MaxTraitDrift 时按上限计
+// 分值越接近某一端，继续向该端变化越慢，因此分值不会超出取值范围
+type TraitDelta struct {
+	Extraversion float64 `json:"extraversion"`
+	Optimism     float64 `json:"optimism"`
+	Bravery      float64 `json:"bravery"`
+}
+
+// TraitWeight 按性格倾向调整事件权重
+// sensitivity 为事件对该维度的敏感度，正值表示倾向正端的角色更容易触发，结果不小于 0
+func TraitWeight(score, sensitivity float64) float64 {
+	return math.Max(0, 1+sensitivity*score)
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// UserTOTP 用户 TOTP 两步验证配置
+type UserTOTP struct {
+	UserID          uint64     `json:"user_id"`
+	SecretEncrypted string     `json:"-"`
+	EnabledAt       *time.Time `json:"enabled_at,omitempty"` // 为空表示尚未确认启用
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// TOTPSetupResponse TOTP 绑定信息，客户端据此生成二维码
+type TOTPSetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// TwoFactorCodeRequest 提交两步验证码的请求，code 可为 TOTP 验证码或恢复码
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required,max=32"`
+}
+
+// RecoveryCodesResponse 恢复码列表，仅在生成时返回一次
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorChallenge 登录需要两步验证时返回的挑战
+type TwoFactorChallenge struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+	ExpiresIn         int64  `json:"expires_in"`
+}
+
+// TwoFactorLoginRequest 完成两步验证登录请求
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required,max=32"`
+}
+
+// LoginChallengeState 登录挑战上下文
+type LoginChallengeState struct {
+	UserID uint64     `json:"user_id"`
+	Device DeviceInfo `json:"device"`
+}
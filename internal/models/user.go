@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// User 用户模型
+type User struct {
+	UserID          uint64     `json:"user_id" db:"user_id"`
+	Username        string     `json:"username" db:"username"`
+	Email           string     `json:"email" db:"email"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty" db:"email_verified_at"`
+	PasswordHash    string     `json:"-" db:"password_hash"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+	LastLogin       *time.Time `json:"last_login,omitempty" db:"last_login"`
+	LastLoginIP     string     `json:"last_login_ip,omitempty" db:"last_login_ip"`
+	FailedLogins    int        `json:"failed_login_count" db:"failed_login_count"`
+	LastFailedAt    *time.Time `json:"last_failed_login_at,omitempty" db:"last_failed_login_at"`
+	LastFailedIP    string     `json:"last_failed_login_ip,omitempty" db:"last_failed_login_ip"`
+	IsActive        bool       `json:"is_active" db:"is_active"`
+	DeletionAt      *time.Time `json:"deletion_scheduled_at,omitempty" db:"deletion_scheduled_at"`
+	IsGuest         bool       `json:"is_guest" db:"is_guest"`
+	DeviceID        string     `json:"-" db:"device_id"`
+	AvatarURL       string     `json:"avatar_url,omitempty" db:"avatar_url"`
+	Bio             string     `json:"bio,omitempty" db:"bio"`
+	BirthDate       *time.Time `json:"birth_date,omitempty" db:"birth_date"`
+	Gender          string     `json:"gender,omitempty" db:"gender"`
+	Country         string     `json:"country,omitempty" db:"country"`
+	Timezone        string     `json:"timezone,omitempty" db:"timezone"`
+}
+
+// UserInfo 返回给客户端的用户信息
+type UserInfo struct {
+	UserID        uint64     `json:"user_id"`
+	Username      string     `json:"username"`
+	Email         string     `json:"email,omitempty"`
+	EmailVerified bool       `json:"email_verified"`
+	IsGuest       bool       `json:"is_guest"`
+	AvatarURL     string     `json:"avatar_url,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	LastLogin     *time.Time `json:"last_login,omitempty"`
+	DeletionAt    *time.Time `json:"deletion_scheduled_at,omitempty"` // 已申请注销时为计划删除时间
+}
+
+// ToUserInfo 转换为对外暴露的用户信息
+func (u *User) ToUserInfo() UserInfo {
+	return UserInfo{
+		UserID:        u.UserID,
+		Username:      u.Username,
+		Email:         u.Email,
+		EmailVerified: u.EmailVerifiedAt != nil,
+		IsGuest:       u.IsGuest,
+		AvatarURL:     u.AvatarURL,
+		CreatedAt:     u.CreatedAt,
+		LastLogin:     u.LastLogin,
+		DeletionAt:    u.DeletionAt,
+	}
+}
@@ -0,0 +1,80 @@
+// Package pagination 提供基于游标的分页
+// 游标对客户端不透明，记录排序方式、筛选条件指纹以及上一页最后一条记录的排序值和主键，
+// 列表接口据此以 (排序值, 主键) 作为键集继续查询，翻页期间插入或删除记录不会导致重复或遗漏
+package pagination
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+)
+
+// 每页数量
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// ErrInvalidCursor 游标格式错误，或与当前排序方式、筛选条件不一致
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor 分页游标
+type Cursor struct {
+	Sort   string `json:"s"` // 排序字段
+	Desc   bool   `json:"d"` // 是否倒序
+	Filter string `json:"f"` // 筛选条件指纹，见 Fingerprint
+	Value  string `json:"v"` // 上一页最后一条记录的排序值，由各列表接口自行编码
+	ID     string `json:"i"` // 上一页最后一条记录的主键
+}
+
+// Encode 编码为不透明字符串
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode 解析游标，并校验其排序方式和筛选条件指纹与本次请求一致
+// 换用其他筛选条件继续翻页会得到错误的结果，因此直接拒绝
+func Decode(raw, sort string, desc bool, filter string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.Sort != sort || cursor.Desc != desc || cursor.Filter != filter || cursor.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// Fingerprint 计算规范化后筛选条件的指纹，参数顺序需固定，未指定的条件传空字符串
+func Fingerprint(values ...string) string {
+	// 按 JSON 数组编码，避免不同条件拼接后相同
+	data, _ := json.Marshal(values)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Page 一页数据
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"` // 为空表示没有更多数据
+	HasMore    bool   `json:"has_more"`
+}
+
+// Limit 规范化每页数量，未指定时取默认值，超过上限时取上限
+func Limit(limit int) int {
+	if limit <= 0 {
+		return DefaultLimit
+	}
+	if limit > MaxLimit {
+		return MaxLimit
+	}
+	return limit
+}
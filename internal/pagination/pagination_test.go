@@ -0,0 +1,82 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	filter := Fingerprint("active", "CN", "1990", "", "", "")
+	cursor := &Cursor{
+		Sort:   "money",
+		Desc:   true,
+		Filter: filter,
+		Value:  "1200",
+		ID:     "6f1c2a52-8a8e-4a5b-9a57-0c6f0b7c3d21",
+	}
+
+	decoded, err := Decode(cursor.Encode(), "money", true, filter)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if *decoded != *cursor {
+		t.Errorf("Decode() = %+v, want %+v", *decoded, *cursor)
+	}
+}
+
+func TestDecodeRejectsMismatch(t *testing.T) {
+	filter := Fingerprint("", "CN", "", "", "", "")
+	raw := (&Cursor{Sort: "created_at", Desc: true, Filter: filter, Value: "2026-01-02T03:04:05Z", ID: "id"}).Encode()
+
+	tests := []struct {
+		name   string
+		raw    string
+		sort   string
+		desc   bool
+		filter string
+	}{
+		{"different sort", raw, "money", true, filter},
+		{"different order", raw, "created_at", false, filter},
+		{"different filter", raw, "created_at", true, Fingerprint("", "US", "", "", "", "")},
+		{"not base64", "%%%", "created_at", true, filter},
+		{"not json", base64.RawURLEncoding.EncodeToString([]byte("cursor")), "created_at", true, filter},
+		{"missing id", (&Cursor{Sort: "created_at", Desc: true, Filter: filter}).Encode(), "created_at", true, filter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode(tt.raw, tt.sort, tt.desc, tt.filter); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("Decode() error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	if Fingerprint("a", "b") != Fingerprint("a", "b") {
+		t.Error("Fingerprint() is not deterministic")
+	}
+	if Fingerprint("ab", "") == Fingerprint("a", "b") {
+		t.Error("Fingerprint() collides when values are shifted between fields")
+	}
+	if Fingerprint("", "1990") == Fingerprint("1990", "") {
+		t.Error("Fingerprint() ignores field order")
+	}
+}
+
+func TestLimit(t *testing.T) {
+	tests := []struct {
+		in, want int
+	}{
+		{0, DefaultLimit},
+		{-5, DefaultLimit},
+		{1, 1},
+		{MaxLimit, MaxLimit},
+		{MaxLimit + 1, MaxLimit},
+	}
+	for _, tt := range tests {
+		if got := Limit(tt.in); got != tt.want {
+			t.Errorf("Limit(%d) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
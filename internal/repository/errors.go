@@ -0,0 +1,17 @@
+package repository
+
+import "errors"
+
+// 数据访问层通用错误
+var (
+	// ErrNotFound 记录不存在
+	ErrNotFound = errors.New("record not found")
+	// ErrDuplicateUsername 用户名已存在
+	ErrDuplicateUsername = errors.New("username already exists")
+	// ErrDuplicateEmail 邮箱已存在
+	ErrDuplicateEmail = errors.New("email already exists")
+	// ErrDuplicateDeviceID 设备ID已绑定其他游客账户
+	ErrDuplicateDeviceID = errors.New("device id already exists")
+	// ErrDuplicateIdentity 第三方账户已关联其他用户
+	ErrDuplicateIdentity = errors.New("identity already exists")
+)
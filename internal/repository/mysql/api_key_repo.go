@@ -0,0 +1,159 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xuchengvcc/restart-life-api/internal/database"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository"
+)
+
+// apiKeyColumns API 密钥表查询字段
+const apiKeyColumns = `key_id, user_id, name, prefix, key_hash, scopes, rate_limit, last_used_at, last_used_ip, expires_at, revoked_at, created_at`
+
+// APIKeyRepository API 密钥数据访问
+type APIKeyRepository struct {
+	db *database.MySQLDB
+}
+
+// NewAPIKeyRepository 创建 API 密钥数据访问对象
+func NewAPIKeyRepository(db *database.MySQLDB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// Create 创建 API 密钥
+func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, rate_limit, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		key.UserID, key.Name, key.Prefix, key.KeyHash, strings.Join(key.Scopes, " "),
+		key.RateLimit, key.ExpiresAt, key.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get api key id: %w", err)
+	}
+	key.KeyID = uint64(id)
+	return nil
+}
+
+// GetByHash 按密钥摘要获取 API 密钥
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+apiKeyColumns+` FROM api_keys WHERE key_hash = ?`, keyHash)
+	return scanAPIKey(row)
+}
+
+// ListByUser 列出用户未吊销的 API 密钥
+func (r *APIKeyRepository) ListByUser(ctx context.Context, userID uint64) ([]*models.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+apiKeyColumns+` FROM api_keys WHERE user_id = ? AND revoked_at IS NULL
+		ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make([]*models.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// CountActiveByUser 统计用户未吊销且未过期的 API 密钥数量
+func (r *APIKeyRepository) CountActiveByUser(ctx context.Context, userID uint64, now time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM api_keys
+		WHERE user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)`,
+		userID, now,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count api keys: %w", err)
+	}
+	return count, nil
+}
+
+// Revoke 吊销用户的 API 密钥，密钥不存在、不属于该用户或已吊销时返回 ErrNotFound
+func (r *APIKeyRepository) Revoke(ctx context.Context, userID, keyID uint64, revokedAt time.Time) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE api_keys SET revoked_at = ? WHERE key_id = ? AND user_id = ? AND revoked_at IS NULL`,
+		revokedAt, keyID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// TouchLastUsed 更新最后使用时间和IP，距上次记录不足 minInterval 时跳过，避免每个请求都写库
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, keyID uint64, usedAt time.Time, ip string, minInterval time.Duration) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE api_keys SET last_used_at = ?, last_used_ip = ?
+		WHERE key_id = ? AND (last_used_at IS NULL OR last_used_at < ? OR NOT (last_used_ip <=> ?))`,
+		usedAt, nullString(ip), keyID, usedAt.Add(-minInterval), nullString(ip),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update api key last used: %w", err)
+	}
+	return nil
+}
+
+// scanAPIKey 扫描 API 密钥记录
+func scanAPIKey(row rowScanner) (*models.APIKey, error) {
+	var (
+		key                              models.APIKey
+		scopes                           string
+		lastUsedIP                       sql.NullString
+		lastUsedAt, expiresAt, revokedAt sql.NullTime
+	)
+
+	err := row.Scan(
+		&key.KeyID, &key.UserID, &key.Name, &key.Prefix, &key.KeyHash, &scopes, &key.RateLimit,
+		&lastUsedAt, &lastUsedIP, &expiresAt, &revokedAt, &key.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to scan api key: %w", err)
+	}
+
+	key.Scopes = strings.Fields(scopes)
+	key.LastUsedIP = lastUsedIP.String
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if expiresAt.Valid {
+		key.ExpiresAt = &expiresAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return &key, nil
+}
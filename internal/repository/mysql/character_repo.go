@@ -0,0 +1,565 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuchengvcc/restart-life-api/internal/database"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/pagination"
+	"github.com/xuchengvcc/restart-life-api/internal/repository"
+)
+
+// characterColumns 角色表查询字段
+const characterColumns = `character_id, user_id, character_name, birth_country, birth_year, current_age, gender, race,
+	is_active, archived_at, created_at, updated_at,
+	generation_seed, generator_version, height_cm, weight_kg, family_background, personality,
+	intelligence, emotional_intelligence, memory, imagination, physical_fitness, appearance,
+	academic_skill, social_skill, athletic_skill, artistic_skill, business_skill,
+	life_stage, current_status, happiness_level, health_level, money,
+	current_location, current_activity,
+	total_playtime, game_completed, final_age, death_cause`
+
+// CharacterRepository 角色数据访问，所有查询均按所属用户过滤
+type CharacterRepository struct {
+	db *database.MySQLDB
+}
+
+// NewCharacterRepository 创建角色数据访问对象
+func NewCharacterRepository(db *database.MySQLDB) *CharacterRepository {
+	return &CharacterRepository{db: db}
+}
+
+// Create 创建角色及其家庭和性格倾向，CharacterID 由调用方生成
+func (r *CharacterRepository) Create(ctx context.Context, character *models.Character) (err error) {
+	personality, err := marshalPersonality(character.Personality)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO characters (
+			character_id, user_id, character_name, birth_country, birth_year, current_age, gender, race, is_active,
+			generation_seed, generator_version, height_cm, weight_kg, family_background, personality,
+			intelligence, emotional_intelligence, memory, imagination, physical_fitness, appearance,
+			life_stage, current_status, happiness_level, health_level, money
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		character.CharacterID, character.UserID, character.Name, character.BirthCountry, character.BirthYear,
+		character.CurrentAge, character.Gender, character.Race, character.IsActive,
+		character.Seed, nullInt(character.GeneratorVersion), nullFloat(character.HeightCM), nullFloat(character.WeightKG),
+		nullString(character.FamilyBackground), personality,
+		character.Intelligence, character.EmotionalIntelligence, character.Memory,
+		character.Imagination, character.PhysicalFitness, character.Appearance,
+		character.LifeStage, character.CurrentStatus, character.HappinessLevel, character.HealthLevel, character.Money,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create character: %w", err)
+	}
+
+	if character.Family != nil {
+		if err = insertFamily(ctx, tx, character.CharacterID, character.Family); err != nil {
+			return err
+		}
+	}
+	if character.Traits != nil {
+		if _, err = tx.ExecContext(ctx,
+			`INSERT INTO character_traits (character_id, extraversion, optimism, bravery) VALUES (?, ?, ?, ?)`,
+			character.CharacterID, character.Traits.Extraversion, character.Traits.Optimism, character.Traits.Bravery,
+		); err != nil {
+			return fmt.Errorf("failed to create character traits: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetByID 获取用户的角色，角色不存在或不属于该用户时返回 ErrNotFound
+func (r *CharacterRepository) GetByID(ctx context.Context, userID uint64, characterID string) (*models.Character, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+characterColumns+` FROM characters WHERE character_id = ? AND user_id = ?`,
+		characterID, userID)
+	return scanCharacter(row)
+}
+
+// characterStatusFilters 角色列表状态 -> 查询条件，空状态表示全部未归档角色
+var characterStatusFilters = map[string]string{
+	"":                              "archived_at IS NULL",
+	models.CharacterStatusActive:    "archived_at IS NULL AND game_completed = FALSE",
+	models.CharacterStatusCompleted: "archived_at IS NULL AND game_completed = TRUE",
+	models.CharacterStatusArchived:  "archived_at IS NOT NULL",
+}
+
+// characterSort 角色列表排序方式，expr 需与索引定义一致才能走索引
+type characterSort struct {
+	expr   string
+	value  func(*models.Character) string
+	decode func(string) (interface{}, error)
+}
+
+// characterSorts 排序字段 -> 排序方式，均以角色ID作为次级排序保证顺序稳定
+var characterSorts = map[string]characterSort{
+	models.CharacterSortCreatedAt: {
+		expr: "created_at",
+		value: func(c *models.Character) string {
+			return c.CreatedAt.UTC().Format(time.RFC3339Nano)
+		},
+		decode: func(v string) (interface{}, error) {
+			return time.Parse(time.RFC3339Nano, v)
+		},
+	},
+	// 人生未结束的角色 final_age 为 NULL，按 -1 参与排序和比较
+	models.CharacterSortFinalAge: {
+		expr: "COALESCE(final_age, -1)",
+		value: func(c *models.Character) string {
+			if c.FinalAge == nil {
+				return "-1"
+			}
+			return strconv.Itoa(*c.FinalAge)
+		},
+		decode: func(v string) (interface{}, error) {
+			return strconv.Atoi(v)
+		},
+	},
+	models.CharacterSortMoney: {
+		expr: "money",
+		value: func(c *models.Character) string {
+			return strconv.Itoa(c.Money)
+		},
+		decode: func(v string) (interface{}, error) {
+			return strconv.Atoi(v)
+		},
+	},
+}
+
+// ListByUser 按查询条件分页列出用户的角色，after 为上一页的游标
+// query 的状态、排序字段和方向需已由调用方校验；还有下一页时返回下一页的游标
+func (r *CharacterRepository) ListByUser(ctx context.Context, userID uint64, query *models.CharacterListQuery, after *pagination.Cursor, limit int) ([]*models.Character, *pagination.Cursor, error) {
+	filter, ok := characterStatusFilters[query.Status]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown character status %q", query.Status)
+	}
+	sort, ok := characterSorts[query.Sort]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown character sort %q", query.Sort)
+	}
+	desc := query.Order == "desc"
+
+	conditions := []string{"user_id = ?", filter}
+	args := []interface{}{userID}
+	if query.BirthCountry != "" {
+		conditions = append(conditions, "birth_country = ?")
+		args = append(args, query.BirthCountry)
+	}
+	if query.BirthYearMin != nil {
+		conditions = append(conditions, "birth_year >= ?")
+		args = append(args, *query.BirthYearMin)
+	}
+	if query.BirthYearMax != nil {
+		conditions = append(conditions, "birth_year <= ?")
+		args = append(args, *query.BirthYearMax)
+	}
+	if query.GameCompleted != nil {
+		conditions = append(conditions, "game_completed = ?")
+		args = append(args, *query.GameCompleted)
+	}
+	if query.LifeStage != "" {
+		conditions = append(conditions, "life_stage = ?")
+		args = append(args, query.LifeStage)
+	}
+
+	op, order := ">", "ASC"
+	if desc {
+		op, order = "<", "DESC"
+	}
+	if after != nil {
+		value, err := sort.decode(after.Value)
+		if err != nil {
+			return nil, nil, pagination.ErrInvalidCursor
+		}
+		conditions = append(conditions,
+			fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND character_id %[2]s ?))", sort.expr, op))
+		args = append(args, value, value, after.ID)
+	}
+
+	// 多取一条判断是否还有下一页
+	args = append(args, limit+1)
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+characterColumns+` FROM characters WHERE `+strings.Join(conditions, " AND ")+`
+		ORDER BY `+sort.expr+` `+order+`, character_id `+order+` LIMIT ?`,
+		args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list characters: %w", err)
+	}
+	defer rows.Close()
+
+	characters := make([]*models.Character, 0, limit)
+	for rows.Next() {
+		character, err := scanCharacter(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		characters = append(characters, character)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to list characters: %w", err)
+	}
+
+	if len(characters) <= limit {
+		return characters, nil, nil
+	}
+	characters = characters[:limit]
+	last := characters[limit-1]
+	return characters, &pagination.Cursor{
+		Sort:  query.Sort,
+		Desc:  desc,
+		Value: sort.value(last),
+		ID:    last.CharacterID,
+	}, nil
+}
+
+// UpdateDetails 更新角色展示类字段，角色不存在或不属于该用户时返回 ErrNotFound
+func (r *CharacterRepository) UpdateDetails(ctx context.Context, character *models.Character) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE characters SET character_name = ?, current_location = ?, current_activity = ?
+		WHERE character_id = ? AND user_id = ?`,
+		character.Name, nullString(character.CurrentLocation), nullString(character.CurrentActivity),
+		character.CharacterID, character.UserID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update character: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update character: %w", err)
+	}
+	if affected == 0 {
+		// 字段未变化时影响行数同样为 0，需区分角色是否存在
+		if _, err := r.GetByID(ctx, character.UserID, character.CharacterID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Archive 归档用户的角色，角色不存在、不属于该用户或已归档时返回 ErrNotFound
+func (r *CharacterRepository) Archive(ctx context.Context, userID uint64, characterID string, archivedAt time.Time) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE characters SET archived_at = ?, is_active = FALSE
+		WHERE character_id = ? AND user_id = ? AND archived_at IS NULL`,
+		archivedAt, characterID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to archive character: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to archive character: %w", err)
+	}
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// Restore 恢复在 archivedAfter 之后归档的角色，角色不存在、未归档或已超过保留期时返回 ErrNotFound
+func (r *CharacterRepository) Restore(ctx context.Context, userID uint64, characterID string, archivedAfter time.Time) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE characters SET archived_at = NULL, is_active = TRUE
+		WHERE character_id = ? AND user_id = ? AND archived_at IS NOT NULL AND archived_at > ?`,
+		characterID, userID, archivedAfter,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to restore character: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to restore character: %w", err)
+	}
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// PurgeArchived 彻底删除在 before 之前归档的角色，每次最多删除 limit 个，返回删除数量
+// 家庭、性格等关联数据通过外键级联删除
+func (r *CharacterRepository) PurgeArchived(ctx context.Context, before time.Time, limit int) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM characters WHERE archived_at IS NOT NULL AND archived_at <= ? ORDER BY archived_at LIMIT ?`,
+		before, limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge archived characters: %w", err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge archived characters: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteByID 彻底删除角色，不限所属用户，供管理后台使用；角色不存在时返回 ErrNotFound
+func (r *CharacterRepository) DeleteByID(ctx context.Context, characterID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM characters WHERE character_id = ?`, characterID)
+	if err != nil {
+		return fmt.Errorf("failed to delete character: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete character: %w", err)
+	}
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// skillColumns 技能 -> 列名
+var skillColumns = map[string]string{
+	models.SkillAcademic: "academic_skill",
+	models.SkillSocial:   "social_skill",
+	models.SkillAthletic: "athletic_skill",
+	models.SkillArtistic: "artistic_skill",
+	models.SkillBusiness: "business_skill",
+}
+
+// UpdateSkill 在技能仍为 from 时将其更新为 to，返回是否更新成功
+// 技能已被并发修改时返回 false，角色不存在或不属于该用户时返回 ErrNotFound
+func (r *CharacterRepository) UpdateSkill(ctx context.Context, userID uint64, characterID, skill string, from, to int) (bool, error) {
+	column, ok := skillColumns[skill]
+	if !ok {
+		return false, fmt.Errorf("unknown skill %q", skill)
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE characters SET `+column+` = ? WHERE character_id = ? AND user_id = ? AND `+column+` = ?`,
+		to, characterID, userID, from,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to update character skill: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to update character skill: %w", err)
+	}
+	if affected == 0 {
+		if _, err := r.GetByID(ctx, userID, characterID); err != nil {
+			return false, err
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+// GetFamily 获取角色的家庭，不含家庭经济状况；早期创建的角色没有家庭记录，返回 ErrNotFound
+// 调用方需先确认角色属于当前用户
+func (r *CharacterRepository) GetFamily(ctx context.Context, characterID string) (*models.Family, error) {
+	family := &models.Family{}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT home_region, housing FROM character_households WHERE character_id = ?`, characterID,
+	).Scan(&family.HomeRegion, &family.Housing)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get character household: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT relation, gender, age_at_birth, is_alive, occupation, closeness
+		FROM character_family_members WHERE character_id = ? ORDER BY member_id`, characterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list family members: %w", err)
+	}
+	defer rows.Close()
+
+	family.Members = make([]*models.FamilyMember, 0)
+	for rows.Next() {
+		var member models.FamilyMember
+		var occupation sql.NullString
+		if err := rows.Scan(&member.Relation, &member.Gender, &member.AgeAtBirth, &member.IsAlive,
+			&occupation, &member.Closeness); err != nil {
+			return nil, fmt.Errorf("failed to scan family member: %w", err)
+		}
+		member.Occupation = occupation.String
+		family.Members = append(family.Members, &member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list family members: %w", err)
+	}
+	return family, nil
+}
+
+// GetTraits 获取角色的性格倾向，调用方需先确认角色属于当前用户
+func (r *CharacterRepository) GetTraits(ctx context.Context, characterID string) (*models.Traits, error) {
+	var traits models.Traits
+	err := r.db.QueryRowContext(ctx,
+		`SELECT extraversion, optimism, bravery FROM character_traits WHERE character_id = ?`, characterID,
+	).Scan(&traits.Extraversion, &traits.Optimism, &traits.Bravery)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get character traits: %w", err)
+	}
+	return &traits, nil
+}
+
+// DriftTraits 原子地调整用户角色的性格倾向，角色不存在或不属于该用户时返回 ErrNotFound
+// 每个维度按 x + d·(1 - x·sign(d)) 变化：越接近目标端变化越慢，|d| <= 1 时结果不会超出 -1 ~ 1
+func (r *CharacterRepository) DriftTraits(ctx context.Context, userID uint64, characterID string, delta models.TraitDelta) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE character_traits t JOIN characters c ON c.character_id = t.character_id
+		SET t.extraversion = t.extraversion + ? * (1 - t.extraversion * SIGN(?)),
+			t.optimism = t.optimism + ? * (1 - t.optimism * SIGN(?)),
+			t.bravery = t.bravery + ? * (1 - t.bravery * SIGN(?))
+		WHERE t.character_id = ? AND c.user_id = ?`,
+		delta.Extraversion, delta.Extraversion,
+		delta.Optimism, delta.Optimism,
+		delta.Bravery, delta.Bravery,
+		characterID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update character traits: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update character traits: %w", err)
+	}
+	if affected == 0 {
+		// 分值未变化时影响行数同样为 0，需区分角色是否存在
+		if _, err := r.GetByID(ctx, userID, characterID); err != nil {
+			return err
+		}
+		if _, err := r.GetTraits(ctx, characterID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertFamily 插入角色的家庭及成员，成员按顺序写入以保持展示顺序
+func insertFamily(ctx context.Context, db execer, characterID string, family *models.Family) error {
+	if _, err := db.ExecContext(ctx,
+		`INSERT INTO character_households (character_id, home_region, housing) VALUES (?, ?, ?)`,
+		characterID, family.HomeRegion, family.Housing,
+	); err != nil {
+		return fmt.Errorf("failed to create character household: %w", err)
+	}
+	if len(family.Members) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(family.Members))
+	args := make([]interface{}, 0, len(family.Members)*7)
+	for _, member := range family.Members {
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?)")
+		args = append(args, characterID, member.Relation, member.Gender, member.AgeAtBirth,
+			member.IsAlive, nullString(member.Occupation), member.Closeness)
+	}
+	if _, err := db.ExecContext(ctx,
+		`INSERT INTO character_family_members
+			(character_id, relation, gender, age_at_birth, is_alive, occupation, closeness)
+		VALUES `+strings.Join(placeholders, ", "),
+		args...,
+	); err != nil {
+		return fmt.Errorf("failed to create family members: %w", err)
+	}
+	return nil
+}
+
+// scanCharacter 扫描角色记录
+func scanCharacter(row rowScanner) (*models.Character, error) {
+	var (
+		character                               models.Character
+		currentLocation, currentActivity, cause sql.NullString
+		familyBackground                        sql.NullString
+		finalAge, seed, generatorVersion        sql.NullInt64
+		heightCM, weightKG                      sql.NullFloat64
+		archivedAt                              sql.NullTime
+		personality                             []byte
+	)
+
+	err := row.Scan(
+		&character.CharacterID, &character.UserID, &character.Name, &character.BirthCountry, &character.BirthYear,
+		&character.CurrentAge, &character.Gender, &character.Race,
+		&character.IsActive, &archivedAt, &character.CreatedAt, &character.UpdatedAt,
+		&seed, &generatorVersion, &heightCM, &weightKG, &familyBackground, &personality,
+		&character.Intelligence, &character.EmotionalIntelligence, &character.Memory,
+		&character.Imagination, &character.PhysicalFitness, &character.Appearance,
+		&character.Skills.Academic, &character.Skills.Social, &character.Skills.Athletic,
+		&character.Skills.Artistic, &character.Skills.Business,
+		&character.LifeStage, &character.CurrentStatus, &character.HappinessLevel, &character.HealthLevel, &character.Money,
+		&currentLocation, &currentActivity,
+		&character.TotalPlaytime, &character.GameCompleted, &finalAge, &cause,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to scan character: %w", err)
+	}
+
+	character.CurrentLocation = currentLocation.String
+	character.CurrentActivity = currentActivity.String
+	character.DeathCause = cause.String
+	if finalAge.Valid {
+		age := int(finalAge.Int64)
+		character.FinalAge = &age
+	}
+	if seed.Valid {
+		character.Seed = &seed.Int64
+	}
+	if archivedAt.Valid {
+		character.ArchivedAt = &archivedAt.Time
+	}
+	character.GeneratorVersion = int(generatorVersion.Int64)
+	character.HeightCM = heightCM.Float64
+	character.WeightKG = weightKG.Float64
+	character.FamilyBackground = familyBackground.String
+	if len(personality) > 0 {
+		character.Personality = &models.Personality{}
+		if err := json.Unmarshal(personality, character.Personality); err != nil {
+			return nil, fmt.Errorf("failed to decode character personality: %w", err)
+		}
+	}
+	character.Attributes = character.CoreAttributes()
+	return &character, nil
+}
+
+// marshalPersonality 编码性格，nil 写入为 NULL
+func marshalPersonality(personality *models.Personality) (interface{}, error) {
+	if personality == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(personality)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode character personality: %w", err)
+	}
+	return string(data), nil
+}
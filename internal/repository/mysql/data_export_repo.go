@@ -0,0 +1,264 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/xuchengvcc/restart-life-api/internal/database"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository"
+)
+
+// dataExportColumns 数据导出任务表查询字段
+const dataExportColumns = `export_id, user_id, status, blob_key, size_bytes, error_message, created_at, completed_at, expires_at`
+
+// exportTable 个人数据导出包含的表
+type exportTable struct {
+	Name  string          // 导出文件名
+	Query string          // 以用户ID为唯一参数的查询
+	Omit  map[string]bool // 不导出的敏感字段
+}
+
+// userExportTables 个人数据导出范围
+// 新增保存用户或角色数据的表时需在此登记；角色下的表通过 JOIN characters 按用户过滤
+var userExportTables = []exportTable{
+	{
+		Name:  "user",
+		Query: `SELECT * FROM users WHERE user_id = ?`,
+		Omit:  map[string]bool{"password_hash": true},
+	},
+	{
+		Name:  "identities",
+		Query: `SELECT provider, provider_subject, union_id, email, created_at, last_used_at FROM user_identities WHERE user_id = ?`,
+	},
+	{
+		Name: "roles",
+		Query: `SELECT r.name AS role, ur.granted_at FROM user_roles ur
+			JOIN roles r ON r.role_id = ur.role_id WHERE ur.user_id = ?`,
+	},
+	{
+		Name:  "two_factor",
+		Query: `SELECT enabled_at, created_at FROM user_totp WHERE user_id = ?`,
+	},
+	{
+		Name: "api_keys",
+		Query: `SELECT name, prefix, scopes, rate_limit, last_used_at, last_used_ip, expires_at, revoked_at, created_at
+			FROM api_keys WHERE user_id = ?`,
+	},
+	{
+		Name:  "characters",
+		Query: `SELECT * FROM characters WHERE user_id = ?`,
+	},
+	{
+		Name: "character_households",
+		Query: `SELECT h.* FROM character_households h
+			JOIN characters c ON c.character_id = h.character_id WHERE c.user_id = ?`,
+	},
+	{
+		Name: "character_family_members",
+		Query: `SELECT m.* FROM character_family_members m
+			JOIN characters c ON c.character_id = m.character_id WHERE c.user_id = ?`,
+	},
+	{
+		Name: "character_traits",
+		Query: `SELECT t.* FROM character_traits t
+			JOIN characters c ON c.character_id = t.character_id WHERE c.user_id = ?`,
+	},
+}
+
+// ExportedTable 导出的一张表
+type ExportedTable struct {
+	Name string
+	Rows []map[string]interface{}
+}
+
+// DataExportRepository 个人数据导出数据访问
+type DataExportRepository struct {
+	db *database.MySQLDB
+}
+
+// NewDataExportRepository 创建个人数据导出数据访问对象
+func NewDataExportRepository(db *database.MySQLDB) *DataExportRepository {
+	return &DataExportRepository{db: db}
+}
+
+// Create 创建导出任务
+func (r *DataExportRepository) Create(ctx context.Context, export *models.DataExport) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO data_exports (export_id, user_id, status) VALUES (?, ?, ?)`,
+		export.ExportID, export.UserID, export.Status,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create data export: %w", err)
+	}
+	return nil
+}
+
+// Get 获取导出任务
+func (r *DataExportRepository) Get(ctx context.Context, exportID string) (*models.DataExport, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+dataExportColumns+` FROM data_exports WHERE export_id = ?`, exportID)
+	return scanDataExport(row)
+}
+
+// GetLatestByUser 获取用户最近一次导出任务
+func (r *DataExportRepository) GetLatestByUser(ctx context.Context, userID uint64) (*models.DataExport, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+dataExportColumns+` FROM data_exports WHERE user_id = ? ORDER BY created_at DESC LIMIT 1`, userID)
+	return scanDataExport(row)
+}
+
+// MarkReady 标记导出完成
+func (r *DataExportRepository) MarkReady(ctx context.Context, exportID, blobKey string, size int64, completedAt, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE data_exports SET status = ?, blob_key = ?, size_bytes = ?, completed_at = ?, expires_at = ?
+		WHERE export_id = ?`,
+		models.ExportStatusReady, blobKey, size, completedAt, expiresAt, exportID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark data export ready: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed 标记导出失败
+func (r *DataExportRepository) MarkFailed(ctx context.Context, exportID, message string, completedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE data_exports SET status = ?, error_message = ?, completed_at = ? WHERE export_id = ?`,
+		models.ExportStatusFailed, message, completedAt, exportID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark data export failed: %w", err)
+	}
+	return nil
+}
+
+// ListExpired 列出已过期的导出任务
+func (r *DataExportRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]*models.DataExport, error) {
+	return r.list(ctx,
+		`SELECT `+dataExportColumns+` FROM data_exports WHERE expires_at IS NOT NULL AND expires_at <= ?
+		ORDER BY expires_at LIMIT ?`,
+		before, limit)
+}
+
+// ListByUser 列出用户的全部导出任务
+func (r *DataExportRepository) ListByUser(ctx context.Context, userID uint64) ([]*models.DataExport, error) {
+	return r.list(ctx,
+		`SELECT `+dataExportColumns+` FROM data_exports WHERE user_id = ? ORDER BY created_at`, userID)
+}
+
+// Delete 删除导出任务记录
+func (r *DataExportRepository) Delete(ctx context.Context, exportID string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM data_exports WHERE export_id = ?`, exportID); err != nil {
+		return fmt.Errorf("failed to delete data export: %w", err)
+	}
+	return nil
+}
+
+// DumpUserData 导出用户的全部个人数据，按表返回原始行
+func (r *DataExportRepository) DumpUserData(ctx context.Context, userID uint64) ([]ExportedTable, error) {
+	tables := make([]ExportedTable, 0, len(userExportTables))
+	for _, table := range userExportTables {
+		rows, err := r.dumpRows(ctx, table, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s: %w", table.Name, err)
+		}
+		tables = append(tables, ExportedTable{Name: table.Name, Rows: rows})
+	}
+	return tables, nil
+}
+
+// dumpRows 将查询结果转换为 列名 -> 值 的行
+func (r *DataExportRepository) dumpRows(ctx context.Context, table exportTable, userID uint64) ([]map[string]interface{}, error) {
+	rows, err := r.db.QueryContext(ctx, table.Query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0)
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			if table.Omit[column] {
+				continue
+			}
+			if b, ok := values[i].([]byte); ok {
+				row[column] = string(b)
+			} else {
+				row[column] = values[i]
+			}
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// list 查询导出任务列表
+func (r *DataExportRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.DataExport, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list data exports: %w", err)
+	}
+	defer rows.Close()
+
+	var exports []*models.DataExport
+	for rows.Next() {
+		export, err := scanDataExport(rows)
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, export)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list data exports: %w", err)
+	}
+	return exports, nil
+}
+
+// scanDataExport 扫描导出任务记录
+func scanDataExport(row rowScanner) (*models.DataExport, error) {
+	var (
+		export                 models.DataExport
+		blobKey, errorMessage  sql.NullString
+		completedAt, expiresAt sql.NullTime
+	)
+
+	err := row.Scan(
+		&export.ExportID, &export.UserID, &export.Status, &blobKey, &export.SizeBytes,
+		&errorMessage, &export.CreatedAt, &completedAt, &expiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to scan data export: %w", err)
+	}
+
+	export.BlobKey = blobKey.String
+	export.ErrorMessage = errorMessage.String
+	if completedAt.Valid {
+		export.CompletedAt = &completedAt.Time
+	}
+	if expiresAt.Valid {
+		export.ExpiresAt = &expiresAt.Time
+	}
+	return &export, nil
+}
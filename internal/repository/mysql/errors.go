@@ -0,0 +1,21 @@
+package mysql
+
+import (
+	"errors"
+	"strings"
+
+	driver "github.com/go-sql-driver/mysql"
+)
+
+// mysqlErrDuplicateEntry MySQL 唯一键冲突错误码
+const mysqlErrDuplicateEntry = 1062
+
+// isDuplicateKeyError 判断是否为指定唯一键的冲突错误
+// MySQL 8 的错误信息形如 "Duplicate entry 'x' for key 'users.username'"
+func isDuplicateKeyError(err error, key string) bool {
+	var mysqlErr *driver.MySQLError
+	if !errors.As(err, &mysqlErr) || mysqlErr.Number != mysqlErrDuplicateEntry {
+		return false
+	}
+	return key == "" || strings.Contains(mysqlErr.Message, key+"'")
+}
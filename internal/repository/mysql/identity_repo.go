@@ -0,0 +1,156 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/xuchengvcc/restart-life-api/internal/database"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository"
+)
+
+// identityColumns 第三方账户关联表查询字段
+const identityColumns = `identity_id, user_id, provider, provider_subject, union_id, email, created_at, last_used_at`
+
+// IdentityRepository 第三方账户关联数据访问
+type IdentityRepository struct {
+	db *database.MySQLDB
+}
+
+// NewIdentityRepository 创建第三方账户关联数据访问对象
+func NewIdentityRepository(db *database.MySQLDB) *IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+// GetBySubject 根据提供方和提供方用户标识查找关联
+func (r *IdentityRepository) GetBySubject(ctx context.Context, provider, subject string) (*models.UserIdentity, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+identityColumns+` FROM user_identities WHERE provider = ? AND provider_subject = ?`,
+		provider, subject)
+	return scanIdentity(row)
+}
+
+// GetByUnionID 根据跨应用统一标识查找任一关联
+func (r *IdentityRepository) GetByUnionID(ctx context.Context, provider, unionID string) (*models.UserIdentity, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+identityColumns+` FROM user_identities WHERE provider = ? AND union_id = ?
+		ORDER BY identity_id LIMIT 1`,
+		provider, unionID)
+	return scanIdentity(row)
+}
+
+// Create 为已有用户创建第三方账户关联
+func (r *IdentityRepository) Create(ctx context.Context, identity *models.UserIdentity) error {
+	id, err := insertIdentity(ctx, r.db.DB, identity)
+	if err != nil {
+		return err
+	}
+	identity.IdentityID = id
+	return nil
+}
+
+// CreateWithUser 在同一事务中创建用户及其第三方账户关联
+func (r *IdentityRepository) CreateWithUser(ctx context.Context, user *models.User, identity *models.UserIdentity) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO users (username, email, password_hash, is_active, is_guest) VALUES (?, ?, '', ?, FALSE)`,
+		user.Username, nullString(user.Email), user.IsActive,
+	)
+	if err != nil {
+		return mapUserWriteError(err)
+	}
+	userID, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get user id: %w", err)
+	}
+
+	identity.UserID = uint64(userID)
+	identityID, err := insertIdentity(ctx, tx, identity)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	user.UserID = uint64(userID)
+	identity.IdentityID = identityID
+	return nil
+}
+
+// TouchLastUsed 更新关联的最近使用时间
+func (r *IdentityRepository) TouchLastUsed(ctx context.Context, identityID uint64, usedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE user_identities SET last_used_at = ? WHERE identity_id = ?`, usedAt, identityID)
+	if err != nil {
+		return fmt.Errorf("failed to update identity last used: %w", err)
+	}
+	return nil
+}
+
+// execer 兼容 *sql.DB 与 *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// insertIdentity 插入第三方账户关联
+func insertIdentity(ctx context.Context, db execer, identity *models.UserIdentity) (uint64, error) {
+	result, err := db.ExecContext(ctx,
+		`INSERT INTO user_identities (user_id, provider, provider_subject, union_id, email) VALUES (?, ?, ?, ?, ?)`,
+		identity.UserID, identity.Provider, identity.ProviderSubject,
+		nullString(identity.UnionID), nullString(identity.Email),
+	)
+	if err != nil {
+		if isDuplicateKeyError(err, "uk_user_identities_provider_subject") {
+			return 0, repository.ErrDuplicateIdentity
+		}
+		return 0, fmt.Errorf("failed to insert identity: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get identity id: %w", err)
+	}
+	return uint64(id), nil
+}
+
+// scanIdentity 扫描第三方账户关联记录
+func scanIdentity(row rowScanner) (*models.UserIdentity, error) {
+	var (
+		identity       models.UserIdentity
+		unionID, email sql.NullString
+		lastUsedAt     sql.NullTime
+	)
+
+	err := row.Scan(
+		&identity.IdentityID, &identity.UserID, &identity.Provider, &identity.ProviderSubject,
+		&unionID, &email, &identity.CreatedAt, &lastUsedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to scan identity: %w", err)
+	}
+
+	identity.UnionID = unionID.String
+	identity.Email = email.String
+	if lastUsedAt.Valid {
+		identity.LastUsedAt = &lastUsedAt.Time
+	}
+
+	return &identity, nil
+}
@@ -0,0 +1,156 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/xuchengvcc/restart-life-api/internal/database"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository"
+)
+
+// RoleRepository 角色与权限数据访问
+type RoleRepository struct {
+	db *database.MySQLDB
+}
+
+// NewRoleRepository 创建角色与权限数据访问对象
+func NewRoleRepository(db *database.MySQLDB) *RoleRepository {
+	return &RoleRepository{db: db}
+}
+
+// ListRoles 列出全部角色及其权限
+func (r *RoleRepository) ListRoles(ctx context.Context) ([]*models.Role, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT r.role_id, r.name, r.description, r.created_at, p.name
+		FROM roles r
+		LEFT JOIN role_permissions rp ON rp.role_id = r.role_id
+		LEFT JOIN permissions p ON p.permission_id = rp.permission_id
+		ORDER BY r.role_id, p.name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	defer rows.Close()
+
+	roles := make([]*models.Role, 0)
+	var current *models.Role
+	for rows.Next() {
+		var (
+			role        models.Role
+			description sql.NullString
+			permission  sql.NullString
+		)
+		if err := rows.Scan(&role.RoleID, &role.Name, &description, &role.CreatedAt, &permission); err != nil {
+			return nil, fmt.Errorf("failed to scan role: %w", err)
+		}
+
+		if current == nil || current.RoleID != role.RoleID {
+			role.Description = description.String
+			role.Permissions = make([]string, 0)
+			current = &role
+			roles = append(roles, current)
+		}
+		if permission.Valid {
+			current.Permissions = append(current.Permissions, permission.String)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	return roles, nil
+}
+
+// GetUserRoles 获取用户拥有的角色名
+func (r *RoleRepository) GetUserRoles(ctx context.Context, userID uint64) ([]string, error) {
+	return r.queryNames(ctx,
+		`SELECT r.name FROM user_roles ur
+		JOIN roles r ON r.role_id = ur.role_id
+		WHERE ur.user_id = ?
+		ORDER BY r.name`, userID)
+}
+
+// GetUserPermissions 获取用户通过角色获得的全部权限名
+func (r *RoleRepository) GetUserPermissions(ctx context.Context, userID uint64) ([]string, error) {
+	return r.queryNames(ctx,
+		`SELECT DISTINCT p.name FROM user_roles ur
+		JOIN role_permissions rp ON rp.role_id = ur.role_id
+		JOIN permissions p ON p.permission_id = rp.permission_id
+		WHERE ur.user_id = ?
+		ORDER BY p.name`, userID)
+}
+
+// AssignRole 为用户授予角色，已拥有该角色时不做修改
+func (r *RoleRepository) AssignRole(ctx context.Context, userID uint64, roleName string, grantedBy uint64) error {
+	roleID, err := r.getRoleID(ctx, roleName)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx,
+		`INSERT IGNORE INTO user_roles (user_id, role_id, granted_by) VALUES (?, ?, ?)`,
+		userID, roleID, grantedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to assign role: %w", err)
+	}
+	return nil
+}
+
+// RevokeRole 撤销用户的角色，用户未拥有该角色时返回 ErrNotFound
+func (r *RoleRepository) RevokeRole(ctx context.Context, userID uint64, roleName string) error {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE ur FROM user_roles ur
+		JOIN roles r ON r.role_id = ur.role_id
+		WHERE ur.user_id = ? AND r.name = ?`,
+		userID, roleName,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke role: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to revoke role: %w", err)
+	}
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// getRoleID 根据角色名获取角色ID
+func (r *RoleRepository) getRoleID(ctx context.Context, roleName string) (uint64, error) {
+	var roleID uint64
+	err := r.db.QueryRowContext(ctx, `SELECT role_id FROM roles WHERE name = ?`, roleName).Scan(&roleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, repository.ErrNotFound
+		}
+		return 0, fmt.Errorf("failed to get role: %w", err)
+	}
+	return roleID, nil
+}
+
+// queryNames 查询单列字符串结果
+func (r *RoleRepository) queryNames(ctx context.Context, query string, args ...interface{}) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query names: %w", err)
+	}
+	defer rows.Close()
+
+	names := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan name: %w", err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query names: %w", err)
+	}
+	return names, nil
+}
@@ -0,0 +1,23 @@
+package mysql
+
+import "database/sql"
+
+// rowScanner 兼容 *sql.Row 与 *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// nullString 空字符串写入为 NULL
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// nullInt 零值写入为 NULL
+func nullInt(value int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(value), Valid: value != 0}
+}
+
+// nullFloat 零值写入为 NULL
+func nullFloat(value float64) sql.NullFloat64 {
+	return sql.NullFloat64{Float64: value, Valid: value != 0}
+}
@@ -0,0 +1,184 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/xuchengvcc/restart-life-api/internal/database"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository"
+)
+
+// TOTPRepository 两步验证数据访问
+type TOTPRepository struct {
+	db *database.MySQLDB
+}
+
+// NewTOTPRepository 创建两步验证数据访问对象
+func NewTOTPRepository(db *database.MySQLDB) *TOTPRepository {
+	return &TOTPRepository{db: db}
+}
+
+// Get 获取用户的 TOTP 配置
+func (r *TOTPRepository) Get(ctx context.Context, userID uint64) (*models.UserTOTP, error) {
+	var (
+		totp      models.UserTOTP
+		enabledAt sql.NullTime
+	)
+
+	err := r.db.QueryRowContext(ctx,
+		`SELECT user_id, secret_encrypted, enabled_at, created_at FROM user_totp WHERE user_id = ?`, userID,
+	).Scan(&totp.UserID, &totp.SecretEncrypted, &enabledAt, &totp.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get totp: %w", err)
+	}
+
+	if enabledAt.Valid {
+		totp.EnabledAt = &enabledAt.Time
+	}
+	return &totp, nil
+}
+
+// SavePending 保存待确认的 TOTP 密钥，覆盖之前未确认的密钥
+// 已启用的配置不会被覆盖，此时返回 ErrNotFound
+func (r *TOTPRepository) SavePending(ctx context.Context, userID uint64, secretEncrypted string) error {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO user_totp (user_id, secret_encrypted) VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE secret_encrypted = IF(enabled_at IS NULL, VALUES(secret_encrypted), secret_encrypted)`,
+		userID, secretEncrypted,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save totp secret: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to save totp secret: %w", err)
+	}
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// Enable 启用 TOTP 并替换全部恢复码
+func (r *TOTPRepository) Enable(ctx context.Context, userID uint64, recoveryCodeHashes []string, enabledAt time.Time) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE user_totp SET enabled_at = ? WHERE user_id = ? AND enabled_at IS NULL`, enabledAt, userID)
+	if err != nil {
+		return fmt.Errorf("failed to enable totp: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to enable totp: %w", err)
+	}
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+
+	if err = replaceRecoveryCodes(ctx, tx, userID, recoveryCodeHashes); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ReplaceRecoveryCodes 重新生成恢复码，旧恢复码全部作废
+func (r *TOTPRepository) ReplaceRecoveryCodes(ctx context.Context, userID uint64, recoveryCodeHashes []string) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = replaceRecoveryCodes(ctx, tx, userID, recoveryCodeHashes); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// UseRecoveryCode 消费一个未使用的恢复码，恢复码不存在或已使用时返回 ErrNotFound
+func (r *TOTPRepository) UseRecoveryCode(ctx context.Context, userID uint64, codeHash string, usedAt time.Time) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE user_recovery_codes SET used_at = ? WHERE user_id = ? AND code_hash = ? AND used_at IS NULL`,
+		usedAt, userID, codeHash,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to use recovery code: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// Delete 删除用户的 TOTP 配置及全部恢复码
+func (r *TOTPRepository) Delete(ctx context.Context, userID uint64) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, `DELETE FROM user_totp WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete totp: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// replaceRecoveryCodes 在事务中替换用户的恢复码
+func replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userID uint64, codeHashes []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	for _, hash := range codeHashes {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO user_recovery_codes (user_id, code_hash) VALUES (?, ?)`, userID, hash); err != nil {
+			return fmt.Errorf("failed to insert recovery code: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,358 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/xuchengvcc/restart-life-api/internal/database"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository"
+)
+
+// userColumns 用户表查询字段
+const userColumns = `user_id, username, email, email_verified_at, password_hash, created_at, updated_at, last_login,
+	last_login_ip, failed_login_count, last_failed_login_at, last_failed_login_ip, is_active, deletion_scheduled_at, is_guest, device_id, avatar_url, bio, birth_date, gender, country, timezone`
+
+// UserRepository 用户数据访问
+type UserRepository struct {
+	db *database.MySQLDB
+}
+
+// NewUserRepository 创建用户数据访问对象
+func NewUserRepository(db *database.MySQLDB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// Create 创建用户，成功后回填 UserID
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO users (username, email, password_hash, is_active, is_guest, device_id)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		user.Username, nullString(user.Email), user.PasswordHash, user.IsActive,
+		user.IsGuest, nullString(user.DeviceID),
+	)
+	if err != nil {
+		return mapUserWriteError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get user id: %w", err)
+	}
+	user.UserID = uint64(id)
+
+	return nil
+}
+
+// GetByID 根据ID获取用户
+func (r *UserRepository) GetByID(ctx context.Context, userID uint64) (*models.User, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+userColumns+` FROM users WHERE user_id = ?`, userID)
+	return scanUser(row)
+}
+
+// GetByUsername 根据用户名获取用户
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+userColumns+` FROM users WHERE username = ?`, username)
+	return scanUser(row)
+}
+
+// GetByEmail 根据邮箱获取用户
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+userColumns+` FROM users WHERE email = ?`, email)
+	return scanUser(row)
+}
+
+// GetByDeviceID 根据绑定的设备ID获取游客用户
+func (r *UserRepository) GetByDeviceID(ctx context.Context, deviceID string) (*models.User, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+userColumns+` FROM users WHERE device_id = ?`, deviceID)
+	return scanUser(row)
+}
+
+// UpgradeGuest 为游客账户绑定邮箱和密码，转为正式账户并解除设备绑定
+// user_id 保持不变，游客名下的角色全部保留
+func (r *UserRepository) UpgradeGuest(ctx context.Context, userID uint64, username, email, passwordHash string) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE users SET username = ?, email = ?, password_hash = ?, is_guest = FALSE, device_id = NULL
+		WHERE user_id = ? AND is_guest = TRUE`,
+		username, email, passwordHash, userID,
+	)
+	if err != nil {
+		return mapUserWriteError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to upgrade guest: %w", err)
+	}
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateLastLogin 记录成功登录的时间和IP，并清零连续失败次数
+func (r *UserRepository) UpdateLastLogin(ctx context.Context, userID uint64, loginAt time.Time, ip string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE users SET last_login = ?, last_login_ip = ?, failed_login_count = 0 WHERE user_id = ?`,
+		loginAt, nullString(ip), userID)
+	if err != nil {
+		return fmt.Errorf("failed to update last login: %w", err)
+	}
+	return nil
+}
+
+// RecordFailedLogin 记录一次失败登录
+func (r *UserRepository) RecordFailedLogin(ctx context.Context, userID uint64, failedAt time.Time, ip string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE users SET failed_login_count = failed_login_count + 1, last_failed_login_at = ?, last_failed_login_ip = ?
+		WHERE user_id = ?`,
+		failedAt, nullString(ip), userID)
+	if err != nil {
+		return fmt.Errorf("failed to record failed login: %w", err)
+	}
+	return nil
+}
+
+// MarkEmailVerified 将邮箱标记为已验证
+// 仅当用户当前邮箱仍为 email 时生效，防止验证邮件发出后邮箱被修改
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, userID uint64, email string, verifiedAt time.Time) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE users SET email_verified_at = ? WHERE user_id = ? AND email = ?`,
+		verifiedAt, userID, email,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark email verified: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to mark email verified: %w", err)
+	}
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// UpdatePassword 更新密码哈希
+func (r *UserRepository) UpdatePassword(ctx context.Context, userID uint64, passwordHash string) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE users SET password_hash = ? WHERE user_id = ?`, passwordHash, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// SetActive 启用或禁用用户
+func (r *UserRepository) SetActive(ctx context.Context, userID uint64, active bool) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE users SET is_active = ? WHERE user_id = ?`, active, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update user status: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update user status: %w", err)
+	}
+	if affected == 0 {
+		// 状态未变化时影响行数同样为 0，需区分用户是否存在
+		if _, err := r.GetByID(ctx, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateProfile 更新用户资料字段
+func (r *UserRepository) UpdateProfile(ctx context.Context, user *models.User) error {
+	var birthDate sql.NullTime
+	if user.BirthDate != nil {
+		birthDate = sql.NullTime{Time: *user.BirthDate, Valid: true}
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE users SET bio = ?, birth_date = ?, gender = ?, country = ?, timezone = ? WHERE user_id = ?`,
+		nullString(user.Bio), birthDate, nullString(user.Gender), nullString(user.Country), user.Timezone, user.UserID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update profile: %w", err)
+	}
+	return nil
+}
+
+// UpdateAvatar 更新头像地址，avatarURL 为空时清除头像
+func (r *UserRepository) UpdateAvatar(ctx context.Context, userID uint64, avatarURL string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE users SET avatar_url = ? WHERE user_id = ?`, nullString(avatarURL), userID)
+	if err != nil {
+		return fmt.Errorf("failed to update avatar: %w", err)
+	}
+	return nil
+}
+
+// ScheduleDeletion 设置账户的计划删除时间
+func (r *UserRepository) ScheduleDeletion(ctx context.Context, userID uint64, at time.Time) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE users SET deletion_scheduled_at = ? WHERE user_id = ? AND deletion_scheduled_at IS NULL`, at, userID)
+	if err != nil {
+		return fmt.Errorf("failed to schedule deletion: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to schedule deletion: %w", err)
+	}
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// CancelDeletion 撤销账户注销申请
+func (r *UserRepository) CancelDeletion(ctx context.Context, userID uint64) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE users SET deletion_scheduled_at = NULL WHERE user_id = ? AND deletion_scheduled_at IS NOT NULL`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to cancel deletion: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to cancel deletion: %w", err)
+	}
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// ListDueForDeletion 列出计划删除时间已到的用户
+func (r *UserRepository) ListDueForDeletion(ctx context.Context, before time.Time, limit int) ([]*models.User, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+userColumns+` FROM users
+		WHERE deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?
+		ORDER BY deletion_scheduled_at LIMIT ?`,
+		before, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users due for deletion: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list users due for deletion: %w", err)
+	}
+	return users, nil
+}
+
+// DeleteScheduled 删除计划删除时间已到的用户，关联数据通过外键级联删除
+// 用户在此之前撤销了注销申请时返回 ErrNotFound
+func (r *UserRepository) DeleteScheduled(ctx context.Context, userID uint64, before time.Time) error {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM users WHERE user_id = ? AND deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?`,
+		userID, before)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// scanUser 扫描用户记录
+func scanUser(row rowScanner) (*models.User, error) {
+	var (
+		user                                      models.User
+		emailVerifiedAt, lastLogin, birthDate     sql.NullTime
+		lastFailedAt, deletionAt                  sql.NullTime
+		email, deviceID                           sql.NullString
+		lastLoginIP, lastFailedIP                 sql.NullString
+		avatarURL, bio, gender, country, timezone sql.NullString
+	)
+
+	err := row.Scan(
+		&user.UserID, &user.Username, &email, &emailVerifiedAt, &user.PasswordHash,
+		&user.CreatedAt, &user.UpdatedAt, &lastLogin,
+		&lastLoginIP, &user.FailedLogins, &lastFailedAt, &lastFailedIP, &user.IsActive, &deletionAt,
+		&user.IsGuest, &deviceID,
+		&avatarURL, &bio, &birthDate, &gender, &country, &timezone,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to scan user: %w", err)
+	}
+
+	if emailVerifiedAt.Valid {
+		user.EmailVerifiedAt = &emailVerifiedAt.Time
+	}
+	if lastLogin.Valid {
+		user.LastLogin = &lastLogin.Time
+	}
+	if lastFailedAt.Valid {
+		user.LastFailedAt = &lastFailedAt.Time
+	}
+	if deletionAt.Valid {
+		user.DeletionAt = &deletionAt.Time
+	}
+	if birthDate.Valid {
+		user.BirthDate = &birthDate.Time
+	}
+	user.Email = email.String
+	user.LastLoginIP = lastLoginIP.String
+	user.LastFailedIP = lastFailedIP.String
+	user.DeviceID = deviceID.String
+	user.AvatarURL = avatarURL.String
+	user.Bio = bio.String
+	user.Gender = gender.String
+	user.Country = country.String
+	user.Timezone = timezone.String
+
+	return &user, nil
+}
+
+// mapUserWriteError 将唯一键冲突转换为仓储层错误
+func mapUserWriteError(err error) error {
+	switch {
+	case isDuplicateKeyError(err, "username"):
+		return repository.ErrDuplicateUsername
+	case isDuplicateKeyError(err, "email"):
+		return repository.ErrDuplicateEmail
+	case isDuplicateKeyError(err, "uk_users_device_id"):
+		return repository.ErrDuplicateDeviceID
+	default:
+		return fmt.Errorf("failed to write user: %w", err)
+	}
+}
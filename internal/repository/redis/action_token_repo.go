@@ -0,0 +1,52 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/xuchengvcc/restart-life-api/internal/database"
+)
+
+// 一次性操作令牌相关键命名
+const (
+	usedActionTokenKey = "auth:used_action_token:%s" // 令牌ID -> 已使用标记
+	actionMailCooldown = "auth:action_mail:%s:%d"    // 用途 + 用户 -> 邮件发送冷却
+)
+
+// ActionMailCooldownPattern 用户全部用途的邮件发送冷却的键模式，用于删除账户时清理
+func ActionMailCooldownPattern(userID uint64) string {
+	return fmt.Sprintf(actionMailCooldown, "*", userID)
+}
+
+// ActionTokenRepository 一次性操作令牌使用记录
+type ActionTokenRepository struct {
+	db *database.RedisDB
+}
+
+// NewActionTokenRepository 创建一次性操作令牌使用记录存储
+func NewActionTokenRepository(db *database.RedisDB) *ActionTokenRepository {
+	return &ActionTokenRepository{db: db}
+}
+
+// MarkUsed 原子地标记令牌已使用，返回是否为首次使用
+// 标记只需保留到令牌过期为止
+func (r *ActionTokenRepository) MarkUsed(ctx context.Context, tokenID string, ttl time.Duration) (bool, error) {
+	if ttl <= 0 {
+		ttl = time.Second
+	}
+	ok, err := r.db.Client.SetNX(ctx, fmt.Sprintf(usedActionTokenKey, tokenID), 1, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to mark action token used: %w", err)
+	}
+	return ok, nil
+}
+
+// AcquireMailCooldown 尝试占用邮件发送冷却期，冷却期内重复发送返回 false
+func (r *ActionTokenRepository) AcquireMailCooldown(ctx context.Context, purpose string, userID uint64, cooldown time.Duration) (bool, error) {
+	ok, err := r.db.Client.SetNX(ctx, fmt.Sprintf(actionMailCooldown, purpose, userID), 1, cooldown).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire mail cooldown: %w", err)
+	}
+	return ok, nil
+}
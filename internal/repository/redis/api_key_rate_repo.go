@@ -0,0 +1,36 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/xuchengvcc/restart-life-api/internal/database"
+)
+
+// apiKeyRateKey API 密钥限流计数键，%d 依次为密钥ID和窗口起始时间（Unix 秒）
+const apiKeyRateKey = "auth:api_key_rate:%d:%d"
+
+// APIKeyRateRepository API 密钥固定窗口限流计数
+type APIKeyRateRepository struct {
+	db *database.RedisDB
+}
+
+// NewAPIKeyRateRepository 创建 API 密钥限流计数存储
+func NewAPIKeyRateRepository(db *database.RedisDB) *APIKeyRateRepository {
+	return &APIKeyRateRepository{db: db}
+}
+
+// Hit 记录一次请求，返回当前窗口内的请求数和窗口剩余时间
+func (r *APIKeyRateRepository) Hit(ctx context.Context, keyID uint64, now time.Time, window time.Duration) (int64, time.Duration, error) {
+	start := now.Truncate(window)
+	key := fmt.Sprintf(apiKeyRateKey, keyID, start.Unix())
+
+	pipe := r.db.Client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, 0, fmt.Errorf("failed to record api key request: %w", err)
+	}
+	return incr.Val(), start.Add(window).Sub(now), nil
+}
@@ -0,0 +1,130 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/xuchengvcc/restart-life-api/internal/database"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository"
+)
+
+// characterDraftKey 角色草稿，%d 为用户ID，%s 为草稿ID
+// 哈希字段 state 保存草稿 JSON，rerolls 为已重新生成次数
+const characterDraftKey = "character:draft:%d:%s"
+
+// CharacterDraftPattern 用户全部角色草稿的键模式，用于删除账户时清理
+func CharacterDraftPattern(userID uint64) string {
+	return fmt.Sprintf(characterDraftKey, userID, "*")
+}
+
+// CharacterDraftRepository 角色草稿存储
+type CharacterDraftRepository struct {
+	db *database.RedisDB
+}
+
+// NewCharacterDraftRepository 创建角色草稿存储
+func NewCharacterDraftRepository(db *database.RedisDB) *CharacterDraftRepository {
+	return &CharacterDraftRepository{db: db}
+}
+
+// Save 保存草稿内容，草稿在 ExpiresAt 时过期，不影响已记录的重新生成次数
+func (r *CharacterDraftRepository) Save(ctx context.Context, userID uint64, draft *models.CharacterDraft) error {
+	data, err := json.Marshal(draft)
+	if err != nil {
+		return fmt.Errorf("failed to marshal character draft: %w", err)
+	}
+
+	key := fmt.Sprintf(characterDraftKey, userID, draft.DraftID)
+	pipe := r.db.Client.TxPipeline()
+	pipe.HSet(ctx, key, "state", data)
+	pipe.ExpireAt(ctx, key, draft.ExpiresAt)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save character draft: %w", err)
+	}
+	return nil
+}
+
+// Get 获取草稿，不存在或已过期时返回 ErrNotFound
+func (r *CharacterDraftRepository) Get(ctx context.Context, userID uint64, draftID string) (*models.CharacterDraft, error) {
+	values, err := r.db.HGetAll(ctx, fmt.Sprintf(characterDraftKey, userID, draftID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get character draft: %w", err)
+	}
+	return decodeCharacterDraft(values)
+}
+
+// IncrRerolls 原子地累加重新生成次数，返回累加前的草稿内容和累加后的次数
+func (r *CharacterDraftRepository) IncrRerolls(ctx context.Context, userID uint64, draftID string) (*models.CharacterDraft, int64, error) {
+	key := fmt.Sprintf(characterDraftKey, userID, draftID)
+
+	pipe := r.db.Client.TxPipeline()
+	get := pipe.HGet(ctx, key, "state")
+	incr := pipe.HIncrBy(ctx, key, "rerolls", 1)
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, goredis.Nil) {
+		return nil, 0, fmt.Errorf("failed to increment character draft rerolls: %w", err)
+	}
+
+	data, err := get.Bytes()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			// HIncrBy 会创建空记录，立即清理
+			r.db.Del(ctx, key)
+			return nil, 0, repository.ErrNotFound
+		}
+		return nil, 0, fmt.Errorf("failed to read character draft: %w", err)
+	}
+
+	var draft models.CharacterDraft
+	if err := json.Unmarshal(data, &draft); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal character draft: %w", err)
+	}
+	return &draft, incr.Val(), nil
+}
+
+// Consume 取出并删除草稿，保证每个草稿只能确认一次
+func (r *CharacterDraftRepository) Consume(ctx context.Context, userID uint64, draftID string) (*models.CharacterDraft, error) {
+	key := fmt.Sprintf(characterDraftKey, userID, draftID)
+
+	pipe := r.db.Client.TxPipeline()
+	get := pipe.HGetAll(ctx, key)
+	pipe.Del(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to consume character draft: %w", err)
+	}
+	return decodeCharacterDraft(get.Val())
+}
+
+// Delete 删除草稿，返回草稿是否存在
+func (r *CharacterDraftRepository) Delete(ctx context.Context, userID uint64, draftID string) (bool, error) {
+	count, err := r.db.Del(ctx, fmt.Sprintf(characterDraftKey, userID, draftID))
+	if err != nil {
+		return false, fmt.Errorf("failed to delete character draft: %w", err)
+	}
+	return count > 0, nil
+}
+
+// decodeCharacterDraft 解析草稿哈希
+func decodeCharacterDraft(values map[string]string) (*models.CharacterDraft, error) {
+	data, ok := values["state"]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+
+	var draft models.CharacterDraft
+	if err := json.Unmarshal([]byte(data), &draft); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal character draft: %w", err)
+	}
+	if raw, ok := values["rerolls"]; ok {
+		rerolls, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid character draft rerolls: %w", err)
+		}
+		draft.Rerolls = rerolls
+	}
+	return &draft, nil
+}
@@ -0,0 +1,75 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/xuchengvcc/restart-life-api/internal/database"
+)
+
+// 登录失败计数相关键命名，%s 为限流对象，如 account:user:42、ip:203.0.113.5
+const (
+	loginFailuresKey = "auth:login_failures:%s" // 限流对象 -> 窗口内连续失败次数
+	loginLockKey     = "auth:login_lock:%s"     // 限流对象 -> 临时锁定标记
+)
+
+// userLoginSubject 已存在用户的限流对象，%d 为用户ID
+const userLoginSubject = "account:user:%d"
+
+// UserLoginSubject 返回已存在用户的限流对象，用户名和邮箱登录共用同一计数
+func UserLoginSubject(userID uint64) string {
+	return fmt.Sprintf(userLoginSubject, userID)
+}
+
+// LoginAttemptRepository 登录失败计数与临时锁定
+type LoginAttemptRepository struct {
+	db *database.RedisDB
+}
+
+// NewLoginAttemptRepository 创建登录失败计数存储
+func NewLoginAttemptRepository(db *database.RedisDB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+// IncrFailures 失败次数加一并返回当前次数，每次失败都会重置计数窗口
+func (r *LoginAttemptRepository) IncrFailures(ctx context.Context, subject string, window time.Duration) (int64, error) {
+	key := fmt.Sprintf(loginFailuresKey, subject)
+
+	pipe := r.db.Client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to record login failure: %w", err)
+	}
+	return incr.Val(), nil
+}
+
+// Lock 临时锁定限流对象
+func (r *LoginAttemptRepository) Lock(ctx context.Context, subject string, duration time.Duration) error {
+	if err := r.db.Set(ctx, fmt.Sprintf(loginLockKey, subject), 1, duration); err != nil {
+		return fmt.Errorf("failed to lock login: %w", err)
+	}
+	return nil
+}
+
+// LockRemaining 返回剩余锁定时间，未锁定时返回 0
+func (r *LoginAttemptRepository) LockRemaining(ctx context.Context, subject string) (time.Duration, error) {
+	ttl, err := r.db.Client.PTTL(ctx, fmt.Sprintf(loginLockKey, subject)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get login lock: %w", err)
+	}
+	if ttl < 0 {
+		// -2 键不存在，-1 未设置过期时间（不应出现）
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// Reset 清除限流对象的失败计数和锁定
+func (r *LoginAttemptRepository) Reset(ctx context.Context, subject string) error {
+	if _, err := r.db.Del(ctx, fmt.Sprintf(loginFailuresKey, subject), fmt.Sprintf(loginLockKey, subject)); err != nil {
+		return fmt.Errorf("failed to reset login failures: %w", err)
+	}
+	return nil
+}
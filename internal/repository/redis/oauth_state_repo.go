@@ -0,0 +1,65 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/xuchengvcc/restart-life-api/internal/database"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository"
+)
+
+// oauthStateKey 授权请求 state -> 授权上下文
+const oauthStateKey = "auth:oauth_state:%s"
+
+// OAuthStateRepository OAuth 授权请求上下文存储
+type OAuthStateRepository struct {
+	db *database.RedisDB
+}
+
+// NewOAuthStateRepository 创建 OAuth 授权请求上下文存储
+func NewOAuthStateRepository(db *database.RedisDB) *OAuthStateRepository {
+	return &OAuthStateRepository{db: db}
+}
+
+// Save 保存授权请求上下文
+func (r *OAuthStateRepository) Save(ctx context.Context, state string, record *models.OAuthState, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal oauth state: %w", err)
+	}
+	if err := r.db.Set(ctx, fmt.Sprintf(oauthStateKey, state), data, ttl); err != nil {
+		return fmt.Errorf("failed to save oauth state: %w", err)
+	}
+	return nil
+}
+
+// Consume 取出并删除授权请求上下文，保证每个 state 只能使用一次
+func (r *OAuthStateRepository) Consume(ctx context.Context, state string) (*models.OAuthState, error) {
+	key := fmt.Sprintf(oauthStateKey, state)
+
+	pipe := r.db.Client.TxPipeline()
+	get := pipe.Get(ctx, key)
+	pipe.Del(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, goredis.Nil) {
+		return nil, fmt.Errorf("failed to consume oauth state: %w", err)
+	}
+
+	data, err := get.Bytes()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to read oauth state: %w", err)
+	}
+
+	var record models.OAuthState
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal oauth state: %w", err)
+	}
+	return &record, nil
+}
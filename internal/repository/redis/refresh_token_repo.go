@@ -0,0 +1,147 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/xuchengvcc/restart-life-api/internal/database"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository"
+)
+
+// 刷新令牌相关键命名
+const (
+	refreshTokenKey        = "auth:refresh:%s"               // 令牌哈希 -> 令牌记录
+	refreshFamilyKey       = "auth:refresh_family:%s"        // 令牌家族（登录会话） -> 家族状态与设备信息
+	userRefreshFamiliesKey = "auth:user_refresh_families:%d" // 用户 -> 令牌家族集合
+)
+
+// RefreshTokenRepository 刷新令牌存储
+type RefreshTokenRepository struct {
+	db *database.RedisDB
+}
+
+// NewRefreshTokenRepository 创建刷新令牌存储
+func NewRefreshTokenRepository(db *database.RedisDB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+// Save 保存刷新令牌，并将其设为所属家族的当前令牌
+func (r *RefreshTokenRepository) Save(ctx context.Context, token *models.RefreshToken, ttl time.Duration) error {
+	tokenKey := fmt.Sprintf(refreshTokenKey, token.TokenHash)
+	familyKey := fmt.Sprintf(refreshFamilyKey, token.FamilyID)
+
+	pipe := r.db.Client.TxPipeline()
+	pipe.HSet(ctx, tokenKey,
+		"user_id", token.UserID,
+		"family_id", token.FamilyID,
+		"platform", token.Platform,
+		"issued_at", token.IssuedAt.Unix(),
+		"rotated", 0,
+	)
+	pipe.Expire(ctx, tokenKey, ttl)
+	pipe.HSet(ctx, familyKey,
+		"user_id", token.UserID,
+		"current", token.TokenHash,
+	)
+	pipe.Expire(ctx, familyKey, ttl)
+	userFamiliesKey := fmt.Sprintf(userRefreshFamiliesKey, token.UserID)
+	pipe.SAdd(ctx, userFamiliesKey, token.FamilyID)
+	pipe.Expire(ctx, userFamiliesKey, ttl)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save refresh token: %w", err)
+	}
+	return nil
+}
+
+// Get 根据令牌哈希获取刷新令牌记录
+func (r *RefreshTokenRepository) Get(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	values, err := r.db.HGetAll(ctx, fmt.Sprintf(refreshTokenKey, tokenHash))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	if len(values) == 0 {
+		return nil, repository.ErrNotFound
+	}
+
+	userID, err := strconv.ParseUint(values["user_id"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid refresh token record: %w", err)
+	}
+	issuedAt, _ := strconv.ParseInt(values["issued_at"], 10, 64)
+
+	return &models.RefreshToken{
+		TokenHash: tokenHash,
+		UserID:    userID,
+		FamilyID:  values["family_id"],
+		Platform:  values["platform"],
+		IssuedAt:  time.Unix(issuedAt, 0),
+	}, nil
+}
+
+// markRotatedScript 令牌记录存在时累加轮换次数，不存在时返回 -1
+// 直接 HINCRBY 会在记录恰好过期后重建一个没有过期时间的空记录
+var markRotatedScript = goredis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return -1
+end
+return redis.call("HINCRBY", KEYS[1], "rotated", 1)
+`)
+
+// MarkRotated 原子地标记令牌已被轮换，返回该令牌是否为首次使用；令牌已过期时返回 ErrNotFound
+func (r *RefreshTokenRepository) MarkRotated(ctx context.Context, tokenHash string) (bool, error) {
+	count, err := markRotatedScript.Run(ctx, r.db.Client, []string{fmt.Sprintf(refreshTokenKey, tokenHash)}).Int64()
+	if err != nil {
+		return false, fmt.Errorf("failed to mark refresh token rotated: %w", err)
+	}
+	if count < 0 {
+		return false, repository.ErrNotFound
+	}
+	return count == 1, nil
+}
+
+// FamilyCurrent 获取家族当前有效的令牌哈希，家族被吊销或过期时返回 ErrNotFound
+func (r *RefreshTokenRepository) FamilyCurrent(ctx context.Context, familyID string) (string, error) {
+	current, err := r.db.HGet(ctx, fmt.Sprintf(refreshFamilyKey, familyID), "current")
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			return "", repository.ErrNotFound
+		}
+		return "", fmt.Errorf("failed to get refresh token family: %w", err)
+	}
+	return current, nil
+}
+
+// RevokeFamily 吊销整个令牌家族
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	if _, err := r.db.Del(ctx, fmt.Sprintf(refreshFamilyKey, familyID)); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+	return nil
+}
+
+// RevokeUserFamilies 吊销用户的全部令牌家族
+func (r *RefreshTokenRepository) RevokeUserFamilies(ctx context.Context, userID uint64) error {
+	userFamiliesKey := fmt.Sprintf(userRefreshFamiliesKey, userID)
+
+	familyIDs, err := r.db.Client.SMembers(ctx, userFamiliesKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list refresh token families: %w", err)
+	}
+
+	keys := make([]string, 0, len(familyIDs)+1)
+	for _, familyID := range familyIDs {
+		keys = append(keys, fmt.Sprintf(refreshFamilyKey, familyID))
+	}
+	keys = append(keys, userFamiliesKey)
+
+	if _, err := r.db.Del(ctx, keys...); err != nil {
+		return fmt.Errorf("failed to revoke refresh token families: %w", err)
+	}
+	return nil
+}
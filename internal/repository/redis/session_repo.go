@@ -0,0 +1,157 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/xuchengvcc/restart-life-api/internal/database"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository"
+)
+
+// SessionRepository 登录会话存储
+// 会话即刷新令牌家族，设备信息保存在家族记录中，家族被吊销即会话结束
+type SessionRepository struct {
+	db *database.RedisDB
+}
+
+// NewSessionRepository 创建登录会话存储
+func NewSessionRepository(db *database.RedisDB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// Create 记录新会话的设备信息
+func (r *SessionRepository) Create(ctx context.Context, session *models.Session, ttl time.Duration) error {
+	familyKey := fmt.Sprintf(refreshFamilyKey, session.SessionID)
+
+	pipe := r.db.Client.TxPipeline()
+	pipe.HSet(ctx, familyKey,
+		"user_id", session.UserID,
+		"platform", session.Platform,
+		"version", session.Version,
+		"user_agent", session.UserAgent,
+		"ip", session.IP,
+		"created_at", session.CreatedAt.Unix(),
+		"last_seen_at", session.LastSeenAt.Unix(),
+	)
+	pipe.Expire(ctx, familyKey, ttl)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	return nil
+}
+
+// Touch 刷新令牌轮换时更新会话的最近活跃信息
+func (r *SessionRepository) Touch(ctx context.Context, sessionID string, device models.DeviceInfo, at time.Time) error {
+	values := []interface{}{"last_seen_at", at.Unix()}
+	if device.IP != "" {
+		values = append(values, "ip", device.IP)
+	}
+	if device.Version != "" {
+		values = append(values, "version", device.Version)
+	}
+
+	if _, err := r.db.HSet(ctx, fmt.Sprintf(refreshFamilyKey, sessionID), values...); err != nil {
+		return fmt.Errorf("failed to touch session: %w", err)
+	}
+	return nil
+}
+
+// Get 获取会话，会话已结束时返回 ErrNotFound
+func (r *SessionRepository) Get(ctx context.Context, sessionID string) (*models.Session, error) {
+	values, err := r.db.HGetAll(ctx, fmt.Sprintf(refreshFamilyKey, sessionID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if len(values) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return parseSession(sessionID, values)
+}
+
+// ListByUser 列出用户的全部活跃会话，按创建时间升序
+func (r *SessionRepository) ListByUser(ctx context.Context, userID uint64) ([]*models.Session, error) {
+	userFamiliesKey := fmt.Sprintf(userRefreshFamiliesKey, userID)
+
+	sessionIDs, err := r.db.Client.SMembers(ctx, userFamiliesKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	if len(sessionIDs) == 0 {
+		return []*models.Session{}, nil
+	}
+
+	pipe := r.db.Client.Pipeline()
+	cmds := make([]*goredis.StringStringMapCmd, len(sessionIDs))
+	for i, sessionID := range sessionIDs {
+		cmds[i] = pipe.HGetAll(ctx, fmt.Sprintf(refreshFamilyKey, sessionID))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	sessions := make([]*models.Session, 0, len(sessionIDs))
+	stale := make([]interface{}, 0)
+	for i, cmd := range cmds {
+		values := cmd.Val()
+		if len(values) == 0 {
+			// 已吊销或过期的会话
+			stale = append(stale, sessionIDs[i])
+			continue
+		}
+		session, err := parseSession(sessionIDs[i], values)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	if len(stale) > 0 {
+		if err := r.db.Client.SRem(ctx, userFamiliesKey, stale...).Err(); err != nil {
+			return nil, fmt.Errorf("failed to clean up stale sessions: %w", err)
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
+
+// Delete 结束会话，会话内的刷新令牌和访问令牌随之失效
+func (r *SessionRepository) Delete(ctx context.Context, userID uint64, sessionID string) error {
+	pipe := r.db.Client.TxPipeline()
+	pipe.Del(ctx, fmt.Sprintf(refreshFamilyKey, sessionID))
+	pipe.SRem(ctx, fmt.Sprintf(userRefreshFamiliesKey, userID), sessionID)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+// parseSession 解析会话记录
+func parseSession(sessionID string, values map[string]string) (*models.Session, error) {
+	userID, err := strconv.ParseUint(values["user_id"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session record: %w", err)
+	}
+	createdAt, _ := strconv.ParseInt(values["created_at"], 10, 64)
+	lastSeenAt, _ := strconv.ParseInt(values["last_seen_at"], 10, 64)
+
+	return &models.Session{
+		SessionID:  sessionID,
+		UserID:     userID,
+		Platform:   values["platform"],
+		Version:    values["version"],
+		UserAgent:  values["user_agent"],
+		IP:         values["ip"],
+		CreatedAt:  time.Unix(createdAt, 0),
+		LastSeenAt: time.Unix(lastSeenAt, 0),
+	}, nil
+}
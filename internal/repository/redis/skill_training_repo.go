@@ -0,0 +1,57 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/xuchengvcc/restart-life-api/internal/database"
+)
+
+// skillTrainingCooldownKey 技能训练冷却，%d 为用户ID，%s 为角色ID
+const skillTrainingCooldownKey = "character:train_cooldown:%d:%s"
+
+// SkillTrainingCooldownPattern 用户全部角色技能训练冷却的键模式，用于删除账户时清理
+func SkillTrainingCooldownPattern(userID uint64) string {
+	return fmt.Sprintf(skillTrainingCooldownKey, userID, "*")
+}
+
+// SkillTrainingRepository 技能训练冷却存储
+type SkillTrainingRepository struct {
+	db *database.RedisDB
+}
+
+// NewSkillTrainingRepository 创建技能训练冷却存储
+func NewSkillTrainingRepository(db *database.RedisDB) *SkillTrainingRepository {
+	return &SkillTrainingRepository{db: db}
+}
+
+// AcquireCooldown 尝试占用角色的训练冷却期，冷却期内返回剩余时间，占用成功时返回 0
+func (r *SkillTrainingRepository) AcquireCooldown(ctx context.Context, userID uint64, characterID string, cooldown time.Duration) (time.Duration, error) {
+	key := fmt.Sprintf(skillTrainingCooldownKey, userID, characterID)
+	ok, err := r.db.Client.SetNX(ctx, key, 1, cooldown).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to acquire skill training cooldown: %w", err)
+	}
+	if ok {
+		return 0, nil
+	}
+
+	remaining, err := r.db.Client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get skill training cooldown: %w", err)
+	}
+	if remaining <= 0 {
+		// 恰好过期，按最短冷却处理，由客户端稍后重试
+		return time.Second, nil
+	}
+	return remaining, nil
+}
+
+// ReleaseCooldown 释放训练冷却期，用于训练未能生效的情况
+func (r *SkillTrainingRepository) ReleaseCooldown(ctx context.Context, userID uint64, characterID string) error {
+	if _, err := r.db.Del(ctx, fmt.Sprintf(skillTrainingCooldownKey, userID, characterID)); err != nil {
+		return fmt.Errorf("failed to release skill training cooldown: %w", err)
+	}
+	return nil
+}
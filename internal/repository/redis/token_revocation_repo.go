@@ -0,0 +1,89 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/xuchengvcc/restart-life-api/internal/database"
+)
+
+// 令牌吊销相关键命名
+const (
+	revokedTokenKey        = "auth:revoked_jti:%s"    // 已吊销的访问令牌ID
+	userTokenGenerationKey = "auth:user_token_gen:%d" // 用户 -> 访问令牌代数，低于当前代数的令牌全部失效
+)
+
+// TokenRevocationRepository 访问令牌吊销列表
+type TokenRevocationRepository struct {
+	db *database.RedisDB
+}
+
+// NewTokenRevocationRepository 创建访问令牌吊销列表
+func NewTokenRevocationRepository(db *database.RedisDB) *TokenRevocationRepository {
+	return &TokenRevocationRepository{db: db}
+}
+
+// RevokeTokenID 吊销单个访问令牌，记录保留到令牌自然过期
+func (r *TokenRevocationRepository) RevokeTokenID(ctx context.Context, tokenID string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	if err := r.db.Set(ctx, fmt.Sprintf(revokedTokenKey, tokenID), 1, ttl); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return nil
+}
+
+// IsTokenIDRevoked 检查访问令牌是否已被吊销
+func (r *TokenRevocationRepository) IsTokenIDRevoked(ctx context.Context, tokenID string) (bool, error) {
+	count, err := r.db.Exists(ctx, fmt.Sprintf(revokedTokenKey, tokenID))
+	if err != nil {
+		return false, fmt.Errorf("failed to check revoked token: %w", err)
+	}
+	return count > 0, nil
+}
+
+// BumpUserGeneration 递增用户的访问令牌代数，此前签发的访问令牌全部失效
+// ttl 至少为一个访问令牌有效期，之后旧令牌已自然过期
+func (r *TokenRevocationRepository) BumpUserGeneration(ctx context.Context, userID uint64, ttl time.Duration) error {
+	key := fmt.Sprintf(userTokenGenerationKey, userID)
+	pipe := r.db.Client.TxPipeline()
+	pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+	return nil
+}
+
+// AcquireUserGeneration 获取签发新令牌时使用的访问令牌代数
+// 同时将代数记录延长到新令牌过期之后，避免记录先于令牌过期、代数归零后旧代数的令牌躲过下一次吊销
+func (r *TokenRevocationRepository) AcquireUserGeneration(ctx context.Context, userID uint64, ttl time.Duration) (int64, error) {
+	key := fmt.Sprintf(userTokenGenerationKey, userID)
+	pipe := r.db.Client.TxPipeline()
+	get := pipe.Get(ctx, key)
+	pipe.Expire(ctx, key, ttl)
+	// 记录不存在时 GET 返回 redis.Nil，由 generation 处理
+	_, _ = pipe.Exec(ctx)
+	return generation(get)
+}
+
+// GetUserGeneration 获取用户当前的访问令牌代数，从未吊销过时返回 0
+func (r *TokenRevocationRepository) GetUserGeneration(ctx context.Context, userID uint64) (int64, error) {
+	return generation(r.db.Client.Get(ctx, fmt.Sprintf(userTokenGenerationKey, userID)))
+}
+
+// generation 解析代数记录，不存在时为 0
+func generation(cmd *goredis.StringCmd) (int64, error) {
+	value, err := cmd.Int64()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get user token generation: %w", err)
+	}
+	return value, nil
+}
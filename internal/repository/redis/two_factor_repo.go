@@ -0,0 +1,98 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/xuchengvcc/restart-life-api/internal/database"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository"
+)
+
+// 两步验证相关键命名
+const (
+	loginChallengeKey  = "auth:2fa_challenge:%s"   // 挑战令牌 -> 登录挑战上下文
+	totpUsedUserPrefix = "auth:totp_used:%d:"      // 用户的 TOTP 使用记录前缀
+	totpUsedStepKey    = totpUsedUserPrefix + "%d" // 用户 + 时间步 -> 已使用标记
+)
+
+// TOTPUsedPattern 用户全部 TOTP 使用记录的键模式，用于删除账户时清理
+func TOTPUsedPattern(userID uint64) string {
+	return fmt.Sprintf(totpUsedUserPrefix, userID) + "*"
+}
+
+// TwoFactorRepository 两步验证临时状态存储
+type TwoFactorRepository struct {
+	db *database.RedisDB
+}
+
+// NewTwoFactorRepository 创建两步验证临时状态存储
+func NewTwoFactorRepository(db *database.RedisDB) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+// SaveChallenge 保存登录挑战
+func (r *TwoFactorRepository) SaveChallenge(ctx context.Context, token string, state *models.LoginChallengeState, ttl time.Duration) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal login challenge: %w", err)
+	}
+
+	key := fmt.Sprintf(loginChallengeKey, token)
+	pipe := r.db.Client.TxPipeline()
+	pipe.HSet(ctx, key, "state", data, "attempts", 0)
+	pipe.Expire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save login challenge: %w", err)
+	}
+	return nil
+}
+
+// GetChallenge 获取登录挑战并累加尝试次数，返回挑战上下文和本次为第几次尝试
+func (r *TwoFactorRepository) GetChallenge(ctx context.Context, token string) (*models.LoginChallengeState, int64, error) {
+	key := fmt.Sprintf(loginChallengeKey, token)
+
+	pipe := r.db.Client.TxPipeline()
+	get := pipe.HGet(ctx, key, "state")
+	incr := pipe.HIncrBy(ctx, key, "attempts", 1)
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, goredis.Nil) {
+		return nil, 0, fmt.Errorf("failed to get login challenge: %w", err)
+	}
+
+	data, err := get.Bytes()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			// HIncrBy 会创建空记录，立即清理
+			r.db.Del(ctx, key)
+			return nil, 0, repository.ErrNotFound
+		}
+		return nil, 0, fmt.Errorf("failed to read login challenge: %w", err)
+	}
+
+	var state models.LoginChallengeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal login challenge: %w", err)
+	}
+	return &state, incr.Val(), nil
+}
+
+// DeleteChallenge 删除登录挑战
+func (r *TwoFactorRepository) DeleteChallenge(ctx context.Context, token string) error {
+	if _, err := r.db.Del(ctx, fmt.Sprintf(loginChallengeKey, token)); err != nil {
+		return fmt.Errorf("failed to delete login challenge: %w", err)
+	}
+	return nil
+}
+
+// MarkTOTPStepUsed 原子地标记某个时间步的验证码已使用，返回是否为首次使用
+func (r *TwoFactorRepository) MarkTOTPStepUsed(ctx context.Context, userID uint64, step int64, ttl time.Duration) (bool, error) {
+	ok, err := r.db.Client.SetNX(ctx, fmt.Sprintf(totpUsedStepKey, userID, step), 1, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to mark totp step used: %w", err)
+	}
+	return ok, nil
+}
@@ -0,0 +1,88 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/xuchengvcc/restart-life-api/internal/database"
+)
+
+// purgeScanCount 按模式清理时每批扫描的键数量
+const purgeScanCount = 500
+
+// UserPurgeRepository 删除账户时清理 Redis 中与该用户相关的数据
+type UserPurgeRepository struct {
+	db *database.RedisDB
+}
+
+// NewUserPurgeRepository 创建用户数据清理存储
+func NewUserPurgeRepository(db *database.RedisDB) *UserPurgeRepository {
+	return &UserPurgeRepository{db: db}
+}
+
+// PurgeUser 清理用户的登录会话、当前刷新令牌、邮件冷却、TOTP 使用记录、角色草稿、技能训练冷却以及登录失败计数和锁定
+// 用户级访问令牌代数不清理：它只含用户ID且会自然过期，保留可确保已签发的访问令牌继续失效
+// 已轮换的旧刷新令牌记录无法按用户索引，只含ID信息，随刷新令牌有效期自然过期
+func (r *UserPurgeRepository) PurgeUser(ctx context.Context, userID uint64) error {
+	userFamiliesKey := fmt.Sprintf(userRefreshFamiliesKey, userID)
+
+	familyIDs, err := r.db.Client.SMembers(ctx, userFamiliesKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list refresh token families: %w", err)
+	}
+
+	subject := UserLoginSubject(userID)
+	keys := []string{
+		userFamiliesKey,
+		fmt.Sprintf(loginFailuresKey, subject),
+		fmt.Sprintf(loginLockKey, subject),
+	}
+	for _, familyID := range familyIDs {
+		familyKey := fmt.Sprintf(refreshFamilyKey, familyID)
+		current, err := r.db.HGet(ctx, familyKey, "current")
+		if err != nil && !errors.Is(err, goredis.Nil) {
+			return fmt.Errorf("failed to get refresh token family: %w", err)
+		}
+		if current != "" {
+			keys = append(keys, fmt.Sprintf(refreshTokenKey, current))
+		}
+		keys = append(keys, familyKey)
+	}
+	if _, err := r.db.Del(ctx, keys...); err != nil {
+		return fmt.Errorf("failed to purge user sessions: %w", err)
+	}
+
+	for _, pattern := range []string{
+		ActionMailCooldownPattern(userID),
+		TOTPUsedPattern(userID),
+		CharacterDraftPattern(userID),
+		SkillTrainingCooldownPattern(userID),
+	} {
+		if err := r.deleteByPattern(ctx, pattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteByPattern 按模式扫描并删除键，仅用于低频的账户清理任务
+func (r *UserPurgeRepository) deleteByPattern(ctx context.Context, pattern string) error {
+	var cursor uint64
+	for {
+		keys, next, err := r.db.Client.Scan(ctx, cursor, pattern, purgeScanCount).Result()
+		if err != nil {
+			return fmt.Errorf("failed to scan keys: %w", err)
+		}
+		if len(keys) > 0 {
+			if _, err := r.db.Del(ctx, keys...); err != nil {
+				return fmt.Errorf("failed to delete keys: %w", err)
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
@@ -0,0 +1,100 @@
+package redis
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/xuchengvcc/restart-life-api/internal/database"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/utils"
+)
+
+// seedUserData 通过各存储写入一个用户的全部 Redis 数据
+func seedUserData(t *testing.T, db *database.RedisDB, userID uint64) {
+	t.Helper()
+	ctx := context.Background()
+	familyID := utils.RandomHex(16)
+
+	steps := []struct {
+		name string
+		run  func() error
+	}{
+		{"session", func() error {
+			return NewSessionRepository(db).Create(ctx, &models.Session{SessionID: familyID, UserID: userID, Platform: "ios"}, time.Hour)
+		}},
+		{"refresh token", func() error {
+			return NewRefreshTokenRepository(db).Save(ctx, &models.RefreshToken{TokenHash: utils.RandomHex(32), UserID: userID, FamilyID: familyID}, time.Hour)
+		}},
+		{"mail cooldown", func() error {
+			_, err := NewActionTokenRepository(db).AcquireMailCooldown(ctx, utils.PurposeVerifyEmail, userID, time.Hour)
+			return err
+		}},
+		{"totp step", func() error {
+			_, err := NewTwoFactorRepository(db).MarkTOTPStepUsed(ctx, userID, 123456, time.Hour)
+			return err
+		}},
+		{"character draft", func() error {
+			return NewCharacterDraftRepository(db).Save(ctx, userID, &models.CharacterDraft{DraftID: "draft-1", ExpiresAt: time.Now().Add(time.Hour)})
+		}},
+		{"training cooldown", func() error {
+			_, err := NewSkillTrainingRepository(db).AcquireCooldown(ctx, userID, "character-1", time.Hour)
+			return err
+		}},
+		{"login failures", func() error {
+			attempts := NewLoginAttemptRepository(db)
+			if _, err := attempts.IncrFailures(ctx, UserLoginSubject(userID), time.Hour); err != nil {
+				return err
+			}
+			return attempts.Lock(ctx, UserLoginSubject(userID), time.Hour)
+		}},
+	}
+	for _, step := range steps {
+		if err := step.run(); err != nil {
+			t.Fatalf("seed %s: %v", step.name, err)
+		}
+	}
+}
+
+// userKeys 返回以指定用户ID作为一段的键
+func userKeys(server *miniredis.Miniredis, userID string) []string {
+	var keys []string
+	for _, key := range server.Keys() {
+		for _, part := range strings.Split(key, ":") {
+			if part == userID {
+				keys = append(keys, key)
+				break
+			}
+		}
+	}
+	return keys
+}
+
+func TestPurgeUserRemovesAllUserKeys(t *testing.T) {
+	server := miniredis.RunT(t)
+	db := &database.RedisDB{Client: goredis.NewClient(&goredis.Options{Addr: server.Addr()})}
+	t.Cleanup(func() { db.Client.Close() })
+
+	seedUserData(t, db, 7)
+	seedUserData(t, db, 8)
+	otherKeys := len(server.Keys())
+
+	if err := NewUserPurgeRepository(db).PurgeUser(context.Background(), 7); err != nil {
+		t.Fatalf("PurgeUser() error = %v", err)
+	}
+
+	// 按用户ID无法识别的会话和刷新令牌键，用剩余键数量校验
+	if keys := userKeys(server, "7"); len(keys) > 0 {
+		t.Errorf("PurgeUser() left user keys behind: %v", keys)
+	}
+	remaining := server.Keys()
+	if len(remaining)*2 != otherKeys {
+		t.Errorf("PurgeUser() left %d keys, want only the other user's %d keys: %v", len(remaining), otherKeys/2, remaining)
+	}
+	if keys := userKeys(server, "8"); len(keys) == 0 {
+		t.Error("PurgeUser() removed another user's keys")
+	}
+}
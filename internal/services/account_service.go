@@ -0,0 +1,313 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xuchengvcc/restart-life-api/internal/mailer"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository"
+	"github.com/xuchengvcc/restart-life-api/internal/repository/mysql"
+	redisrepo "github.com/xuchengvcc/restart-life-api/internal/repository/redis"
+	"github.com/xuchengvcc/restart-life-api/internal/utils"
+)
+
+const (
+	// DefaultEmailVerifyExpiry 默认邮箱验证链接有效期
+	DefaultEmailVerifyExpiry = 24 * time.Hour
+	// DefaultPasswordResetExpiry 默认重置密码链接有效期
+	DefaultPasswordResetExpiry = 30 * time.Minute
+	// accountMailCooldown 同一用户同类邮件的最小发送间隔
+	accountMailCooldown = time.Minute
+	// accountMailTimeout 后台发送邮件的超时
+	accountMailTimeout = 30 * time.Second
+)
+
+// 账户安全业务错误
+var (
+	// ErrEmailNotSet 账户未绑定邮箱
+	ErrEmailNotSet = errors.New("account has no email")
+	// ErrEmailAlreadyVerified 邮箱已验证
+	ErrEmailAlreadyVerified = errors.New("email already verified")
+	// ErrMailRateLimited 邮件发送过于频繁
+	ErrMailRateLimited = errors.New("mail sent too frequently")
+	// ErrInvalidActionToken 验证链接无效、已过期或已使用
+	ErrInvalidActionToken = errors.New("invalid action token")
+)
+
+// AccountConfig 账户安全配置
+type AccountConfig struct {
+	EmailVerifyURL      string
+	PasswordResetURL    string
+	EmailVerifyExpiry   time.Duration
+	PasswordResetExpiry time.Duration
+}
+
+// AccountService 账户安全服务，负责邮箱验证与找回密码
+type AccountService struct {
+	users        *mysql.UserRepository
+	actionTokens *redisrepo.ActionTokenRepository
+	signer       *utils.ActionTokenSigner
+	mailer       mailer.Mailer
+	tokens       *TokenService
+	config       AccountConfig
+}
+
+// NewAccountService 创建账户安全服务
+func NewAccountService(
+	users *mysql.UserRepository,
+	actionTokens *redisrepo.ActionTokenRepository,
+	signer *utils.ActionTokenSigner,
+	mail mailer.Mailer,
+	tokens *TokenService,
+	config AccountConfig,
+) *AccountService {
+	// 设置默认值
+	if config.EmailVerifyExpiry <= 0 {
+		config.EmailVerifyExpiry = DefaultEmailVerifyExpiry
+	}
+	if config.PasswordResetExpiry <= 0 {
+		config.PasswordResetExpiry = DefaultPasswordResetExpiry
+	}
+
+	return &AccountService{
+		users:        users,
+		actionTokens: actionTokens,
+		signer:       signer,
+		mailer:       mail,
+		tokens:       tokens,
+		config:       config,
+	}
+}
+
+// SendVerificationEmail 向用户当前邮箱发送验证邮件
+func (s *AccountService) SendVerificationEmail(ctx context.Context, userID uint64) error {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if user.Email == "" {
+		return ErrEmailNotSet
+	}
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+
+	acquired, err := s.actionTokens.AcquireMailCooldown(ctx, utils.PurposeVerifyEmail, userID, accountMailCooldown)
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		return ErrMailRateLimited
+	}
+
+	// 绑定邮箱地址，邮箱变更后旧链接自动失效
+	token, _, err := s.signer.Sign(utils.PurposeVerifyEmail, userID, user.Email, s.config.EmailVerifyExpiry)
+	if err != nil {
+		return err
+	}
+
+	link, err := withToken(s.config.EmailVerifyURL, token)
+	if err != nil {
+		return err
+	}
+
+	s.deliver(&mailer.Message{
+		To:      user.Email,
+		Subject: "【重启人生】请验证你的邮箱",
+		TextBody: fmt.Sprintf("%s，你好：\n\n请点击以下链接完成邮箱验证，链接在 %s 内有效：\n%s\n\n如果这不是你本人的操作，请忽略本邮件。\n",
+			user.Username, formatDuration(s.config.EmailVerifyExpiry), link),
+	}, userID, utils.PurposeVerifyEmail)
+	return nil
+}
+
+// VerifyEmail 使用验证链接中的令牌完成邮箱验证
+func (s *AccountService) VerifyEmail(ctx context.Context, token string) error {
+	claims, user, err := s.consumeActionToken(ctx, utils.PurposeVerifyEmail, token)
+	if err != nil {
+		return err
+	}
+	if claims.Binding != user.Email {
+		return ErrInvalidActionToken
+	}
+
+	if err := s.users.MarkEmailVerified(ctx, user.UserID, user.Email, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrInvalidActionToken
+		}
+		return err
+	}
+
+	logrus.WithField("user_id", user.UserID).Info("Email verified")
+	return nil
+}
+
+// RequestPasswordReset 发送重置密码邮件
+// 无论邮箱是否存在均正常返回，避免被用于探测已注册邮箱
+func (s *AccountService) RequestPasswordReset(ctx context.Context, email string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	user, err := s.users.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			logrus.Debug("Password reset requested for unknown email")
+			return nil
+		}
+		return err
+	}
+	if !user.IsActive {
+		return nil
+	}
+
+	acquired, err := s.actionTokens.AcquireMailCooldown(ctx, utils.PurposeResetPassword, user.UserID, accountMailCooldown)
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		return nil
+	}
+
+	// 绑定当前密码哈希指纹，密码修改后所有未使用的重置链接一并失效
+	token, _, err := s.signer.Sign(utils.PurposeResetPassword, user.UserID, passwordFingerprint(user.PasswordHash), s.config.PasswordResetExpiry)
+	if err != nil {
+		return err
+	}
+
+	link, err := withToken(s.config.PasswordResetURL, token)
+	if err != nil {
+		return err
+	}
+
+	s.deliver(&mailer.Message{
+		To:      user.Email,
+		Subject: "【重启人生】重置密码",
+		TextBody: fmt.Sprintf("%s，你好：\n\n我们收到了重置你账户密码的请求。请点击以下链接设置新密码，链接在 %s 内有效且只能使用一次：\n%s\n\n如果这不是你本人的操作，请忽略本邮件，你的密码不会被修改。\n",
+			user.Username, formatDuration(s.config.PasswordResetExpiry), link),
+	}, user.UserID, utils.PurposeResetPassword)
+	return nil
+}
+
+// ResetPassword 使用重置链接中的令牌设置新密码，并登出所有设备
+func (s *AccountService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	// 先校验密码长度，避免无效请求消耗重置链接
+	if len(newPassword) > utils.MaxPasswordBytes {
+		return ErrPasswordTooLong
+	}
+
+	claims, user, err := s.consumeActionToken(ctx, utils.PurposeResetPassword, token)
+	if err != nil {
+		return err
+	}
+	if claims.Binding != passwordFingerprint(user.PasswordHash) {
+		return ErrInvalidActionToken
+	}
+
+	hash, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.users.UpdatePassword(ctx, user.UserID, hash); err != nil {
+		return err
+	}
+
+	// 能收到重置邮件即证明拥有该邮箱
+	if user.EmailVerifiedAt == nil && user.Email != "" {
+		if err := s.users.MarkEmailVerified(ctx, user.UserID, user.Email, time.Now()); err != nil {
+			logrus.WithError(err).WithField("user_id", user.UserID).Warn("Failed to mark email verified after password reset")
+		}
+	}
+
+	if err := s.tokens.RevokeAllForUser(ctx, user.UserID); err != nil {
+		return err
+	}
+
+	logrus.WithField("user_id", user.UserID).Info("Password reset, all sessions revoked")
+	return nil
+}
+
+// consumeActionToken 校验操作令牌并标记为已使用，返回令牌载荷和所属用户
+func (s *AccountService) consumeActionToken(ctx context.Context, purpose, token string) (*utils.ActionClaims, *models.User, error) {
+	claims, err := s.signer.Parse(purpose, token)
+	if err != nil {
+		return nil, nil, ErrInvalidActionToken
+	}
+
+	userID, err := claims.UserID()
+	if err != nil {
+		return nil, nil, ErrInvalidActionToken
+	}
+
+	firstUse, err := s.actionTokens.MarkUsed(ctx, claims.ID, time.Until(claims.ExpiresAt.Time))
+	if err != nil {
+		return nil, nil, err
+	}
+	if !firstUse {
+		return nil, nil, ErrInvalidActionToken
+	}
+
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, ErrInvalidActionToken
+		}
+		return nil, nil, err
+	}
+	if !user.IsActive {
+		return nil, nil, ErrUserDisabled
+	}
+
+	return claims, user, nil
+}
+
+// deliver 在后台发送邮件，请求无需等待 SMTP 往返，发送失败仅记录日志
+func (s *AccountService) deliver(msg *mailer.Message, userID uint64, purpose string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), accountMailTimeout)
+		defer cancel()
+
+		if err := s.mailer.Send(ctx, msg); err != nil {
+			logrus.WithError(err).WithFields(logrus.Fields{
+				"user_id": userID,
+				"purpose": purpose,
+			}).Error("Failed to send account mail")
+		}
+	}()
+}
+
+// withToken 将令牌以 token 查询参数附加到链接
+func withToken(rawURL, token string) (string, error) {
+	link, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid link url %q: %w", rawURL, err)
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String(), nil
+}
+
+// passwordFingerprint 密码哈希指纹
+func passwordFingerprint(passwordHash string) string {
+	sum := sha256.Sum256([]byte(passwordHash))
+	return hex.EncodeToString(sum[:8])
+}
+
+// formatDuration 将有效期格式化为中文描述
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= time.Hour && d%time.Hour == 0:
+		return fmt.Sprintf("%d 小时", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%d 分钟", int(d.Minutes()))
+	}
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/xuchengvcc/restart-life-api/internal/mailer"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository/mysql"
+	redisrepo "github.com/xuchengvcc/restart-life-api/internal/repository/redis"
+	"github.com/xuchengvcc/restart-life-api/internal/utils"
+)
+
+const (
+	testUserID = uint64(7)
+	testEmail  = "alice@example.com"
+)
+
+// captureMailer 记录发出的邮件，替代真实的邮件发送
+type captureMailer struct {
+	sent chan *mailer.Message
+}
+
+func (m *captureMailer) Send(ctx context.Context, msg *mailer.Message) error {
+	m.sent <- msg
+	return nil
+}
+
+// accountFixture 基于 sqlmock 和 miniredis 的账户安全服务
+type accountFixture struct {
+	service *AccountService
+	sql     sqlmock.Sqlmock
+	redis   *miniredis.Miniredis
+	mail    *captureMailer
+}
+
+func newAccountFixture(t *testing.T) *accountFixture {
+	t.Helper()
+
+	db, mock := newTestMySQL(t)
+	rdb, server := newTestRedis(t)
+
+	mail := &captureMailer{sent: make(chan *mailer.Message, 1)}
+	tokens := NewTokenService(
+		utils.NewJWTManager("jwt-secret", 15*time.Minute),
+		redisrepo.NewRefreshTokenRepository(rdb),
+		redisrepo.NewTokenRevocationRepository(rdb),
+		nil, nil, 0,
+	)
+
+	return &accountFixture{
+		service: NewAccountService(
+			mysql.NewUserRepository(db),
+			redisrepo.NewActionTokenRepository(rdb),
+			utils.NewActionTokenSigner("action-secret"),
+			mail,
+			tokens,
+			AccountConfig{
+				EmailVerifyURL:   "https://app.example.com/verify-email",
+				PasswordResetURL: "https://app.example.com/reset-password",
+			},
+		),
+		sql:   mock,
+		redis: server,
+		mail:  mail,
+	}
+}
+
+// expectUser 期望按指定条件查询一次用户
+func (f *accountFixture) expectUser(where string, arg interface{}, passwordHash string, emailVerifiedAt *time.Time) {
+	f.sql.ExpectQuery(`FROM users WHERE ` + where + ` = \?`).WithArgs(arg).WillReturnRows(userRows(&models.User{
+		UserID:          testUserID,
+		Username:        "alice",
+		Email:           testEmail,
+		EmailVerifiedAt: emailVerifiedAt,
+		PasswordHash:    passwordHash,
+		IsActive:        true,
+	}))
+}
+
+// receiveToken 等待后台发出的邮件，返回其中链接携带的令牌
+func (f *accountFixture) receiveToken(t *testing.T) string {
+	t.Helper()
+
+	select {
+	case msg := <-f.mail.sent:
+		if msg.To != testEmail {
+			t.Fatalf("mail sent to %q, want %q", msg.To, testEmail)
+		}
+		link, err := url.Parse(regexp.MustCompile(`https://\S+`).FindString(msg.TextBody))
+		if err != nil {
+			t.Fatalf("mail contains no valid link: %v", err)
+		}
+		token := link.Query().Get("token")
+		if token == "" {
+			t.Fatalf("mail link %q has no token", link)
+		}
+		return token
+	case <-time.After(5 * time.Second):
+		t.Fatal("no mail sent")
+		return ""
+	}
+}
+
+func TestVerifyEmailTokenSingleUse(t *testing.T) {
+	f := newAccountFixture(t)
+	ctx := context.Background()
+
+	f.expectUser("user_id", testUserID, "hash", nil)
+	if err := f.service.SendVerificationEmail(ctx, testUserID); err != nil {
+		t.Fatalf("SendVerificationEmail() error = %v", err)
+	}
+	token := f.receiveToken(t)
+
+	f.expectUser("user_id", testUserID, "hash", nil)
+	f.sql.ExpectExec(`UPDATE users SET email_verified_at = \?`).
+		WithArgs(sqlmock.AnyArg(), testUserID, testEmail).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := f.service.VerifyEmail(ctx, token); err != nil {
+		t.Fatalf("VerifyEmail() error = %v", err)
+	}
+
+	// 同一链接再次使用时在查询数据库之前即被拒绝
+	if err := f.service.VerifyEmail(ctx, token); !errors.Is(err, ErrInvalidActionToken) {
+		t.Errorf("VerifyEmail() reuse error = %v, want ErrInvalidActionToken", err)
+	}
+	// 验证令牌不能用于重置密码
+	if err := f.service.ResetPassword(ctx, token, "new-password"); !errors.Is(err, ErrInvalidActionToken) {
+		t.Errorf("ResetPassword() with verify-email token error = %v, want ErrInvalidActionToken", err)
+	}
+	if err := f.sql.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestResetPasswordTokenSingleUse(t *testing.T) {
+	f := newAccountFixture(t)
+	ctx := context.Background()
+	verifiedAt := time.Now()
+
+	f.expectUser("email", testEmail, "old-hash", &verifiedAt)
+	if err := f.service.RequestPasswordReset(ctx, " Alice@Example.com "); err != nil {
+		t.Fatalf("RequestPasswordReset() error = %v", err)
+	}
+	token := f.receiveToken(t)
+
+	f.expectUser("user_id", testUserID, "old-hash", &verifiedAt)
+	f.sql.ExpectExec(`UPDATE users SET password_hash = \?`).
+		WithArgs(sqlmock.AnyArg(), testUserID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := f.service.ResetPassword(ctx, token, "new-password"); err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	if !f.redis.Exists("auth:user_token_gen:7") {
+		t.Error("ResetPassword() did not revoke existing access tokens")
+	}
+
+	if err := f.service.ResetPassword(ctx, token, "another-password"); !errors.Is(err, ErrInvalidActionToken) {
+		t.Errorf("ResetPassword() reuse error = %v, want ErrInvalidActionToken", err)
+	}
+	if err := f.sql.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestResetPasswordTokenBoundToPassword(t *testing.T) {
+	f := newAccountFixture(t)
+	ctx := context.Background()
+	verifiedAt := time.Now()
+
+	f.expectUser("email", testEmail, "old-hash", &verifiedAt)
+	if err := f.service.RequestPasswordReset(ctx, testEmail); err != nil {
+		t.Fatalf("RequestPasswordReset() error = %v", err)
+	}
+	token := f.receiveToken(t)
+
+	// 密码在使用链接前已被修改，未使用的重置链接随之失效
+	f.expectUser("user_id", testUserID, "changed-hash", &verifiedAt)
+	if err := f.service.ResetPassword(ctx, token, "new-password"); !errors.Is(err, ErrInvalidActionToken) {
+		t.Errorf("ResetPassword() after password change error = %v, want ErrInvalidActionToken", err)
+	}
+	if err := f.sql.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
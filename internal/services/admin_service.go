@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository"
+	"github.com/xuchengvcc/restart-life-api/internal/repository/mysql"
+)
+
+// 管理后台业务错误
+var (
+	// ErrRoleNotFound 角色不存在
+	ErrRoleNotFound = errors.New("role not found")
+	// ErrRoleNotAssigned 用户未拥有该角色
+	ErrRoleNotAssigned = errors.New("role not assigned")
+	// ErrCannotModifySelf 不能修改自己的状态或角色，避免管理员误操作锁死自己
+	ErrCannotModifySelf = errors.New("cannot modify own account")
+	// ErrAdminRequired 目标用户或所授予的角色拥有管理后台权限，只有超级管理员才能修改
+	ErrAdminRequired = errors.New("admin role required")
+)
+
+// AdminService 管理后台服务
+type AdminService struct {
+	users      *mysql.UserRepository
+	roles      *mysql.RoleRepository
+	characters *mysql.CharacterRepository
+	tokens     *TokenService
+}
+
+// NewAdminService 创建管理后台服务
+func NewAdminService(
+	users *mysql.UserRepository,
+	roles *mysql.RoleRepository,
+	characters *mysql.CharacterRepository,
+	tokens *TokenService,
+) *AdminService {
+	return &AdminService{
+		users:      users,
+		roles:      roles,
+		characters: characters,
+		tokens:     tokens,
+	}
+}
+
+// GetUser 获取用户详情及其角色权限
+func (s *AdminService) GetUser(ctx context.Context, userID uint64) (*models.AdminUserDetail, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	roles, err := s.roles.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := s.roles.GetUserPermissions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AdminUserDetail{
+		User:        user,
+		Roles:       roles,
+		Permissions: permissions,
+	}, nil
+}
+
+// SetUserActive 启用或禁用用户，禁用时吊销其全部令牌
+func (s *AdminService) SetUserActive(ctx context.Context, operatorID, userID uint64, active bool) error {
+	if operatorID == userID {
+		return ErrCannotModifySelf
+	}
+	if err := s.requireAdminForStaff(ctx, operatorID, userID); err != nil {
+		return err
+	}
+
+	if err := s.users.SetActive(ctx, userID, active); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if !active {
+		if err := s.tokens.RevokeAllForUser(ctx, userID); err != nil {
+			return err
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"operator_id": operatorID,
+		"user_id":     userID,
+		"is_active":   active,
+	}).Info("User status changed by admin")
+	return nil
+}
+
+// ListRoles 列出全部角色及其权限
+func (s *AdminService) ListRoles(ctx context.Context) ([]*models.Role, error) {
+	return s.roles.ListRoles(ctx)
+}
+
+// AssignRole 为用户授予角色，用户的访问令牌随即失效，刷新后获得新权限
+func (s *AdminService) AssignRole(ctx context.Context, operatorID, userID uint64, role string) error {
+	if operatorID == userID {
+		return ErrCannotModifySelf
+	}
+	if _, err := s.users.GetByID(ctx, userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if err := s.requireAdminForStaff(ctx, operatorID, userID); err != nil {
+		return err
+	}
+	if err := s.requireAdminForRole(ctx, operatorID, role); err != nil {
+		return err
+	}
+
+	if err := s.roles.AssignRole(ctx, userID, role, operatorID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrRoleNotFound
+		}
+		return err
+	}
+	if err := s.tokens.InvalidateAccessTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"operator_id": operatorID,
+		"user_id":     userID,
+		"role":        role,
+	}).Info("Role assigned")
+	return nil
+}
+
+// RevokeRole 撤销用户的角色，用户的访问令牌随即失效
+func (s *AdminService) RevokeRole(ctx context.Context, operatorID, userID uint64, role string) error {
+	if operatorID == userID {
+		return ErrCannotModifySelf
+	}
+	if err := s.requireAdminForStaff(ctx, operatorID, userID); err != nil {
+		return err
+	}
+
+	if err := s.roles.RevokeRole(ctx, userID, role); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrRoleNotAssigned
+		}
+		return err
+	}
+	if err := s.tokens.InvalidateAccessTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"operator_id": operatorID,
+		"user_id":     userID,
+		"role":        role,
+	}).Info("Role revoked")
+	return nil
+}
+
+// DeleteCharacter 彻底删除角色，包括已归档的角色，不可恢复
+func (s *AdminService) DeleteCharacter(ctx context.Context, operatorID uint64, characterID string) error {
+	if err := s.characters.DeleteByID(ctx, characterID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrCharacterNotFound
+		}
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"operator_id":  operatorID,
+		"character_id": characterID,
+	}).Info("Character permanently deleted by admin")
+	return nil
+}
+
+// requireAdminForStaff 目标用户拥有管理后台访问权限时要求操作者为超级管理员，避免运营人员禁用或降级管理员
+func (s *AdminService) requireAdminForStaff(ctx context.Context, operatorID, userID uint64) error {
+	permissions, err := s.roles.GetUserPermissions(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(permissions, models.PermissionAdminAccess) {
+		return nil
+	}
+	return s.requireAdmin(ctx, operatorID)
+}
+
+// requireAdminForRole 授予的角色包含管理后台访问权限时要求操作者为超级管理员，避免越权提升
+func (s *AdminService) requireAdminForRole(ctx context.Context, operatorID uint64, role string) error {
+	roles, err := s.roles.ListRoles(ctx)
+	if err != nil {
+		return err
+	}
+	for _, r := range roles {
+		if r.Name == role && slices.Contains(r.Permissions, models.PermissionAdminAccess) {
+			return s.requireAdmin(ctx, operatorID)
+		}
+	}
+	return nil
+}
+
+// requireAdmin 要求操作者拥有超级管理员角色
+func (s *AdminService) requireAdmin(ctx context.Context, operatorID uint64) error {
+	roles, err := s.roles.GetUserRoles(ctx, operatorID)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(roles, models.RoleAdmin) {
+		return ErrAdminRequired
+	}
+	return nil
+}
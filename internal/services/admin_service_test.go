@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository/mysql"
+)
+
+// 管理后台测试中的用户
+const (
+	testAdminID    = uint64(1)
+	testOperatorID = uint64(2)
+	testStaffID    = uint64(3)
+	testPlayerID   = uint64(4)
+)
+
+// adminFixture 基于 sqlmock 和 miniredis 的管理后台服务
+type adminFixture struct {
+	service *AdminService
+	sql     sqlmock.Sqlmock
+}
+
+func newAdminFixture(t *testing.T) *adminFixture {
+	t.Helper()
+
+	db, mock := newTestMySQL(t)
+	rdb, _ := newTestRedis(t)
+
+	return &adminFixture{
+		service: NewAdminService(mysql.NewUserRepository(db), mysql.NewRoleRepository(db), nil, newTestTokenService(db, rdb)),
+		sql:     mock,
+	}
+}
+
+// expectPermissions 期望查询一次用户权限
+func (f *adminFixture) expectPermissions(userID uint64, permissions ...string) {
+	f.sql.ExpectQuery(`SELECT DISTINCT p.name FROM user_roles`).WithArgs(userID).WillReturnRows(nameRows(permissions...))
+}
+
+// expectRoles 期望查询一次用户角色
+func (f *adminFixture) expectRoles(userID uint64, roles ...string) {
+	f.sql.ExpectQuery(`SELECT r.name FROM user_roles`).WithArgs(userID).WillReturnRows(nameRows(roles...))
+}
+
+// expectUser 期望查询一次目标用户
+func (f *adminFixture) expectUser(userID uint64) {
+	f.sql.ExpectQuery(`FROM users WHERE user_id = \?`).WithArgs(userID).
+		WillReturnRows(userRows(&models.User{UserID: userID, Username: "target", IsActive: true}))
+}
+
+// expectRoleList 期望查询一次角色列表：operator 含管理后台权限，support 不含
+func (f *adminFixture) expectRoleList() {
+	now := time.Now()
+	f.sql.ExpectQuery(`FROM roles r`).WillReturnRows(sqlmock.NewRows([]string{"role_id", "name", "description", "created_at", "name"}).
+		AddRow(1, models.RoleAdmin, nil, now, models.PermissionAdminAccess).
+		AddRow(2, models.RoleOperator, nil, now, models.PermissionAdminAccess).
+		AddRow(2, models.RoleOperator, nil, now, models.PermissionUsersWrite).
+		AddRow(3, "support", nil, now, models.PermissionContentRead))
+}
+
+func TestOperatorCannotModifyStaff(t *testing.T) {
+	f := newAdminFixture(t)
+	ctx := context.Background()
+
+	f.expectPermissions(testStaffID, models.PermissionAdminAccess, models.PermissionUsersWrite)
+	f.expectRoles(testOperatorID, models.RoleOperator)
+	if err := f.service.SetUserActive(ctx, testOperatorID, testStaffID, false); !errors.Is(err, ErrAdminRequired) {
+		t.Errorf("SetUserActive() error = %v, want ErrAdminRequired", err)
+	}
+
+	f.expectPermissions(testStaffID, models.PermissionAdminAccess)
+	f.expectRoles(testOperatorID, models.RoleOperator)
+	if err := f.service.RevokeRole(ctx, testOperatorID, testStaffID, models.RoleOperator); !errors.Is(err, ErrAdminRequired) {
+		t.Errorf("RevokeRole() error = %v, want ErrAdminRequired", err)
+	}
+
+	f.expectUser(testStaffID)
+	f.expectPermissions(testStaffID, models.PermissionAdminAccess)
+	f.expectRoles(testOperatorID, models.RoleOperator)
+	if err := f.service.AssignRole(ctx, testOperatorID, testStaffID, "support"); !errors.Is(err, ErrAdminRequired) {
+		t.Errorf("AssignRole() to staff error = %v, want ErrAdminRequired", err)
+	}
+
+	if err := f.sql.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestOperatorCannotGrantStaffRole(t *testing.T) {
+	f := newAdminFixture(t)
+	ctx := context.Background()
+
+	f.expectUser(testPlayerID)
+	f.expectPermissions(testPlayerID)
+	f.expectRoleList()
+	f.expectRoles(testOperatorID, models.RoleOperator)
+	if err := f.service.AssignRole(ctx, testOperatorID, testPlayerID, models.RoleOperator); !errors.Is(err, ErrAdminRequired) {
+		t.Errorf("AssignRole() of a staff role error = %v, want ErrAdminRequired", err)
+	}
+
+	// 不含管理后台权限的角色可以由运营人员授予
+	f.expectUser(testPlayerID)
+	f.expectPermissions(testPlayerID)
+	f.expectRoleList()
+	f.sql.ExpectQuery(`SELECT role_id FROM roles WHERE name = \?`).WithArgs("support").
+		WillReturnRows(sqlmock.NewRows([]string{"role_id"}).AddRow(3))
+	f.sql.ExpectExec(`INSERT IGNORE INTO user_roles`).WithArgs(testPlayerID, 3, testOperatorID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := f.service.AssignRole(ctx, testOperatorID, testPlayerID, "support"); err != nil {
+		t.Errorf("AssignRole() of a non-staff role error = %v", err)
+	}
+
+	if err := f.sql.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAdminCanModifyStaff(t *testing.T) {
+	f := newAdminFixture(t)
+	ctx := context.Background()
+
+	f.expectPermissions(testStaffID, models.PermissionAdminAccess)
+	f.expectRoles(testAdminID, models.RoleAdmin)
+	f.sql.ExpectExec(`DELETE ur FROM user_roles`).WithArgs(testStaffID, models.RoleOperator).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := f.service.RevokeRole(ctx, testAdminID, testStaffID, models.RoleOperator); err != nil {
+		t.Errorf("RevokeRole() error = %v", err)
+	}
+
+	if err := f.sql.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository"
+	"github.com/xuchengvcc/restart-life-api/internal/repository/mysql"
+	redisrepo "github.com/xuchengvcc/restart-life-api/internal/repository/redis"
+	"github.com/xuchengvcc/restart-life-api/internal/utils"
+)
+
+// API 密钥相关默认值
+const (
+	DefaultMaxAPIKeysPerUser  = 10
+	DefaultAPIKeyRateLimit    = 60
+	DefaultMaxAPIKeyRateLimit = 600
+)
+
+// API 密钥使用参数
+const (
+	// apiKeyRateWindow 限流窗口，rate_limit 为每个窗口内的请求上限
+	apiKeyRateWindow = time.Minute
+	// apiKeyTouchInterval 最后使用时间的最小记录间隔
+	apiKeyTouchInterval = time.Minute
+)
+
+// API 密钥业务错误
+var (
+	// ErrAPIKeyNotFound API 密钥不存在或已吊销
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	// ErrAPIKeyLimitReached API 密钥数量达到上限
+	ErrAPIKeyLimitReached = errors.New("api key limit reached")
+	// ErrInvalidAPIKeyScope 授权范围不存在或超出用户自身权限
+	ErrInvalidAPIKeyScope = errors.New("invalid api key scope")
+	// ErrInvalidAPIKeyRateLimit 限流值超出上限
+	ErrInvalidAPIKeyRateLimit = errors.New("invalid api key rate limit")
+)
+
+// apiKeyBaseScopes 所有用户都可授予 API 密钥的基础授权范围
+var apiKeyBaseScopes = map[string]bool{
+	models.ScopeGameRead:  true,
+	models.ScopeGameWrite: true,
+}
+
+// APIKeyConfig API 密钥服务配置
+type APIKeyConfig struct {
+	MaxKeysPerUser   int // 每个用户可同时持有的有效密钥数
+	DefaultRateLimit int // 未指定时每分钟请求上限
+	MaxRateLimit     int // 可指定的每分钟请求上限
+}
+
+// APIKeyService 个人 API 密钥服务，供脚本和服务端调用方免交互登录访问接口
+type APIKeyService struct {
+	keys   *mysql.APIKeyRepository
+	users  *mysql.UserRepository
+	roles  *mysql.RoleRepository
+	rates  *redisrepo.APIKeyRateRepository
+	config APIKeyConfig
+}
+
+// NewAPIKeyService 创建 API 密钥服务
+func NewAPIKeyService(
+	keys *mysql.APIKeyRepository,
+	users *mysql.UserRepository,
+	roles *mysql.RoleRepository,
+	rates *redisrepo.APIKeyRateRepository,
+	config APIKeyConfig,
+) *APIKeyService {
+	// 设置默认值
+	if config.MaxKeysPerUser <= 0 {
+		config.MaxKeysPerUser = DefaultMaxAPIKeysPerUser
+	}
+	if config.DefaultRateLimit <= 0 {
+		config.DefaultRateLimit = DefaultAPIKeyRateLimit
+	}
+	if config.MaxRateLimit <= 0 {
+		config.MaxRateLimit = DefaultMaxAPIKeyRateLimit
+	}
+	if config.DefaultRateLimit > config.MaxRateLimit {
+		config.DefaultRateLimit = config.MaxRateLimit
+	}
+	return &APIKeyService{
+		keys:   keys,
+		users:  users,
+		roles:  roles,
+		rates:  rates,
+		config: config,
+	}
+}
+
+// Create 创建 API 密钥，授权范围只能是基础范围或用户当前拥有的权限
+func (s *APIKeyService) Create(ctx context.Context, userID uint64, req *models.CreateAPIKeyRequest) (*models.CreateAPIKeyResponse, error) {
+	rateLimit := req.RateLimit
+	if rateLimit == 0 {
+		rateLimit = s.config.DefaultRateLimit
+	}
+	if rateLimit > s.config.MaxRateLimit {
+		return nil, ErrInvalidAPIKeyRateLimit
+	}
+
+	permissions, err := s.roles.GetUserPermissions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	scopes, err := normalizeScopes(req.Scopes, permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	count, err := s.keys.CountActiveByUser(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if count >= s.config.MaxKeysPerUser {
+		return nil, ErrAPIKeyLimitReached
+	}
+
+	plaintext, prefix, hash := utils.GenerateAPIKey()
+	key := &models.APIKey{
+		UserID:    userID,
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    prefix,
+		KeyHash:   hash,
+		Scopes:    scopes,
+		RateLimit: rateLimit,
+		CreatedAt: now,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := now.AddDate(0, 0, req.ExpiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+	if err := s.keys.Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id": userID,
+		"key_id":  key.KeyID,
+		"scopes":  scopes,
+	}).Info("API key created")
+
+	return &models.CreateAPIKeyResponse{APIKey: key, Key: plaintext}, nil
+}
+
+// List 列出用户未吊销的 API 密钥
+func (s *APIKeyService) List(ctx context.Context, userID uint64) ([]*models.APIKey, error) {
+	return s.keys.ListByUser(ctx, userID)
+}
+
+// Revoke 吊销 API 密钥，立即生效
+func (s *APIKeyService) Revoke(ctx context.Context, userID, keyID uint64) error {
+	if err := s.keys.Revoke(ctx, userID, keyID, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id": userID,
+		"key_id":  keyID,
+	}).Info("API key revoked")
+	return nil
+}
+
+// ValidateAPIKey 校验 API 密钥并执行限流，返回与访问令牌一致的载荷
+// 载荷中的权限为密钥授权范围与用户当前权限的交集，角色被撤销后密钥随之失去对应权限
+func (s *APIKeyService) ValidateAPIKey(ctx context.Context, plaintext, clientIP string) (*utils.Claims, error) {
+	if !utils.IsAPIKeyFormat(plaintext) {
+		return nil, utils.ErrAPIKeyInvalid
+	}
+
+	key, err := s.keys.GetByHash(ctx, utils.HashAPIKey(plaintext))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, utils.ErrAPIKeyInvalid
+		}
+		return nil, err
+	}
+	now := time.Now()
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && !now.Before(*key.ExpiresAt)) {
+		return nil, utils.ErrAPIKeyInvalid
+	}
+
+	user, err := s.users.GetByID(ctx, key.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, utils.ErrAPIKeyInvalid
+		}
+		return nil, err
+	}
+	// 被禁用或已申请注销的账户，其密钥一并失效
+	if !user.IsActive || user.DeletionAt != nil {
+		return nil, utils.ErrAPIKeyInvalid
+	}
+
+	count, retryAfter, err := s.rates.Hit(ctx, key.KeyID, now, apiKeyRateWindow)
+	if err != nil {
+		return nil, err
+	}
+	if count > int64(key.RateLimit) {
+		return nil, &utils.RateLimitError{RetryAfter: retryAfter}
+	}
+
+	permissions, err := s.roles.GetUserPermissions(ctx, key.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.keys.TouchLastUsed(ctx, key.KeyID, now, clientIP, apiKeyTouchInterval); err != nil {
+		logrus.WithError(err).WithField("key_id", key.KeyID).Warn("Failed to record api key usage")
+	}
+
+	return &utils.Claims{
+		UserID:      strconv.FormatUint(user.UserID, 10),
+		Username:    user.Username,
+		Platform:    models.PlatformAPIKey,
+		Permissions: effectiveScopes(key.Scopes, permissions),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       fmt.Sprintf("api_key:%d", key.KeyID),
+			Subject:  strconv.FormatUint(user.UserID, 10),
+			IssuedAt: jwt.NewNumericDate(now),
+		},
+	}, nil
+}
+
+// normalizeScopes 校验并去重授权范围
+func normalizeScopes(requested, permissions []string) ([]string, error) {
+	granted := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		granted[permission] = true
+	}
+
+	seen := make(map[string]bool, len(requested))
+	scopes := make([]string, 0, len(requested))
+	for _, scope := range requested {
+		scope = strings.TrimSpace(scope)
+		if !apiKeyBaseScopes[scope] && !granted[scope] {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidAPIKeyScope, scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+// effectiveScopes 计算密钥当前生效的授权范围
+func effectiveScopes(scopes, permissions []string) []string {
+	granted := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		granted[permission] = true
+	}
+
+	effective := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if apiKeyBaseScopes[scope] || granted[scope] {
+			effective = append(effective, scope)
+		}
+	}
+	return effective
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository/mysql"
+	redisrepo "github.com/xuchengvcc/restart-life-api/internal/repository/redis"
+	"github.com/xuchengvcc/restart-life-api/internal/utils"
+)
+
+// apiKeyFixture 基于 sqlmock 和 miniredis 的 API 密钥服务
+type apiKeyFixture struct {
+	service *APIKeyService
+	sql     sqlmock.Sqlmock
+	redis   *miniredis.Miniredis
+}
+
+func newAPIKeyFixture(t *testing.T) *apiKeyFixture {
+	t.Helper()
+
+	db, mock := newTestMySQL(t)
+	rdb, server := newTestRedis(t)
+
+	return &apiKeyFixture{
+		service: NewAPIKeyService(
+			mysql.NewAPIKeyRepository(db),
+			mysql.NewUserRepository(db),
+			mysql.NewRoleRepository(db),
+			redisrepo.NewAPIKeyRateRepository(rdb),
+			APIKeyConfig{},
+		),
+		sql:   mock,
+		redis: server,
+	}
+}
+
+// newKey 生成一个属于测试用户的密钥，返回明文和记录
+func newKey(keyID uint64, rateLimit int, scopes ...string) (string, *models.APIKey) {
+	plaintext, prefix, hash := utils.GenerateAPIKey()
+	return plaintext, &models.APIKey{
+		KeyID:     keyID,
+		UserID:    testUserID,
+		Name:      "script",
+		Prefix:    prefix,
+		KeyHash:   hash,
+		Scopes:    scopes,
+		RateLimit: rateLimit,
+		CreatedAt: time.Now(),
+	}
+}
+
+// expectKey 期望按哈希查询一次密钥，之后查询一次密钥所属用户
+func (f *apiKeyFixture) expectKey(key *models.APIKey, user *models.User) {
+	f.sql.ExpectQuery(`FROM api_keys WHERE key_hash = \?`).WithArgs(key.KeyHash).WillReturnRows(sqlmock.NewRows([]string{
+		"key_id", "user_id", "name", "prefix", "key_hash", "scopes", "rate_limit",
+		"last_used_at", "last_used_ip", "expires_at", "revoked_at", "created_at",
+	}).AddRow(
+		key.KeyID, key.UserID, key.Name, key.Prefix, key.KeyHash, strings.Join(key.Scopes, " "), key.RateLimit,
+		nil, nil, timeValue(key.ExpiresAt), timeValue(key.RevokedAt), key.CreatedAt,
+	))
+	f.sql.ExpectQuery(`FROM users WHERE user_id = \?`).WithArgs(user.UserID).WillReturnRows(userRows(user))
+}
+
+// expectAccepted 期望通过限流后查询一次用户权限并记录使用
+func (f *apiKeyFixture) expectAccepted(key *models.APIKey, permissions ...string) {
+	f.sql.ExpectQuery(`FROM user_roles`).WithArgs(key.UserID).WillReturnRows(nameRows(permissions...))
+	f.sql.ExpectExec(`UPDATE api_keys SET last_used_at = \?`).WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+// validate 以测试IP校验密钥
+func (f *apiKeyFixture) validate(plaintext string) (*utils.Claims, error) {
+	return f.service.ValidateAPIKey(context.Background(), plaintext, testIP)
+}
+
+// activeUser 测试用户的正常账户
+func activeUser() *models.User {
+	return &models.User{UserID: testUserID, Username: "alice", Email: testEmail, IsActive: true}
+}
+
+func TestValidateAPIKeyIntersectsScopesWithPermissions(t *testing.T) {
+	f := newAPIKeyFixture(t)
+
+	// 创建时用户拥有 content:read 和 users:write，之后 users:write 所属角色被撤销
+	plaintext, key := newKey(11, 60, models.ScopeGameRead, models.PermissionContentRead, models.PermissionUsersWrite)
+	f.expectKey(key, activeUser())
+	f.expectAccepted(key, models.PermissionContentRead, models.PermissionRolesRead)
+
+	claims, err := f.validate(plaintext)
+	if err != nil {
+		t.Fatalf("ValidateAPIKey() error = %v", err)
+	}
+	want := []string{models.ScopeGameRead, models.PermissionContentRead}
+	if !slices.Equal(claims.Permissions, want) {
+		t.Errorf("ValidateAPIKey() permissions = %v, want %v", claims.Permissions, want)
+	}
+	if claims.UserID != "7" || claims.Platform != models.PlatformAPIKey || claims.ID != "api_key:11" {
+		t.Errorf("ValidateAPIKey() claims = %+v, want user 7 on platform %s with id api_key:11", claims, models.PlatformAPIKey)
+	}
+
+	if err := f.sql.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValidateAPIKeyRejectsInactiveUsers(t *testing.T) {
+	deletionAt := time.Now().Add(7 * 24 * time.Hour)
+
+	tests := []struct {
+		name string
+		user *models.User
+	}{
+		{"disabled", &models.User{UserID: testUserID, Username: "alice", IsActive: false}},
+		{"deletion scheduled", &models.User{UserID: testUserID, Username: "alice", IsActive: true, DeletionAt: &deletionAt}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newAPIKeyFixture(t)
+			plaintext, key := newKey(11, 60, models.ScopeGameRead)
+			f.expectKey(key, tt.user)
+
+			if _, err := f.validate(plaintext); !errors.Is(err, utils.ErrAPIKeyInvalid) {
+				t.Errorf("ValidateAPIKey() error = %v, want ErrAPIKeyInvalid", err)
+			}
+			// 被拒绝的请求不计入限流
+			if keys := f.redis.Keys(); len(keys) > 0 {
+				t.Errorf("ValidateAPIKey() left rate limit keys %v", keys)
+			}
+			if err := f.sql.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestValidateAPIKeyRateLimit(t *testing.T) {
+	f := newAPIKeyFixture(t)
+
+	// 固定窗口按分钟对齐，临近窗口边界时等到下一个窗口开始，避免计数中途清零
+	if remaining := time.Until(time.Now().Truncate(apiKeyRateWindow).Add(apiKeyRateWindow)); remaining < 5*time.Second {
+		time.Sleep(remaining)
+	}
+
+	limited, key := newKey(11, 2, models.ScopeGameRead)
+	for i := 0; i < key.RateLimit; i++ {
+		f.expectKey(key, activeUser())
+		f.expectAccepted(key)
+		if _, err := f.validate(limited); err != nil {
+			t.Fatalf("ValidateAPIKey() request %d error = %v", i+1, err)
+		}
+	}
+
+	f.expectKey(key, activeUser())
+	_, err := f.validate(limited)
+	var rateErr *utils.RateLimitError
+	if !errors.As(err, &rateErr) || !errors.Is(err, utils.ErrRateLimited) {
+		t.Fatalf("ValidateAPIKey() over the limit error = %v, want RateLimitError", err)
+	}
+	if rateErr.RetryAfter <= 0 || rateErr.RetryAfter > apiKeyRateWindow {
+		t.Errorf("RetryAfter = %v, want within (0, %v]", rateErr.RetryAfter, apiKeyRateWindow)
+	}
+
+	// 计数只保留到窗口结束
+	keys := f.redis.Keys()
+	if len(keys) != 1 {
+		t.Fatalf("rate limit keys = %v, want one for the current window", keys)
+	}
+	if ttl := f.redis.TTL(keys[0]); ttl <= 0 || ttl > apiKeyRateWindow {
+		t.Errorf("rate limit key TTL = %v, want within (0, %v]", ttl, apiKeyRateWindow)
+	}
+
+	// 限流按密钥计数，同一用户的其他密钥不受影响
+	other, otherKey := newKey(12, 2, models.ScopeGameRead)
+	f.expectKey(otherKey, activeUser())
+	f.expectAccepted(otherKey)
+	if _, err := f.validate(other); err != nil {
+		t.Errorf("ValidateAPIKey() with another key error = %v", err)
+	}
+
+	// 计数键在窗口结束后过期，下一个窗口重新计数
+	f.redis.FastForward(apiKeyRateWindow)
+	if f.redis.Exists(keys[0]) {
+		t.Errorf("rate limit key %s outlived its window", keys[0])
+	}
+
+	if err := f.sql.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,702 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository"
+	"github.com/xuchengvcc/restart-life-api/internal/repository/mysql"
+	redisrepo "github.com/xuchengvcc/restart-life-api/internal/repository/redis"
+	"github.com/xuchengvcc/restart-life-api/internal/services/providers"
+	"github.com/xuchengvcc/restart-life-api/internal/utils"
+)
+
+// 认证业务错误
+var (
+	// ErrUsernameTaken 用户名已被占用
+	ErrUsernameTaken = errors.New("username already taken")
+	// ErrEmailTaken 邮箱已被占用
+	ErrEmailTaken = errors.New("email already taken")
+	// ErrInvalidCredentials 用户名或密码错误
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrUserDisabled 账户已被禁用
+	ErrUserDisabled = errors.New("user is disabled")
+	// ErrUserNotFound 用户不存在
+	ErrUserNotFound = errors.New("user not found")
+	// ErrInvalidUsername 用户名格式不合法
+	ErrInvalidUsername = errors.New("invalid username")
+	// ErrNotGuest 账户不是游客账户
+	ErrNotGuest = errors.New("user is not a guest")
+	// ErrProviderNotSupported 不支持的第三方登录方式
+	ErrProviderNotSupported = errors.New("login provider not supported")
+	// ErrInvalidOAuthState OAuth state 无效、已过期或已使用
+	ErrInvalidOAuthState = errors.New("invalid oauth state")
+	// ErrPasswordTooLong 密码按 UTF-8 编码超过 72 字节
+	ErrPasswordTooLong = utils.ErrPasswordTooLong
+)
+
+// guestUsernamePrefix 游客用户名前缀
+const guestUsernamePrefix = "guest_"
+
+// generatedUsernameAttempts 系统生成用户名冲突时的最大重试次数
+const generatedUsernameAttempts = 3
+
+// oauthStateTTL OAuth 授权请求有效期
+const oauthStateTTL = 10 * time.Minute
+
+// usernamePattern 用户名仅允许字母、数字、下划线、点和连字符，禁止 @ 以免与邮箱登录混淆
+var usernamePattern = regexp.MustCompile(`^[\p{L}\p{N}_.-]{3,50}$`)
+
+// dummyPasswordHash 用于用户不存在时的等时比较
+const dummyPasswordHash = "$2a$12$2q0hvYnCYizcrKItfQZKE.WJ9QZx3f5ls3ugxcruMXqhrfaziAEOi"
+
+// AuthService 认证服务
+type AuthService struct {
+	users             *mysql.UserRepository
+	identities        *mysql.IdentityRepository
+	oauthStates       *redisrepo.OAuthStateRepository
+	tokens            *TokenService
+	twoFactor         *TwoFactorService
+	loginGuard        *LoginGuard
+	providers         map[string]providers.LoginProvider
+	redirectProviders map[string]providers.RedirectProvider
+}
+
+// NewAuthService 创建认证服务
+func NewAuthService(
+	users *mysql.UserRepository,
+	identities *mysql.IdentityRepository,
+	oauthStates *redisrepo.OAuthStateRepository,
+	tokens *TokenService,
+	twoFactor *TwoFactorService,
+	loginGuard *LoginGuard,
+) *AuthService {
+	return &AuthService{
+		users:             users,
+		identities:        identities,
+		oauthStates:       oauthStates,
+		tokens:            tokens,
+		twoFactor:         twoFactor,
+		loginGuard:        loginGuard,
+		providers:         make(map[string]providers.LoginProvider),
+		redirectProviders: make(map[string]providers.RedirectProvider),
+	}
+}
+
+// RegisterProvider 注册第三方登录提供方
+func (s *AuthService) RegisterProvider(provider providers.LoginProvider) {
+	s.providers[provider.Name()] = provider
+}
+
+// RegisterRedirectProvider 注册基于授权码跳转的第三方登录提供方
+func (s *AuthService) RegisterRedirectProvider(provider providers.RedirectProvider) {
+	s.redirectProviders[provider.Name()] = provider
+}
+
+// Register 注册新用户并签发令牌
+func (s *AuthService) Register(ctx context.Context, req *models.RegisterRequest, device models.DeviceInfo) (*models.AuthResponse, error) {
+	username := strings.TrimSpace(req.Username)
+	if !usernamePattern.MatchString(username) {
+		return nil, ErrInvalidUsername
+	}
+
+	hash, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Username:     username,
+		Email:        strings.ToLower(strings.TrimSpace(req.Email)),
+		PasswordHash: hash,
+		IsActive:     true,
+	}
+
+	if err := s.users.Create(ctx, user); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicateUsername):
+			return nil, ErrUsernameTaken
+		case errors.Is(err, repository.ErrDuplicateEmail):
+			return nil, ErrEmailTaken
+		default:
+			return nil, err
+		}
+	}
+
+	// 重新读取以获取数据库默认值（创建时间等）
+	created, err := s.users.GetByID(ctx, user.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load created user: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id":  created.UserID,
+		"platform": device.Platform,
+	}).Info("User registered")
+
+	return s.issueAuthResponse(ctx, created, device)
+}
+
+// Login 用户名（或邮箱）+ 密码登录
+// 已启用两步验证的账户不会直接签发令牌，而是返回登录挑战
+func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest, device models.DeviceInfo) (*models.AuthResponse, *models.TwoFactorChallenge, error) {
+	user, err := s.findByAccount(ctx, req.Username)
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		return nil, nil, err
+	}
+
+	subject := unknownAccountSubject(req.Username)
+	if user != nil {
+		subject = userSubject(user.UserID)
+	}
+	if err := s.loginGuard.Check(ctx, subject, device.IP); err != nil {
+		return nil, nil, err
+	}
+
+	if user == nil {
+		// 执行一次哈希比较，避免通过响应时间枚举用户名
+		utils.CheckPassword(dummyPasswordHash, req.Password)
+		return nil, nil, s.loginFailed(ctx, nil, subject, device)
+	}
+	if !utils.CheckPassword(user.PasswordHash, req.Password) {
+		return nil, nil, s.loginFailed(ctx, user, subject, device)
+	}
+	if !user.IsActive {
+		return nil, nil, ErrUserDisabled
+	}
+
+	// 失败计数在整个登录完成后才清除，密码正确但未通过两步验证不能重置锁定
+	challenge, err := s.twoFactorChallenge(ctx, user, device)
+	if err != nil || challenge != nil {
+		return nil, challenge, err
+	}
+
+	s.touchLastLogin(ctx, user, device.IP)
+
+	logrus.WithFields(logrus.Fields{
+		"user_id":  user.UserID,
+		"platform": device.Platform,
+	}).Info("User login successful")
+
+	resp, err := s.issueAuthResponse(ctx, user, device)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.resetLoginFailures(ctx, user.UserID)
+	return resp, nil, nil
+}
+
+// CompleteTwoFactorLogin 提交两步验证码完成登录
+// 验证码错误计入用户的登录失败次数，避免通过反复登录获取新挑战来穷举验证码
+func (s *AuthService) CompleteTwoFactorLogin(ctx context.Context, req *models.TwoFactorLoginRequest) (*models.AuthResponse, error) {
+	state, err := s.twoFactor.OpenLoginChallenge(ctx, req.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	subject := userSubject(state.UserID)
+	if err := s.loginGuard.Check(ctx, subject, ""); err != nil {
+		return nil, err
+	}
+	if err := s.twoFactor.CompleteLoginChallenge(ctx, req.ChallengeToken, state, req.Code); err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			return nil, s.twoFactorFailed(ctx, state, subject)
+		}
+		return nil, err
+	}
+
+	user, err := s.GetUser(ctx, state.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrUserDisabled
+	}
+
+	s.touchLastLogin(ctx, user, state.Device.IP)
+
+	logrus.WithFields(logrus.Fields{
+		"user_id":  user.UserID,
+		"platform": state.Device.Platform,
+	}).Info("Two-factor login successful")
+
+	resp, err := s.issueAuthResponse(ctx, user, state.Device)
+	if err != nil {
+		return nil, err
+	}
+	s.resetLoginFailures(ctx, user.UserID)
+	return resp, nil
+}
+
+// GuestLogin 游客登录，设备首次登录时自动创建绑定该设备的游客账户
+func (s *AuthService) GuestLogin(ctx context.Context, req *models.GuestLoginRequest, device models.DeviceInfo) (*models.AuthResponse, error) {
+	device.DeviceID = req.DeviceID
+
+	user, err := s.users.GetByDeviceID(ctx, req.DeviceID)
+	switch {
+	case err == nil:
+	case errors.Is(err, repository.ErrNotFound):
+		user, err = s.createGuest(ctx, req.DeviceID)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	if !user.IsActive {
+		return nil, ErrUserDisabled
+	}
+
+	s.touchLastLogin(ctx, user, device.IP)
+
+	logrus.WithFields(logrus.Fields{
+		"user_id":  user.UserID,
+		"platform": device.Platform,
+	}).Info("Guest login successful")
+
+	return s.issueAuthResponse(ctx, user, device)
+}
+
+// UpgradeGuest 将游客账户升级为正式账户，保留其全部角色
+func (s *AuthService) UpgradeGuest(ctx context.Context, userID uint64, req *models.UpgradeGuestRequest, device models.DeviceInfo) (*models.AuthResponse, error) {
+	user, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsGuest {
+		return nil, ErrNotGuest
+	}
+
+	username := strings.TrimSpace(req.Username)
+	if username == "" {
+		username = user.Username
+	} else if !usernamePattern.MatchString(username) {
+		return nil, ErrInvalidUsername
+	}
+
+	hash, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if err := s.users.UpgradeGuest(ctx, userID, username, email, hash); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicateUsername):
+			return nil, ErrUsernameTaken
+		case errors.Is(err, repository.ErrDuplicateEmail):
+			return nil, ErrEmailTaken
+		case errors.Is(err, repository.ErrNotFound):
+			return nil, ErrNotGuest
+		default:
+			return nil, err
+		}
+	}
+
+	upgraded, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.WithField("user_id", userID).Info("Guest account upgraded")
+
+	return s.issueAuthResponse(ctx, upgraded, device)
+}
+
+// LoginWithProvider 使用第三方凭据登录，首次登录时自动创建并关联账户
+func (s *AuthService) LoginWithProvider(ctx context.Context, providerName, credential string, device models.DeviceInfo) (*models.AuthResponse, *models.TwoFactorChallenge, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, nil, ErrProviderNotSupported
+	}
+
+	identity, err := provider.Authenticate(ctx, credential)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.loginWithIdentity(ctx, identity, device)
+}
+
+// BeginOAuth 发起授权码 + PKCE 登录，返回提供方授权页地址
+func (s *AuthService) BeginOAuth(ctx context.Context, providerName string, device models.DeviceInfo) (*models.OAuthAuthorizeResponse, error) {
+	provider, ok := s.redirectProviders[providerName]
+	if !ok {
+		return nil, ErrProviderNotSupported
+	}
+
+	state := utils.RandomHex(16)
+	record := &models.OAuthState{
+		Provider:     providerName,
+		CodeVerifier: providers.NewCodeVerifier(),
+		Nonce:        utils.RandomHex(16),
+		Platform:     device.Platform,
+	}
+	if err := s.oauthStates.Save(ctx, state, record, oauthStateTTL); err != nil {
+		return nil, err
+	}
+
+	return &models.OAuthAuthorizeResponse{
+		AuthorizationURL: provider.AuthorizationURL(state, record.Nonce, providers.CodeChallengeS256(record.CodeVerifier)),
+		State:            state,
+		ExpiresIn:        int64(oauthStateTTL.Seconds()),
+	}, nil
+}
+
+// CompleteOAuth 处理授权回调：校验 state，换取并校验 ID 令牌后登录
+func (s *AuthService) CompleteOAuth(ctx context.Context, providerName string, req *models.OAuthCallbackRequest, device models.DeviceInfo) (*models.AuthResponse, *models.TwoFactorChallenge, error) {
+	provider, ok := s.redirectProviders[providerName]
+	if !ok {
+		return nil, nil, ErrProviderNotSupported
+	}
+
+	record, err := s.oauthStates.Consume(ctx, req.State)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, ErrInvalidOAuthState
+		}
+		return nil, nil, err
+	}
+	if record.Provider != providerName {
+		return nil, nil, ErrInvalidOAuthState
+	}
+
+	identity, err := provider.Exchange(ctx, req.Code, record.CodeVerifier, record.Nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 浏览器回调通常不带平台头，沿用发起授权时的平台
+	if record.Platform != "" {
+		device.Platform = record.Platform
+	}
+	return s.loginWithIdentity(ctx, identity, device)
+}
+
+// Refresh 使用刷新令牌换取新的令牌对，旧刷新令牌随即失效
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string, device models.DeviceInfo) (*models.AuthResponse, error) {
+	record, err := s.tokens.RotateRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.GetUser(ctx, record.UserID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	if !user.IsActive {
+		if err := s.tokens.RevokeFamily(ctx, record.FamilyID); err != nil {
+			logrus.WithError(err).WithField("user_id", user.UserID).Warn("Failed to revoke token family of disabled user")
+		}
+		return nil, ErrUserDisabled
+	}
+
+	pair, err := s.tokens.IssueRotatedTokenPair(ctx, user, record, device)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AuthResponse{
+		TokenPair: *pair,
+		User:      user.ToUserInfo(),
+	}, nil
+}
+
+// Logout 登出当前会话
+func (s *AuthService) Logout(ctx context.Context, claims *utils.Claims) error {
+	if err := s.tokens.RevokeSession(ctx, claims); err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id":  claims.UserID,
+		"platform": claims.Platform,
+	}).Info("User logged out")
+	return nil
+}
+
+// LogoutAll 登出用户的所有设备
+func (s *AuthService) LogoutAll(ctx context.Context, userID uint64) error {
+	if err := s.tokens.RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+
+	logrus.WithField("user_id", userID).Info("User logged out from all devices")
+	return nil
+}
+
+// ListSessions 列出用户的登录会话，并标记发起请求的会话
+func (s *AuthService) ListSessions(ctx context.Context, userID uint64, currentSessionID string) ([]*models.Session, error) {
+	sessions, err := s.tokens.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		session.Current = session.SessionID == currentSessionID
+	}
+	return sessions, nil
+}
+
+// RevokeSession 吊销用户的指定会话（远程登出某台设备）
+func (s *AuthService) RevokeSession(ctx context.Context, userID uint64, sessionID string) error {
+	if err := s.tokens.RevokeUserSession(ctx, userID, sessionID); err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id":    userID,
+		"session_id": sessionID,
+	}).Info("User session revoked")
+	return nil
+}
+
+// GetUser 获取用户信息
+func (s *AuthService) GetUser(ctx context.Context, userID uint64) (*models.User, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// findByAccount 根据用户名或邮箱查找用户
+func (s *AuthService) findByAccount(ctx context.Context, account string) (*models.User, error) {
+	account = strings.TrimSpace(account)
+
+	var (
+		user *models.User
+		err  error
+	)
+	if strings.Contains(account, "@") {
+		user, err = s.users.GetByEmail(ctx, strings.ToLower(account))
+	} else {
+		user, err = s.users.GetByUsername(ctx, account)
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// createGuest 创建绑定设备的游客账户
+func (s *AuthService) createGuest(ctx context.Context, deviceID string) (*models.User, error) {
+	user := &models.User{
+		IsActive: true,
+		IsGuest:  true,
+		DeviceID: deviceID,
+	}
+
+	err := createWithGeneratedUsername(guestUsernamePrefix, func(username string) error {
+		user.Username = username
+		return s.users.Create(ctx, user)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicateDeviceID) {
+			// 并发请求已为该设备创建了游客账户
+			return s.users.GetByDeviceID(ctx, deviceID)
+		}
+		return nil, err
+	}
+
+	logrus.WithField("user_id", user.UserID).Info("Guest account created")
+	return s.users.GetByID(ctx, user.UserID)
+}
+
+// loginWithIdentity 使用第三方身份登录，已启用两步验证时返回登录挑战
+func (s *AuthService) loginWithIdentity(ctx context.Context, identity *providers.Identity, device models.DeviceInfo) (*models.AuthResponse, *models.TwoFactorChallenge, error) {
+	user, err := s.findOrCreateIdentityUser(ctx, identity)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !user.IsActive {
+		return nil, nil, ErrUserDisabled
+	}
+
+	challenge, err := s.twoFactorChallenge(ctx, user, device)
+	if err != nil || challenge != nil {
+		return nil, challenge, err
+	}
+
+	s.touchLastLogin(ctx, user, device.IP)
+
+	logrus.WithFields(logrus.Fields{
+		"user_id":  user.UserID,
+		"provider": identity.Provider,
+		"platform": device.Platform,
+	}).Info("Third-party login successful")
+
+	resp, err := s.issueAuthResponse(ctx, user, device)
+	return resp, nil, err
+}
+
+// twoFactorChallenge 用户已启用两步验证时创建登录挑战，未启用时返回 nil
+// 用户因验证码错误次数过多被锁定期间不再签发新的挑战
+func (s *AuthService) twoFactorChallenge(ctx context.Context, user *models.User, device models.DeviceInfo) (*models.TwoFactorChallenge, error) {
+	enabled, err := s.twoFactor.IsEnabled(ctx, user.UserID)
+	if err != nil || !enabled {
+		return nil, err
+	}
+	if err := s.loginGuard.Check(ctx, userSubject(user.UserID), ""); err != nil {
+		return nil, err
+	}
+
+	challenge, err := s.twoFactor.CreateLoginChallenge(ctx, user.UserID, device)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id":  user.UserID,
+		"platform": device.Platform,
+	}).Info("Two-factor challenge issued")
+	return challenge, nil
+}
+
+// touchLastLogin 更新最后登录时间和IP，失败不影响登录
+func (s *AuthService) touchLastLogin(ctx context.Context, user *models.User, ip string) {
+	now := time.Now()
+	if err := s.users.UpdateLastLogin(ctx, user.UserID, now, ip); err != nil {
+		logrus.WithError(err).WithField("user_id", user.UserID).Warn("Failed to update last login")
+	}
+	user.LastLogin = &now
+	user.LastLoginIP = ip
+	user.FailedLogins = 0
+}
+
+// loginFailed 记录一次密码错误，user 为空表示账户不存在
+func (s *AuthService) loginFailed(ctx context.Context, user *models.User, subject string, device models.DeviceInfo) error {
+	if err := s.loginGuard.RecordFailure(ctx, subject, device.IP); err != nil {
+		return err
+	}
+
+	fields := logrus.Fields{"ip": device.IP, "platform": device.Platform}
+	if user != nil {
+		fields["user_id"] = user.UserID
+		if err := s.users.RecordFailedLogin(ctx, user.UserID, time.Now(), device.IP); err != nil {
+			logrus.WithError(err).WithField("user_id", user.UserID).Warn("Failed to record failed login")
+		}
+	}
+	logrus.WithFields(fields).Info("Login failed: invalid credentials")
+
+	return ErrInvalidCredentials
+}
+
+// twoFactorFailed 记录一次两步验证失败，与密码错误一样计入登录失败次数
+func (s *AuthService) twoFactorFailed(ctx context.Context, state *models.LoginChallengeState, subject string) error {
+	if err := s.loginGuard.RecordFailure(ctx, subject, state.Device.IP); err != nil {
+		return err
+	}
+	if err := s.users.RecordFailedLogin(ctx, state.UserID, time.Now(), state.Device.IP); err != nil {
+		logrus.WithError(err).WithField("user_id", state.UserID).Warn("Failed to record failed login")
+	}
+	logrus.WithFields(logrus.Fields{
+		"user_id":  state.UserID,
+		"ip":       state.Device.IP,
+		"platform": state.Device.Platform,
+	}).Info("Login failed: invalid two-factor code")
+
+	return ErrInvalidTwoFactorCode
+}
+
+// resetLoginFailures 登录完成后清除用户的失败计数
+func (s *AuthService) resetLoginFailures(ctx context.Context, userID uint64) {
+	if err := s.loginGuard.Reset(ctx, userSubject(userID)); err != nil {
+		logrus.WithError(err).WithField("user_id", userID).Warn("Failed to reset login failures")
+	}
+}
+
+// findOrCreateIdentityUser 查找第三方身份关联的用户，不存在时按 unionid 关联或创建新用户
+func (s *AuthService) findOrCreateIdentityUser(ctx context.Context, identity *providers.Identity) (*models.User, error) {
+	link, err := s.identities.GetBySubject(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		if err := s.identities.TouchLastUsed(ctx, link.IdentityID, time.Now()); err != nil {
+			logrus.WithError(err).WithField("identity_id", link.IdentityID).Warn("Failed to update identity last used")
+		}
+		return s.GetUser(ctx, link.UserID)
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	newLink := &models.UserIdentity{
+		Provider:        identity.Provider,
+		ProviderSubject: identity.Subject,
+		UnionID:         identity.UnionID,
+		Email:           identity.Email,
+	}
+
+	// 同一开放平台下的其他应用已关联过该用户
+	if identity.UnionID != "" {
+		sibling, err := s.identities.GetByUnionID(ctx, identity.Provider, identity.UnionID)
+		switch {
+		case err == nil:
+			newLink.UserID = sibling.UserID
+			if err := s.identities.Create(ctx, newLink); err != nil && !errors.Is(err, repository.ErrDuplicateIdentity) {
+				return nil, err
+			}
+			return s.GetUser(ctx, sibling.UserID)
+		case !errors.Is(err, repository.ErrNotFound):
+			return nil, err
+		}
+	}
+
+	user := &models.User{IsActive: true}
+	err = createWithGeneratedUsername(identity.Provider+"_", func(username string) error {
+		user.Username = username
+		return s.identities.CreateWithUser(ctx, user, newLink)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicateIdentity) {
+			// 并发请求已完成关联
+			return s.findOrCreateIdentityUser(ctx, identity)
+		}
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id":  user.UserID,
+		"provider": identity.Provider,
+	}).Info("Third-party account created")
+
+	return s.GetUser(ctx, user.UserID)
+}
+
+// createWithGeneratedUsername 使用系统生成的用户名创建账户，用户名冲突时重试
+func createWithGeneratedUsername(prefix string, create func(username string) error) error {
+	for attempt := 0; attempt < generatedUsernameAttempts; attempt++ {
+		err := create(prefix + utils.RandomHex(5))
+		if !errors.Is(err, repository.ErrDuplicateUsername) {
+			return err
+		}
+	}
+	return fmt.Errorf("failed to generate unique username after %d attempts", generatedUsernameAttempts)
+}
+
+// issueAuthResponse 为用户签发令牌并组装响应
+func (s *AuthService) issueAuthResponse(ctx context.Context, user *models.User, device models.DeviceInfo) (*models.AuthResponse, error) {
+	pair, err := s.tokens.IssueTokenPair(ctx, user, device)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AuthResponse{
+		TokenPair: *pair,
+		User:      user.ToUserInfo(),
+	}, nil
+}
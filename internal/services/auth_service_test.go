@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository/mysql"
+	redisrepo "github.com/xuchengvcc/restart-life-api/internal/repository/redis"
+	"github.com/xuchengvcc/restart-life-api/internal/utils"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	testPassword    = "correct horse battery staple"
+	testIP          = "203.0.113.5"
+	testMaxFailures = 3
+	testLockout     = time.Minute
+)
+
+// authFixture 基于 sqlmock 和 miniredis 的认证服务，账户连续失败 3 次后锁定
+type authFixture struct {
+	service      *AuthService
+	sql          sqlmock.Sqlmock
+	redis        *miniredis.Miniredis
+	passwordHash string
+	totpSecret   string
+	sealedSecret string
+}
+
+func newAuthFixture(t *testing.T) *authFixture {
+	t.Helper()
+
+	db, mock := newTestMySQL(t)
+	rdb, server := newTestRedis(t)
+
+	secrets, err := utils.NewSecretBox("jwt-secret", "totp")
+	if err != nil {
+		t.Fatalf("NewSecretBox() error = %v", err)
+	}
+	totpSecret := utils.GenerateTOTPSecret()
+	sealedSecret, err := secrets.Seal(totpSecret)
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	// 测试中使用最低计算成本，避免每次密码比较耗时过长
+	hash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() error = %v", err)
+	}
+
+	return &authFixture{
+		service: NewAuthService(
+			mysql.NewUserRepository(db),
+			mysql.NewIdentityRepository(db),
+			redisrepo.NewOAuthStateRepository(rdb),
+			newTestTokenService(db, rdb),
+			NewTwoFactorService(mysql.NewTOTPRepository(db), redisrepo.NewTwoFactorRepository(rdb), secrets),
+			NewLoginGuard(redisrepo.NewLoginAttemptRepository(rdb), LoginGuardConfig{
+				MaxAccountFailures: testMaxFailures,
+				BaseLockout:        testLockout,
+			}),
+		),
+		sql:          mock,
+		redis:        server,
+		passwordHash: string(hash),
+		totpSecret:   totpSecret,
+		sealedSecret: sealedSecret,
+	}
+}
+
+// expectUser 期望按指定条件查询一次用户
+func (f *authFixture) expectUser(where string, arg interface{}) {
+	f.sql.ExpectQuery(`FROM users WHERE ` + where + ` = \?`).WithArgs(arg).WillReturnRows(userRows(&models.User{
+		UserID:       testUserID,
+		Username:     "alice",
+		Email:        testEmail,
+		PasswordHash: f.passwordHash,
+		IsActive:     true,
+	}))
+}
+
+// expectTOTP 期望查询一次两步验证配置
+func (f *authFixture) expectTOTP(enabled bool) {
+	rows := sqlmock.NewRows([]string{"user_id", "secret_encrypted", "enabled_at", "created_at"})
+	if enabled {
+		rows.AddRow(testUserID, f.sealedSecret, time.Now(), time.Now())
+	}
+	f.sql.ExpectQuery(`FROM user_totp WHERE user_id = \?`).WithArgs(testUserID).WillReturnRows(rows)
+}
+
+// expectFailureRecorded 期望记录一次登录失败
+func (f *authFixture) expectFailureRecorded() {
+	f.sql.ExpectExec(`UPDATE users SET failed_login_count = failed_login_count \+ 1`).
+		WithArgs(sqlmock.AnyArg(), testIP, testUserID).WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+// expectTokensIssued 期望更新最后登录信息并签发令牌
+func (f *authFixture) expectTokensIssued() {
+	f.sql.ExpectExec(`UPDATE users SET last_login = \?`).
+		WithArgs(sqlmock.AnyArg(), testIP, testUserID).WillReturnResult(sqlmock.NewResult(0, 1))
+	f.sql.ExpectQuery(`FROM user_roles`).WithArgs(testUserID).WillReturnRows(nameRows(models.PermissionContentRead))
+}
+
+// login 使用用户名和密码登录
+func (f *authFixture) login(password string) (*models.AuthResponse, *models.TwoFactorChallenge, error) {
+	return f.service.Login(context.Background(), &models.LoginRequest{Username: "alice", Password: password},
+		models.DeviceInfo{Platform: "ios", IP: testIP})
+}
+
+// failPassword 密码错误登录 n 次
+func (f *authFixture) failPassword(t *testing.T, n int) {
+	t.Helper()
+
+	for i := 0; i < n; i++ {
+		f.expectUser("username", "alice")
+		f.expectFailureRecorded()
+		if _, _, err := f.login("wrong password"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("Login() with wrong password error = %v, want ErrInvalidCredentials", err)
+		}
+	}
+}
+
+// loginWithoutTwoFactor 未启用两步验证的账户使用正确密码登录
+func (f *authFixture) loginWithoutTwoFactor() error {
+	f.expectUser("username", "alice")
+	f.expectTOTP(false)
+	f.expectTokensIssued()
+	_, _, err := f.login(testPassword)
+	return err
+}
+
+// challenge 已启用两步验证的账户使用正确密码登录，返回登录挑战
+func (f *authFixture) challenge(t *testing.T) string {
+	t.Helper()
+
+	f.expectUser("username", "alice")
+	f.expectTOTP(true)
+	_, challenge, err := f.login(testPassword)
+	if err != nil || challenge == nil {
+		t.Fatalf("Login() = %v, %v, want a two-factor challenge", challenge, err)
+	}
+	return challenge.ChallengeToken
+}
+
+// completeTwoFactor 提交两步验证码
+func (f *authFixture) completeTwoFactor(token, code string) error {
+	_, err := f.service.CompleteTwoFactorLogin(context.Background(), &models.TwoFactorLoginRequest{
+		ChallengeToken: token,
+		Code:           code,
+	})
+	return err
+}
+
+// validCode 当前时间步的验证码
+func (f *authFixture) validCode(t *testing.T) string {
+	t.Helper()
+
+	code, err := utils.TOTPCode(f.totpSecret, utils.TOTPStep(time.Now()))
+	if err != nil {
+		t.Fatalf("TOTPCode() error = %v", err)
+	}
+	return code
+}
+
+// invalidCode 在允许的时钟偏差内都不匹配的验证码
+func (f *authFixture) invalidCode(t *testing.T) string {
+	t.Helper()
+
+	for i := 0; ; i++ {
+		code := fmt.Sprintf("%0*d", utils.TOTPDigits, i)
+		if _, ok := utils.ValidateTOTP(f.totpSecret, code, time.Now()); !ok {
+			return code
+		}
+	}
+}
+
+// assertLocked 断言账户处于锁定期
+func assertLocked(t *testing.T, err error) {
+	t.Helper()
+
+	var locked *LoginLockedError
+	if !errors.As(err, &locked) || !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("error = %v, want ErrAccountLocked", err)
+	}
+	if locked.RetryAfter <= 0 || locked.RetryAfter > testLockout {
+		t.Errorf("RetryAfter = %v, want within (0, %v]", locked.RetryAfter, testLockout)
+	}
+}
+
+func TestLoginLocksAccountAfterThreshold(t *testing.T) {
+	f := newAuthFixture(t)
+
+	// 达到阈值前只返回密码错误
+	f.failPassword(t, testMaxFailures-1)
+	f.expectUser("username", "alice")
+	f.expectFailureRecorded()
+	if _, _, err := f.login("wrong password"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Login() with wrong password error = %v, want ErrInvalidCredentials", err)
+	}
+
+	// 第 3 次失败后锁定，锁定期内正确的密码也被拒绝，且不再比较密码
+	f.expectUser("username", "alice")
+	_, _, err := f.login(testPassword)
+	assertLocked(t, err)
+
+	// 用户名和邮箱登录共用同一计数
+	f.expectUser("email", testEmail)
+	_, _, err = f.service.Login(context.Background(), &models.LoginRequest{Username: testEmail, Password: testPassword},
+		models.DeviceInfo{Platform: "ios", IP: testIP})
+	assertLocked(t, err)
+
+	// 锁定期结束后可以正常登录
+	f.redis.FastForward(testLockout)
+	if err := f.loginWithoutTwoFactor(); err != nil {
+		t.Fatalf("Login() after lockout error = %v", err)
+	}
+
+	if err := f.sql.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLoginResetsFailuresOnlyAfterFullLogin(t *testing.T) {
+	f := newAuthFixture(t)
+
+	// 完整登录后计数清零，之后可以再失败 2 次而不被锁定
+	f.failPassword(t, testMaxFailures-1)
+	if err := f.loginWithoutTwoFactor(); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	f.failPassword(t, testMaxFailures-1)
+	if err := f.loginWithoutTwoFactor(); err != nil {
+		t.Fatalf("Login() after reset error = %v", err)
+	}
+
+	// 通过两步验证完成登录后计数清零
+	f.failPassword(t, testMaxFailures-1)
+	token := f.challenge(t)
+	f.expectTOTP(true)
+	f.expectUser("user_id", testUserID)
+	f.expectTokensIssued()
+	if err := f.completeTwoFactor(token, f.validCode(t)); err != nil {
+		t.Fatalf("CompleteTwoFactorLogin() error = %v", err)
+	}
+	f.failPassword(t, testMaxFailures-1)
+	if err := f.loginWithoutTwoFactor(); err != nil {
+		t.Fatalf("Login() after two-factor reset error = %v", err)
+	}
+
+	// 密码正确但尚未通过两步验证，计数不清零
+	f.failPassword(t, testMaxFailures-1)
+	f.challenge(t)
+	f.failPassword(t, 1)
+	f.expectUser("username", "alice")
+	_, _, err := f.login(testPassword)
+	assertLocked(t, err)
+
+	if err := f.sql.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTwoFactorFailuresCountTowardLockout(t *testing.T) {
+	f := newAuthFixture(t)
+
+	// 密码错误与验证码错误共用同一计数
+	f.failPassword(t, 1)
+	token := f.challenge(t)
+	for i := 1; i < testMaxFailures; i++ {
+		f.expectTOTP(true)
+		f.expectFailureRecorded()
+		if err := f.completeTwoFactor(token, f.invalidCode(t)); !errors.Is(err, ErrInvalidTwoFactorCode) {
+			t.Fatalf("CompleteTwoFactorLogin() with wrong code error = %v, want ErrInvalidTwoFactorCode", err)
+		}
+	}
+
+	// 锁定期内正确的验证码也被拒绝，也无法通过重新登录获取新挑战
+	assertLocked(t, f.completeTwoFactor(token, f.validCode(t)))
+	f.expectUser("username", "alice")
+	_, _, err := f.login(testPassword)
+	assertLocked(t, err)
+
+	if err := f.sql.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
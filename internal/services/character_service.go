@@ -0,0 +1,667 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/xuchengvcc/restart-life-api/internal/catalog"
+	"github.com/xuchengvcc/restart-life-api/internal/generator"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/pagination"
+	"github.com/xuchengvcc/restart-life-api/internal/repository"
+	"github.com/xuchengvcc/restart-life-api/internal/repository/mysql"
+	redisrepo "github.com/xuchengvcc/restart-life-api/internal/repository/redis"
+)
+
+// 技能训练参数
+const (
+	// skillTrainBaseGain 天赋中等、技能为 0 时单次训练的提升
+	skillTrainBaseGain = 6
+	// skillTrainRetries 技能被并发修改时的重试次数
+	skillTrainRetries = 3
+)
+
+// skillTraitDrift 训练各项技能对性格倾向的影响，作为生活事件使性格缓慢变化
+var skillTraitDrift = map[string]models.TraitDelta{
+	models.SkillAcademic: {Extraversion: -0.005},
+	models.SkillSocial:   {Extraversion: 0.01},
+	models.SkillAthletic: {Bravery: 0.01},
+	models.SkillArtistic: {Optimism: 0.005},
+	models.SkillBusiness: {Optimism: 0.005, Bravery: 0.005},
+}
+
+// 角色默认配置
+const (
+	DefaultCharacterDraftTTL         = 30 * time.Minute
+	DefaultCharacterMaxRerolls       = 10
+	DefaultCharacterArchiveRetention = 30 * 24 * time.Hour
+	DefaultCharacterPurgeInterval    = time.Hour
+	DefaultSkillTrainingCooldown     = time.Hour
+)
+
+// characterPurgeBatchSize 清理任务每批删除的角色数
+const characterPurgeBatchSize = 100
+
+// 角色业务错误
+var (
+	// ErrCharacterNotFound 角色不存在或不属于当前用户
+	ErrCharacterNotFound = errors.New("character not found")
+	// ErrInvalidBirthYear 角色出生年份超出参考数据支持的范围
+	ErrInvalidBirthYear = errors.New("invalid birth year")
+	// ErrCountryNotAvailable 出生国家在所选年份尚未成立或已经解体
+	ErrCountryNotAvailable = errors.New("country not available in birth year")
+	// ErrInvalidCharacterName 角色名为空
+	ErrInvalidCharacterName = errors.New("invalid character name")
+	// ErrInvalidCountry 出生国家不在参考数据中
+	ErrInvalidCountry = errors.New("invalid country")
+	// ErrCharacterDraftNotFound 角色草稿不存在或已过期
+	ErrCharacterDraftNotFound = errors.New("character draft not found")
+	// ErrRerollLimitReached 草稿的重新生成次数已用完
+	ErrRerollLimitReached = errors.New("reroll limit reached")
+	// ErrUnknownSkill 技能不存在
+	ErrUnknownSkill = errors.New("unknown skill")
+	// ErrSkillAtCap 技能已达到当前年龄的上限
+	ErrSkillAtCap = errors.New("skill at cap")
+	// ErrCharacterEnded 角色的人生已结束
+	ErrCharacterEnded = errors.New("character life ended")
+	// ErrSkillTrainingConflict 技能被并发修改，重试后仍未成功
+	ErrSkillTrainingConflict = errors.New("skill training conflict")
+	// ErrSkillTrainingCooldown 角色处于技能训练冷却期
+	ErrSkillTrainingCooldown = errors.New("skill training on cooldown")
+	// ErrCharacterArchived 角色已归档，需先恢复
+	ErrCharacterArchived = errors.New("character archived")
+	// ErrCharacterNotArchived 角色未归档，无需恢复
+	ErrCharacterNotArchived = errors.New("character not archived")
+	// ErrInvalidCharacterStatus 角色列表筛选状态不存在
+	ErrInvalidCharacterStatus = errors.New("invalid character status")
+	// ErrInvalidCharacterSort 角色列表排序字段或方向不存在
+	ErrInvalidCharacterSort = errors.New("invalid character sort")
+	// ErrInvalidBirthYearRange 角色列表出生年份下限大于上限
+	ErrInvalidBirthYearRange = errors.New("invalid birth year range")
+)
+
+// CharacterConfig 角色服务配置
+type CharacterConfig struct {
+	DraftTTL         time.Duration // 角色草稿保留时间
+	MaxRerolls       int           // 每个草稿可重新生成的次数
+	ArchiveRetention time.Duration // 删除的角色保留多久后彻底删除
+	PurgeInterval    time.Duration // 清理任务执行间隔
+	TrainingCooldown time.Duration // 同一角色两次技能训练的最短间隔
+}
+
+// SkillTrainingCooldownError 角色处于技能训练冷却期，携带剩余冷却时间
+type SkillTrainingCooldownError struct {
+	RetryAfter time.Duration
+}
+
+// Error 实现 error 接口
+func (e *SkillTrainingCooldownError) Error() string {
+	return fmt.Sprintf("%v, retry after %s", ErrSkillTrainingCooldown, e.RetryAfter.Round(time.Second))
+}
+
+// Unwrap 支持 errors.Is 判断
+func (e *SkillTrainingCooldownError) Unwrap() error {
+	return ErrSkillTrainingCooldown
+}
+
+// CharacterService 角色服务，所有操作均限定在当前用户自己的角色内
+type CharacterService struct {
+	characters *mysql.CharacterRepository
+	drafts     *redisrepo.CharacterDraftRepository
+	training   *redisrepo.SkillTrainingRepository
+	catalog    *catalog.Catalog
+	config     CharacterConfig
+}
+
+// NewCharacterService 创建角色服务
+func NewCharacterService(
+	characters *mysql.CharacterRepository,
+	drafts *redisrepo.CharacterDraftRepository,
+	training *redisrepo.SkillTrainingRepository,
+	catalog *catalog.Catalog,
+	config CharacterConfig,
+) *CharacterService {
+	// 设置默认值
+	if config.DraftTTL <= 0 {
+		config.DraftTTL = DefaultCharacterDraftTTL
+	}
+	if config.MaxRerolls <= 0 {
+		config.MaxRerolls = DefaultCharacterMaxRerolls
+	}
+	if config.ArchiveRetention <= 0 {
+		config.ArchiveRetention = DefaultCharacterArchiveRetention
+	}
+	if config.PurgeInterval <= 0 {
+		config.PurgeInterval = DefaultCharacterPurgeInterval
+	}
+	if config.TrainingCooldown <= 0 {
+		config.TrainingCooldown = DefaultSkillTrainingCooldown
+	}
+	return &CharacterService{
+		characters: characters,
+		drafts:     drafts,
+		training:   training,
+		catalog:    catalog,
+		config:     config,
+	}
+}
+
+// CreateDraft 生成角色草稿，草稿只保存在 Redis 中，过期未确认即自动丢弃
+// 角色只能经草稿确认创建，种子由服务端选取，玩家只能在重新生成次数内挑选
+func (s *CharacterService) CreateDraft(ctx context.Context, userID uint64, req *models.CreateCharacterRequest) (*models.CharacterDraftResponse, error) {
+	name, country, err := s.validateCreate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	draft := &models.CharacterDraft{
+		DraftID:          uuid.NewString(),
+		Name:             name,
+		BirthCountry:     country,
+		BirthYear:        req.BirthYear,
+		Seed:             rand.Int64(),
+		GeneratorVersion: generator.Version,
+		CreatedAt:        now,
+		ExpiresAt:        now.Add(s.config.DraftTTL),
+	}
+	if err := s.drafts.Save(ctx, userID, draft); err != nil {
+		return nil, err
+	}
+	return s.draftResponse(userID, draft), nil
+}
+
+// GetDraft 获取角色草稿
+func (s *CharacterService) GetDraft(ctx context.Context, userID uint64, draftID string) (*models.CharacterDraftResponse, error) {
+	draft, err := s.drafts.Get(ctx, userID, draftID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrCharacterDraftNotFound
+		}
+		return nil, err
+	}
+	return s.draftResponse(userID, draft), nil
+}
+
+// RerollDraft 使用新的随机种子重新生成草稿，消耗一次重新生成次数
+// 草稿的过期时间不因重新生成而延长
+func (s *CharacterService) RerollDraft(ctx context.Context, userID uint64, draftID string) (*models.CharacterDraftResponse, error) {
+	draft, rerolls, err := s.drafts.IncrRerolls(ctx, userID, draftID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrCharacterDraftNotFound
+		}
+		return nil, err
+	}
+	if rerolls > int64(s.config.MaxRerolls) {
+		return nil, ErrRerollLimitReached
+	}
+
+	draft.Seed = rand.Int64()
+	draft.GeneratorVersion = generator.Version
+	draft.Rerolls = int(rerolls)
+	if err := s.drafts.Save(ctx, userID, draft); err != nil {
+		return nil, err
+	}
+	return s.draftResponse(userID, draft), nil
+}
+
+// ConfirmDraft 确认草稿并保存为正式角色，草稿随即删除
+func (s *CharacterService) ConfirmDraft(ctx context.Context, userID uint64, draftID string) (*models.Character, error) {
+	draft, err := s.drafts.Consume(ctx, userID, draftID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrCharacterDraftNotFound
+		}
+		return nil, err
+	}
+	// 生成规则已升级的草稿无法按原样复现，视为过期
+	if draft.GeneratorVersion != generator.Version {
+		return nil, ErrCharacterDraftNotFound
+	}
+
+	return s.persist(ctx, userID, newCharacter(userID, draft.Name, draft.BirthCountry, draft.BirthYear, draft.Seed))
+}
+
+// DeleteDraft 放弃角色草稿
+func (s *CharacterService) DeleteDraft(ctx context.Context, userID uint64, draftID string) error {
+	found, err := s.drafts.Delete(ctx, userID, draftID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrCharacterDraftNotFound
+	}
+	return nil
+}
+
+// TrainSkill 训练角色技能
+// 提升幅度取决于天赋属性，技能越高提升越慢；技能上限随年龄增长，避免幼年角色通过反复训练直接练满
+// 每个角色两次训练之间有冷却期，避免客户端循环调用在短时间内练到上限
+func (s *CharacterService) TrainSkill(ctx context.Context, userID uint64, characterID, skill string) (*models.TrainSkillResponse, error) {
+	// 训练未生效时释放已占用的冷却期
+	cooldown, trained := false, false
+	defer func() {
+		if cooldown && !trained {
+			if err := s.training.ReleaseCooldown(ctx, userID, characterID); err != nil {
+				logrus.WithError(err).WithField("character_id", characterID).Warn("Failed to release skill training cooldown")
+			}
+		}
+	}()
+
+	for attempt := 0; attempt < skillTrainRetries; attempt++ {
+		character, err := s.characters.GetByID(ctx, userID, characterID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, ErrCharacterNotFound
+			}
+			return nil, err
+		}
+		if character.ArchivedAt != nil {
+			return nil, ErrCharacterArchived
+		}
+		if character.GameCompleted {
+			return nil, ErrCharacterEnded
+		}
+
+		current, ok := character.Skills.Get(skill)
+		if !ok {
+			return nil, ErrUnknownSkill
+		}
+		limit := skillCap(character.CurrentAge)
+		if current >= limit {
+			return nil, ErrSkillAtCap
+		}
+		value := min(limit, current+trainingGain(current, character.SkillAptitude(skill)))
+
+		// 校验通过后才占用冷却期，无效请求不消耗冷却
+		if !cooldown {
+			remaining, err := s.training.AcquireCooldown(ctx, userID, characterID, s.config.TrainingCooldown)
+			if err != nil {
+				return nil, err
+			}
+			if remaining > 0 {
+				return nil, &SkillTrainingCooldownError{RetryAfter: remaining}
+			}
+			cooldown = true
+		}
+
+		updated, err := s.characters.UpdateSkill(ctx, userID, characterID, skill, current, value)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, ErrCharacterNotFound
+			}
+			return nil, err
+		}
+		if !updated {
+			continue
+		}
+
+		trained = true
+		character.Skills.Set(skill, value)
+
+		// 技能已提升，性格变化失败不影响训练结果
+		traits, err := s.driftTraits(ctx, userID, characterID, skillTraitDrift[skill])
+		if err != nil {
+			logrus.WithError(err).WithField("character_id", characterID).Warn("Failed to drift traits after skill training")
+		}
+		return &models.TrainSkillResponse{
+			Skill:  skill,
+			Gain:   value - current,
+			Value:  value,
+			Cap:    limit,
+			Skills: character.Skills,
+			Traits: traits,
+		}, nil
+	}
+	return nil, ErrSkillTrainingConflict
+}
+
+// validateCreate 校验创建参数，返回规范化后的角色名和国家代码
+func (s *CharacterService) validateCreate(req *models.CreateCharacterRequest) (string, string, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return "", "", ErrInvalidCharacterName
+	}
+	country, err := s.catalog.ValidateBirth(req.BirthCountry, req.BirthYear)
+	if err != nil {
+		switch {
+		case errors.Is(err, catalog.ErrUnknownCountry):
+			return "", "", ErrInvalidCountry
+		case errors.Is(err, catalog.ErrYearOutOfRange):
+			return "", "", ErrInvalidBirthYear
+		case errors.Is(err, catalog.ErrCountryNotExisted):
+			return "", "", ErrCountryNotAvailable
+		}
+		return "", "", err
+	}
+	return name, country, nil
+}
+
+// persist 保存新角色
+func (s *CharacterService) persist(ctx context.Context, userID uint64, character *models.Character) (*models.Character, error) {
+	character.CharacterID = uuid.NewString()
+	if err := s.characters.Create(ctx, character); err != nil {
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id":      userID,
+		"character_id": character.CharacterID,
+		"seed":         *character.Seed,
+	}).Info("Character created")
+
+	// 重新读取以获得数据库生成的时间戳
+	return s.Get(ctx, userID, character.CharacterID)
+}
+
+// draftResponse 构造草稿响应，角色预览由草稿参数现场生成
+func (s *CharacterService) draftResponse(userID uint64, draft *models.CharacterDraft) *models.CharacterDraftResponse {
+	// 次数用完后的失败请求同样会累加计数，展示时截断到上限
+	if draft.Rerolls > s.config.MaxRerolls {
+		draft.Rerolls = s.config.MaxRerolls
+	}
+	return &models.CharacterDraftResponse{
+		CharacterDraft:   draft,
+		RerollsRemaining: s.config.MaxRerolls - draft.Rerolls,
+		Character:        newCharacter(userID, draft.Name, draft.BirthCountry, draft.BirthYear, draft.Seed),
+	}
+}
+
+// List 分页列出用户的角色，状态为空时列出全部未归档角色，默认按创建时间倒序
+func (s *CharacterService) List(ctx context.Context, userID uint64, query *models.CharacterListQuery) (*pagination.Page[*models.Character], error) {
+	switch query.Status {
+	case "", models.CharacterStatusActive, models.CharacterStatusCompleted, models.CharacterStatusArchived:
+	default:
+		return nil, ErrInvalidCharacterStatus
+	}
+
+	switch query.Sort {
+	case "":
+		query.Sort = models.CharacterSortCreatedAt
+	case models.CharacterSortCreatedAt, models.CharacterSortFinalAge, models.CharacterSortMoney:
+	default:
+		return nil, ErrInvalidCharacterSort
+	}
+	switch query.Order {
+	case "":
+		query.Order = "desc"
+	case "asc", "desc":
+	default:
+		return nil, ErrInvalidCharacterSort
+	}
+
+	if query.BirthYearMin != nil && query.BirthYearMax != nil && *query.BirthYearMin > *query.BirthYearMax {
+		return nil, ErrInvalidBirthYearRange
+	}
+	query.BirthCountry = strings.ToUpper(strings.TrimSpace(query.BirthCountry))
+
+	filter := characterListFingerprint(query)
+	var after *pagination.Cursor
+	if query.Cursor != "" {
+		cursor, err := pagination.Decode(query.Cursor, query.Sort, query.Order == "desc", filter)
+		if err != nil {
+			return nil, err
+		}
+		after = cursor
+	}
+
+	characters, next, err := s.characters.ListByUser(ctx, userID, query, after, pagination.Limit(query.Limit))
+	if err != nil {
+		return nil, err
+	}
+	for _, character := range characters {
+		s.setPurgeAt(character)
+	}
+
+	page := &pagination.Page[*models.Character]{Items: characters}
+	if next != nil {
+		next.Filter = filter
+		page.NextCursor = next.Encode()
+		page.HasMore = true
+	}
+	return page, nil
+}
+
+// characterListFingerprint 角色列表筛选条件指纹，游标只能在相同的筛选条件下继续使用
+func characterListFingerprint(query *models.CharacterListQuery) string {
+	optionalInt := func(v *int) string {
+		if v == nil {
+			return ""
+		}
+		return strconv.Itoa(*v)
+	}
+	completed := ""
+	if query.GameCompleted != nil {
+		completed = strconv.FormatBool(*query.GameCompleted)
+	}
+	return pagination.Fingerprint(query.Status, query.BirthCountry,
+		optionalInt(query.BirthYearMin), optionalInt(query.BirthYearMax), completed, query.LifeStage)
+}
+
+// Get 获取用户的角色，包含出生时的家庭
+func (s *CharacterService) Get(ctx context.Context, userID uint64, characterID string) (*models.Character, error) {
+	character, err := s.characters.GetByID(ctx, userID, characterID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrCharacterNotFound
+		}
+		return nil, err
+	}
+	s.setPurgeAt(character)
+
+	family, err := s.characters.GetFamily(ctx, characterID)
+	switch {
+	case err == nil:
+		family.IncomeClass = character.FamilyBackground
+		character.Family = family
+	case !errors.Is(err, repository.ErrNotFound):
+		return nil, err
+	}
+
+	traits, err := s.characters.GetTraits(ctx, characterID)
+	switch {
+	case err == nil:
+		character.Traits = traits
+	case !errors.Is(err, repository.ErrNotFound):
+		return nil, err
+	}
+	return character, nil
+}
+
+// driftTraits 按生活事件调整角色的性格倾向，返回调整后的性格倾向
+// 每个分量限制在 ±MaxTraitDrift 以内，性格只会缓慢变化；调用方需已确认角色未归档
+func (s *CharacterService) driftTraits(ctx context.Context, userID uint64, characterID string, delta models.TraitDelta) (*models.Traits, error) {
+	delta = models.TraitDelta{
+		Extraversion: limitDrift(delta.Extraversion),
+		Optimism:     limitDrift(delta.Optimism),
+		Bravery:      limitDrift(delta.Bravery),
+	}
+	if err := s.characters.DriftTraits(ctx, userID, characterID, delta); err != nil {
+		return nil, err
+	}
+	return s.characters.GetTraits(ctx, characterID)
+}
+
+// Update 更新角色展示类字段，只修改请求中提供的字段
+func (s *CharacterService) Update(ctx context.Context, userID uint64, characterID string, req *models.UpdateCharacterRequest) (*models.Character, error) {
+	character, err := s.Get(ctx, userID, characterID)
+	if err != nil {
+		return nil, err
+	}
+	if character.ArchivedAt != nil {
+		return nil, ErrCharacterArchived
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, ErrInvalidCharacterName
+		}
+		character.Name = name
+	}
+	if req.CurrentLocation != nil {
+		character.CurrentLocation = strings.TrimSpace(*req.CurrentLocation)
+	}
+	if req.CurrentActivity != nil {
+		character.CurrentActivity = strings.TrimSpace(*req.CurrentActivity)
+	}
+
+	if err := s.characters.UpdateDetails(ctx, character); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrCharacterNotFound
+		}
+		return nil, err
+	}
+	return s.Get(ctx, userID, characterID)
+}
+
+// Delete 删除用户的角色：角色先归档，保留期内可以恢复，期满后由清理任务彻底删除
+func (s *CharacterService) Delete(ctx context.Context, userID uint64, characterID string) (*models.Character, error) {
+	character, err := s.characters.GetByID(ctx, userID, characterID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrCharacterNotFound
+		}
+		return nil, err
+	}
+	if character.ArchivedAt != nil {
+		return nil, ErrCharacterArchived
+	}
+
+	if err := s.characters.Archive(ctx, userID, characterID, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrCharacterNotFound
+		}
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id":      userID,
+		"character_id": characterID,
+	}).Info("Character archived")
+	return s.Get(ctx, userID, characterID)
+}
+
+// Restore 恢复保留期内被删除的角色
+func (s *CharacterService) Restore(ctx context.Context, userID uint64, characterID string) (*models.Character, error) {
+	character, err := s.characters.GetByID(ctx, userID, characterID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrCharacterNotFound
+		}
+		return nil, err
+	}
+	if character.ArchivedAt == nil {
+		return nil, ErrCharacterNotArchived
+	}
+
+	// 已超过保留期、等待清理的角色视为不存在
+	if err := s.characters.Restore(ctx, userID, characterID, time.Now().Add(-s.config.ArchiveRetention)); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrCharacterNotFound
+		}
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id":      userID,
+		"character_id": characterID,
+	}).Info("Character restored")
+	return s.Get(ctx, userID, characterID)
+}
+
+// RunPurgeWorker 定期彻底删除超过保留期的归档角色，直到 ctx 结束
+func (s *CharacterService) RunPurgeWorker(ctx context.Context) {
+	ticker := time.NewTicker(s.config.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := s.PurgeArchived(ctx); err != nil {
+			logrus.WithError(err).Error("Failed to purge archived characters")
+		} else if n > 0 {
+			logrus.WithField("count", n).Info("Archived characters purged")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeArchived 分批彻底删除超过保留期的归档角色，返回删除数量
+func (s *CharacterService) PurgeArchived(ctx context.Context) (int64, error) {
+	before := time.Now().Add(-s.config.ArchiveRetention)
+
+	var total int64
+	for {
+		n, err := s.characters.PurgeArchived(ctx, before, characterPurgeBatchSize)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if n < characterPurgeBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// setPurgeAt 计算归档角色的彻底删除时间
+func (s *CharacterService) setPurgeAt(character *models.Character) {
+	if character.ArchivedAt == nil {
+		return
+	}
+	purgeAt := character.ArchivedAt.Add(s.config.ArchiveRetention)
+	character.PurgeAt = &purgeAt
+}
+
+// newCharacter 按生成参数构造初始状态的角色，不含角色ID
+func newCharacter(userID uint64, name, country string, birthYear int, seed int64) *models.Character {
+	character := &models.Character{
+		UserID:           userID,
+		Name:             name,
+		BirthCountry:     country,
+		BirthYear:        birthYear,
+		IsActive:         true,
+		Seed:             &seed,
+		GeneratorVersion: generator.Version,
+
+		LifeStage:      models.DefaultLifeStage,
+		CurrentStatus:  models.DefaultCurrentStatus,
+		HappinessLevel: models.DefaultHappinessLevel,
+		HealthLevel:    models.DefaultHealthLevel,
+	}
+	generator.Generate(generator.Input{
+		Country:   country,
+		BirthYear: birthYear,
+		Seed:      seed,
+	}).Apply(character)
+	character.Attributes = character.CoreAttributes()
+	return character
+}
+
+// limitDrift 将单次性格变化限制在 ±MaxTraitDrift 以内
+func limitDrift(value float64) float64 {
+	return math.Max(-models.MaxTraitDrift, math.Min(models.MaxTraitDrift, value))
+}
+
+// skillCap 当前年龄可达到的技能上限，18 岁起不再限制
+func skillCap(age int) int {
+	return min(models.MaxAttributeValue, 10+5*age)
+}
+
+// trainingGain 单次训练的提升，天赋越高提升越多，技能越接近 100 提升越少，至少提升 1
+func trainingGain(current, aptitude int) int {
+	gain := skillTrainBaseGain * (0.5 + float64(aptitude)/100) * (1 - float64(current)/100)
+	return max(1, int(math.Round(gain)))
+}
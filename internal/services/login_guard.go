@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	redisrepo "github.com/xuchengvcc/restart-life-api/internal/repository/redis"
+)
+
+// 登录防护默认值
+const (
+	DefaultMaxAccountFailures = 5
+	DefaultMaxIPFailures      = 20
+	DefaultFailureWindow      = time.Hour
+	DefaultBaseLockout        = time.Minute
+	DefaultMaxLockout         = time.Hour
+)
+
+// 登录防护错误
+var (
+	// ErrAccountLocked 账户因连续登录失败被临时锁定
+	ErrAccountLocked = errors.New("account temporarily locked")
+	// ErrTooManyLoginAttempts 来源IP登录失败次数过多被临时限制
+	ErrTooManyLoginAttempts = errors.New("too many login attempts")
+)
+
+// LoginLockedError 登录被临时锁定，携带剩余锁定时间
+type LoginLockedError struct {
+	Err        error // ErrAccountLocked 或 ErrTooManyLoginAttempts
+	RetryAfter time.Duration
+}
+
+// Error 实现 error 接口
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("%v, retry after %s", e.Err, e.RetryAfter.Round(time.Second))
+}
+
+// Unwrap 支持 errors.Is 判断锁定类型
+func (e *LoginLockedError) Unwrap() error {
+	return e.Err
+}
+
+// LoginGuardConfig 登录防护配置
+type LoginGuardConfig struct {
+	MaxAccountFailures int           // 单个账户允许的连续失败次数，达到后开始锁定
+	MaxIPFailures      int           // 单个IP允许的连续失败次数，达到后开始锁定
+	FailureWindow      time.Duration // 失败计数的保留时间，期间无新的失败则清零
+	BaseLockout        time.Duration // 首次锁定时长，之后每次失败翻倍
+	MaxLockout         time.Duration // 锁定时长上限
+}
+
+// LoginGuard 登录防暴力破解，按账户和来源IP分别计数，超过阈值后按指数退避临时锁定
+type LoginGuard struct {
+	attempts *redisrepo.LoginAttemptRepository
+	config   LoginGuardConfig
+}
+
+// NewLoginGuard 创建登录防护
+func NewLoginGuard(attempts *redisrepo.LoginAttemptRepository, config LoginGuardConfig) *LoginGuard {
+	// 设置默认值
+	if config.MaxAccountFailures <= 0 {
+		config.MaxAccountFailures = DefaultMaxAccountFailures
+	}
+	if config.MaxIPFailures <= 0 {
+		config.MaxIPFailures = DefaultMaxIPFailures
+	}
+	if config.FailureWindow <= 0 {
+		config.FailureWindow = DefaultFailureWindow
+	}
+	if config.BaseLockout <= 0 {
+		config.BaseLockout = DefaultBaseLockout
+	}
+	if config.MaxLockout <= 0 {
+		config.MaxLockout = DefaultMaxLockout
+	}
+	return &LoginGuard{
+		attempts: attempts,
+		config:   config,
+	}
+}
+
+// Check 检查来源IP和账户是否处于锁定期
+func (g *LoginGuard) Check(ctx context.Context, account, ip string) error {
+	if ip != "" {
+		remaining, err := g.attempts.LockRemaining(ctx, ipSubject(ip))
+		if err != nil {
+			return err
+		}
+		if remaining > 0 {
+			return &LoginLockedError{Err: ErrTooManyLoginAttempts, RetryAfter: remaining}
+		}
+	}
+
+	remaining, err := g.attempts.LockRemaining(ctx, account)
+	if err != nil {
+		return err
+	}
+	if remaining > 0 {
+		return &LoginLockedError{Err: ErrAccountLocked, RetryAfter: remaining}
+	}
+	return nil
+}
+
+// RecordFailure 记录一次失败登录，达到阈值时锁定对应账户或IP
+func (g *LoginGuard) RecordFailure(ctx context.Context, account, ip string) error {
+	failures, err := g.attempts.IncrFailures(ctx, account, g.config.FailureWindow)
+	if err != nil {
+		return err
+	}
+	if lockout := g.lockout(failures, g.config.MaxAccountFailures); lockout > 0 {
+		if err := g.attempts.Lock(ctx, account, lockout); err != nil {
+			return err
+		}
+		logrus.WithFields(logrus.Fields{
+			"account":  account,
+			"failures": failures,
+			"lockout":  lockout.String(),
+		}).Warn("Account locked after repeated login failures")
+	}
+
+	if ip == "" {
+		return nil
+	}
+	failures, err = g.attempts.IncrFailures(ctx, ipSubject(ip), g.config.FailureWindow)
+	if err != nil {
+		return err
+	}
+	if lockout := g.lockout(failures, g.config.MaxIPFailures); lockout > 0 {
+		if err := g.attempts.Lock(ctx, ipSubject(ip), lockout); err != nil {
+			return err
+		}
+		logrus.WithFields(logrus.Fields{
+			"ip":       ip,
+			"failures": failures,
+			"lockout":  lockout.String(),
+		}).Warn("IP throttled after repeated login failures")
+	}
+	return nil
+}
+
+// Reset 登录成功后清除账户的失败计数
+// IP 计数不清除，避免攻击者用自己的账户登录来重置限流
+func (g *LoginGuard) Reset(ctx context.Context, account string) error {
+	return g.attempts.Reset(ctx, account)
+}
+
+// lockout 计算锁定时长：达到阈值时锁定 BaseLockout，之后每多失败一次翻倍，不超过 MaxLockout
+func (g *LoginGuard) lockout(failures int64, threshold int) time.Duration {
+	if failures < int64(threshold) {
+		return 0
+	}
+	lockout := g.config.BaseLockout
+	for i := int64(threshold); i < failures; i++ {
+		lockout *= 2
+		if lockout >= g.config.MaxLockout {
+			return g.config.MaxLockout
+		}
+	}
+	return lockout
+}
+
+// userSubject 已存在用户的限流对象，用户名和邮箱登录共用同一计数
+func userSubject(userID uint64) string {
+	return redisrepo.UserLoginSubject(userID)
+}
+
+// unknownAccountSubject 不存在账户的限流对象，与存在的账户行为一致，避免通过锁定响应枚举用户
+func unknownAccountSubject(account string) string {
+	return "account:name:" + strings.ToLower(strings.TrimSpace(account))
+}
+
+// ipSubject 来源IP的限流对象
+func ipSubject(ip string) string {
+	return "ip:" + ip
+}
@@ -0,0 +1,440 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository"
+	"github.com/xuchengvcc/restart-life-api/internal/repository/mysql"
+	redisrepo "github.com/xuchengvcc/restart-life-api/internal/repository/redis"
+	"github.com/xuchengvcc/restart-life-api/internal/storage"
+	"github.com/xuchengvcc/restart-life-api/internal/utils"
+)
+
+// 个人数据相关默认值
+const (
+	DefaultDeletionGracePeriod = 30 * 24 * time.Hour
+	DefaultExportExpiry        = 7 * 24 * time.Hour
+	DefaultExportCooldown      = time.Hour
+	DefaultPurgeInterval       = time.Hour
+)
+
+// 清理任务参数
+const (
+	// purgeBatchSize 每轮清理处理的最大记录数
+	purgeBatchSize = 100
+	// exportBuildTimeout 单个导出任务的最长执行时间
+	exportBuildTimeout = 5 * time.Minute
+)
+
+// 个人数据业务错误
+var (
+	// ErrExportNotFound 导出任务不存在、不属于当前用户或已过期
+	ErrExportNotFound = errors.New("data export not found")
+	// ErrExportNotReady 导出任务尚未完成
+	ErrExportNotReady = errors.New("data export not ready")
+	// ErrExportRateLimited 导出过于频繁
+	ErrExportRateLimited = errors.New("data export requested too frequently")
+	// ErrDeletionAlreadyScheduled 已申请注销
+	ErrDeletionAlreadyScheduled = errors.New("account deletion already scheduled")
+	// ErrDeletionNotScheduled 未申请注销
+	ErrDeletionNotScheduled = errors.New("account deletion not scheduled")
+)
+
+// PrivacyConfig 个人数据服务配置
+type PrivacyConfig struct {
+	DeletionGracePeriod time.Duration // 申请注销到实际删除的冷静期
+	ExportExpiry        time.Duration // 导出文件保留时间
+	ExportCooldown      time.Duration // 两次导出的最小间隔
+	PurgeInterval       time.Duration // 清理任务执行间隔
+}
+
+// PrivacyService 个人数据服务，负责数据导出与账户注销
+type PrivacyService struct {
+	users     *mysql.UserRepository
+	exports   *mysql.DataExportRepository
+	purger    *redisrepo.UserPurgeRepository
+	blobs     storage.BlobStore
+	tokens    *TokenService
+	twoFactor *TwoFactorService
+	config    PrivacyConfig
+}
+
+// NewPrivacyService 创建个人数据服务
+func NewPrivacyService(
+	users *mysql.UserRepository,
+	exports *mysql.DataExportRepository,
+	purger *redisrepo.UserPurgeRepository,
+	blobs storage.BlobStore,
+	tokens *TokenService,
+	twoFactor *TwoFactorService,
+	config PrivacyConfig,
+) *PrivacyService {
+	// 设置默认值
+	if config.DeletionGracePeriod <= 0 {
+		config.DeletionGracePeriod = DefaultDeletionGracePeriod
+	}
+	if config.ExportExpiry <= 0 {
+		config.ExportExpiry = DefaultExportExpiry
+	}
+	if config.ExportCooldown <= 0 {
+		config.ExportCooldown = DefaultExportCooldown
+	}
+	if config.PurgeInterval <= 0 {
+		config.PurgeInterval = DefaultPurgeInterval
+	}
+	return &PrivacyService{
+		users:     users,
+		exports:   exports,
+		purger:    purger,
+		blobs:     blobs,
+		tokens:    tokens,
+		twoFactor: twoFactor,
+		config:    config,
+	}
+}
+
+// RequestExport 创建个人数据导出任务，导出在后台执行
+func (s *PrivacyService) RequestExport(ctx context.Context, userID uint64) (*models.DataExport, error) {
+	latest, err := s.exports.GetLatestByUser(ctx, userID)
+	switch {
+	case err == nil:
+		if latest.Status != models.ExportStatusFailed && time.Since(latest.CreatedAt) < s.config.ExportCooldown {
+			return nil, ErrExportRateLimited
+		}
+	case !errors.Is(err, repository.ErrNotFound):
+		return nil, err
+	}
+
+	export := &models.DataExport{
+		ExportID:  utils.RandomHex(16),
+		UserID:    userID,
+		Status:    models.ExportStatusPending,
+		CreatedAt: time.Now(),
+	}
+	if err := s.exports.Create(ctx, export); err != nil {
+		return nil, err
+	}
+
+	go s.buildExport(export)
+
+	logrus.WithFields(logrus.Fields{
+		"user_id":   userID,
+		"export_id": export.ExportID,
+	}).Info("Data export requested")
+	return export, nil
+}
+
+// GetExport 获取导出任务状态
+func (s *PrivacyService) GetExport(ctx context.Context, userID uint64, exportID string) (*models.DataExport, error) {
+	export, err := s.exports.Get(ctx, exportID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrExportNotFound
+		}
+		return nil, err
+	}
+	if export.UserID != userID {
+		return nil, ErrExportNotFound
+	}
+	if export.ExpiresAt != nil && time.Now().After(*export.ExpiresAt) {
+		return nil, ErrExportNotFound
+	}
+	return export, nil
+}
+
+// DownloadExport 下载已完成的导出文件
+func (s *PrivacyService) DownloadExport(ctx context.Context, userID uint64, exportID string) ([]byte, error) {
+	export, err := s.GetExport(ctx, userID, exportID)
+	if err != nil {
+		return nil, err
+	}
+	if export.Status != models.ExportStatusReady {
+		return nil, ErrExportNotReady
+	}
+
+	data, err := s.blobs.Get(ctx, export.BlobKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrBlobNotFound) {
+			return nil, ErrExportNotFound
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// ScheduleDeletion 申请注销账户，冷静期结束后账户及全部数据被删除
+// 申请后所有会话立即失效，冷静期内重新登录并撤销即可保留账户
+func (s *PrivacyService) ScheduleDeletion(ctx context.Context, userID uint64, req *models.DeleteAccountRequest) (*models.AccountDeletionResponse, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if user.DeletionAt != nil {
+		return nil, ErrDeletionAlreadyScheduled
+	}
+
+	// 游客和第三方登录创建的账户没有密码
+	if user.PasswordHash != "" && !utils.CheckPassword(user.PasswordHash, req.Password) {
+		return nil, ErrInvalidCredentials
+	}
+	enabled, err := s.twoFactor.IsEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		if err := s.twoFactor.Verify(ctx, userID, req.Code); err != nil {
+			return nil, err
+		}
+	}
+
+	scheduledAt := time.Now().Add(s.config.DeletionGracePeriod)
+	if err := s.users.ScheduleDeletion(ctx, userID, scheduledAt); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrDeletionAlreadyScheduled
+		}
+		return nil, err
+	}
+	if err := s.tokens.RevokeAllForUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"user_id":      userID,
+		"scheduled_at": scheduledAt,
+	}).Info("Account deletion scheduled")
+
+	return &models.AccountDeletionResponse{DeletionScheduledAt: scheduledAt}, nil
+}
+
+// CancelDeletion 撤销注销申请
+func (s *PrivacyService) CancelDeletion(ctx context.Context, userID uint64) error {
+	if err := s.users.CancelDeletion(ctx, userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrDeletionNotScheduled
+		}
+		return err
+	}
+
+	logrus.WithField("user_id", userID).Info("Account deletion cancelled")
+	return nil
+}
+
+// RunPurgeWorker 定期删除冷静期已结束的账户和已过期的导出文件，直到 ctx 取消
+// 多个实例同时运行是安全的：删除操作带条件且幂等
+func (s *PrivacyService) RunPurgeWorker(ctx context.Context) {
+	ticker := time.NewTicker(s.config.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := s.PurgeDueAccounts(ctx); err != nil {
+			logrus.WithError(err).Error("Failed to purge deleted accounts")
+		} else if n > 0 {
+			logrus.WithField("count", n).Info("Deleted accounts purged")
+		}
+		if err := s.PurgeExpiredExports(ctx); err != nil {
+			logrus.WithError(err).Error("Failed to purge expired data exports")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeDueAccounts 删除冷静期已结束的账户，返回删除数量
+func (s *PrivacyService) PurgeDueAccounts(ctx context.Context) (int, error) {
+	now := time.Now()
+	users, err := s.users.ListDueForDeletion(ctx, now, purgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, user := range users {
+		if err := s.purgeUser(ctx, user, now); err != nil {
+			logrus.WithError(err).WithField("user_id", user.UserID).Error("Failed to purge account")
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// PurgeExpiredExports 删除已过期的导出文件及其任务记录
+func (s *PrivacyService) PurgeExpiredExports(ctx context.Context) error {
+	exports, err := s.exports.ListExpired(ctx, time.Now(), purgeBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, export := range exports {
+		if export.BlobKey != "" {
+			if err := s.blobs.Delete(ctx, export.BlobKey); err != nil {
+				logrus.WithError(err).WithField("export_id", export.ExportID).Warn("Failed to delete expired export file")
+				continue
+			}
+		}
+		if err := s.exports.Delete(ctx, export.ExportID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// purgeUser 删除用户：先清理对象存储和 Redis 中的数据，再删除数据库记录（关联数据通过外键级联删除）
+// 数据库记录是下次清理任务重试的依据，因此放在最后删除；前面任一步骤失败即返回，等待下次重试
+// 对象存储和 Redis 的清理均可重复执行
+func (s *PrivacyService) purgeUser(ctx context.Context, user *models.User, now time.Time) error {
+	var blobKeys []string
+	exports, err := s.exports.ListByUser(ctx, user.UserID)
+	if err != nil {
+		return err
+	}
+	for _, export := range exports {
+		if export.BlobKey != "" {
+			blobKeys = append(blobKeys, export.BlobKey)
+		}
+	}
+	if key, ok := storage.KeyFromURL(s.blobs, user.AvatarURL); ok {
+		blobKeys = append(blobKeys, key)
+	}
+
+	for _, key := range blobKeys {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			return fmt.Errorf("failed to delete user file %s: %w", key, err)
+		}
+	}
+	if err := s.purger.PurgeUser(ctx, user.UserID); err != nil {
+		return err
+	}
+
+	if err := s.users.DeleteScheduled(ctx, user.UserID, now); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			// 期间已被其他实例删除，或恰好在清理期间撤销了注销（此时会话和头像已被清理，需重新登录和上传）
+			return nil
+		}
+		return err
+	}
+
+	logrus.WithField("user_id", user.UserID).Info("Account purged")
+	return nil
+}
+
+// buildExport 生成导出文件并更新任务状态
+func (s *PrivacyService) buildExport(export *models.DataExport) {
+	ctx, cancel := context.WithTimeout(context.Background(), exportBuildTimeout)
+	defer cancel()
+
+	logger := logrus.WithFields(logrus.Fields{
+		"user_id":   export.UserID,
+		"export_id": export.ExportID,
+	})
+
+	data, err := s.buildArchive(ctx, export)
+	if err == nil {
+		key := fmt.Sprintf("exports/%d/%s.zip", export.UserID, export.ExportID)
+		if err = s.blobs.Put(ctx, key, data, "application/zip"); err == nil {
+			now := time.Now()
+			err = s.exports.MarkReady(ctx, export.ExportID, key, int64(len(data)), now, now.Add(s.config.ExportExpiry))
+		}
+	}
+	if err != nil {
+		logger.WithError(err).Error("Data export failed")
+		if err := s.exports.MarkFailed(ctx, export.ExportID, "export failed, please try again later", time.Now()); err != nil {
+			logger.WithError(err).Error("Failed to mark data export failed")
+		}
+		return
+	}
+
+	logger.Info("Data export ready")
+}
+
+// exportManifest 导出文件说明
+type exportManifest struct {
+	ExportID   string    `json:"export_id"`
+	UserID     uint64    `json:"user_id"`
+	ExportedAt time.Time `json:"exported_at"`
+	Files      []string  `json:"files"`
+}
+
+// buildArchive 生成 ZIP 导出文件：每张表一个 JSON 文件，另附头像和说明文件
+func (s *PrivacyService) buildArchive(ctx context.Context, export *models.DataExport) ([]byte, error) {
+	tables, err := s.exports.DumpUserData(ctx, export.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	manifest := exportManifest{
+		ExportID:   export.ExportID,
+		UserID:     export.UserID,
+		ExportedAt: time.Now(),
+	}
+
+	for _, table := range tables {
+		name := table.Name + ".json"
+		if err := writeZipJSON(archive, name, table.Rows); err != nil {
+			return nil, err
+		}
+		manifest.Files = append(manifest.Files, name)
+
+		// 附带本存储中的头像文件
+		if table.Name == "user" && len(table.Rows) == 1 {
+			avatarURL, _ := table.Rows[0]["avatar_url"].(string)
+			if key, ok := storage.KeyFromURL(s.blobs, avatarURL); ok {
+				avatar, err := s.blobs.Get(ctx, key)
+				if err != nil && !errors.Is(err, storage.ErrBlobNotFound) {
+					return nil, err
+				}
+				if err == nil {
+					if err := writeZipFile(archive, "avatar.jpg", avatar); err != nil {
+						return nil, err
+					}
+					manifest.Files = append(manifest.Files, "avatar.jpg")
+				}
+			}
+		}
+	}
+
+	if err := writeZipJSON(archive, "manifest.json", manifest); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish export archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeZipJSON 向 ZIP 写入格式化的 JSON 文件
+func writeZipJSON(archive *zip.Writer, name string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	return writeZipFile(archive, name, data)
+}
+
+// writeZipFile 向 ZIP 写入文件
+func writeZipFile(archive *zip.Writer, name string, data []byte) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	// 内嵌时区数据，运行环境缺少 zoneinfo 时也能校验时区
+	_ "time/tzdata"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository"
+	"github.com/xuchengvcc/restart-life-api/internal/repository/mysql"
+	"github.com/xuchengvcc/restart-life-api/internal/storage"
+	"github.com/xuchengvcc/restart-life-api/internal/utils"
+)
+
+// 头像处理参数
+const (
+	// AvatarSize 头像边长（像素）
+	AvatarSize = 256
+	// MaxAvatarUploadSize 头像上传文件大小上限
+	MaxAvatarUploadSize = 5 << 20
+)
+
+// defaultTimezone 未设置时区时的默认值，与 users 表默认值一致
+const defaultTimezone = "UTC"
+
+// 用户资料业务错误
+var (
+	// ErrInvalidBirthDate 生日格式错误或不在合理范围内
+	ErrInvalidBirthDate = errors.New("invalid birth date")
+	// ErrInvalidGender 性别取值不合法
+	ErrInvalidGender = errors.New("invalid gender")
+	// ErrInvalidTimezone 时区不是合法的 IANA 时区
+	ErrInvalidTimezone = errors.New("invalid timezone")
+	// ErrUnsupportedImage 上传的文件不是支持的图片格式
+	ErrUnsupportedImage = errors.New("unsupported image")
+)
+
+// allowedGenders 允许的性别取值，空字符串表示未设置
+var allowedGenders = map[string]bool{
+	"":       true,
+	"male":   true,
+	"female": true,
+	"other":  true,
+}
+
+// minBirthDate 允许的最早生日
+var minBirthDate = time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ProfileService 用户资料服务
+type ProfileService struct {
+	users *mysql.UserRepository
+	blobs storage.BlobStore
+}
+
+// NewProfileService 创建用户资料服务
+func NewProfileService(users *mysql.UserRepository, blobs storage.BlobStore) *ProfileService {
+	return &ProfileService{
+		users: users,
+		blobs: blobs,
+	}
+}
+
+// GetProfile 获取用户资料
+func (s *ProfileService) GetProfile(ctx context.Context, userID uint64) (*models.UserProfile, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	profile := user.ToProfile()
+	return &profile, nil
+}
+
+// UpdateProfile 更新用户资料，只修改请求中提供的字段
+func (s *ProfileService) UpdateProfile(ctx context.Context, userID uint64, req *models.UpdateProfileRequest) (*models.UserProfile, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Bio != nil {
+		user.Bio = strings.TrimSpace(*req.Bio)
+	}
+	if req.BirthDate != nil {
+		birthDate, err := parseBirthDate(strings.TrimSpace(*req.BirthDate))
+		if err != nil {
+			return nil, err
+		}
+		user.BirthDate = birthDate
+	}
+	if req.Gender != nil {
+		gender := strings.ToLower(strings.TrimSpace(*req.Gender))
+		if !allowedGenders[gender] {
+			return nil, ErrInvalidGender
+		}
+		user.Gender = gender
+	}
+	if req.Country != nil {
+		user.Country = strings.TrimSpace(*req.Country)
+	}
+	if req.Timezone != nil {
+		timezone := strings.TrimSpace(*req.Timezone)
+		if timezone == "" {
+			timezone = defaultTimezone
+		}
+		if _, err := time.LoadLocation(timezone); err != nil || timezone == "Local" {
+			return nil, ErrInvalidTimezone
+		}
+		user.Timezone = timezone
+	}
+	if user.Timezone == "" {
+		user.Timezone = defaultTimezone
+	}
+
+	if err := s.users.UpdateProfile(ctx, user); err != nil {
+		return nil, err
+	}
+
+	profile := user.ToProfile()
+	return &profile, nil
+}
+
+// UpdateAvatar 处理并保存上传的头像，替换成功后删除旧头像
+func (s *ProfileService) UpdateAvatar(ctx context.Context, userID uint64, file io.Reader) (*models.UserProfile, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := utils.ProcessAvatar(file, AvatarSize)
+	if err != nil {
+		if errors.Is(err, utils.ErrUnsupportedImage) || errors.Is(err, utils.ErrImageTooLarge) {
+			return nil, ErrUnsupportedImage
+		}
+		return nil, err
+	}
+
+	// 每次上传使用新的对象键，避免客户端和 CDN 缓存旧头像
+	key := fmt.Sprintf("avatars/%d/%s.jpg", userID, utils.RandomHex(8))
+	if err := s.blobs.Put(ctx, key, data, "image/jpeg"); err != nil {
+		return nil, err
+	}
+
+	previous := user.AvatarURL
+	user.AvatarURL = s.blobs.URL(key)
+	if err := s.users.UpdateAvatar(ctx, userID, user.AvatarURL); err != nil {
+		s.deleteBlob(ctx, key)
+		return nil, err
+	}
+	s.deleteAvatar(ctx, previous)
+
+	logrus.WithField("user_id", userID).Info("Avatar updated")
+
+	profile := user.ToProfile()
+	return &profile, nil
+}
+
+// DeleteAvatar 删除用户头像
+func (s *ProfileService) DeleteAvatar(ctx context.Context, userID uint64) (*models.UserProfile, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.AvatarURL != "" {
+		if err := s.users.UpdateAvatar(ctx, userID, ""); err != nil {
+			return nil, err
+		}
+		s.deleteAvatar(ctx, user.AvatarURL)
+		user.AvatarURL = ""
+	}
+
+	profile := user.ToProfile()
+	return &profile, nil
+}
+
+// getUser 获取用户
+func (s *ProfileService) getUser(ctx context.Context, userID uint64) (*models.User, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// deleteAvatar 删除存储在本存储中的旧头像，外部地址（如第三方登录带入的头像）忽略
+func (s *ProfileService) deleteAvatar(ctx context.Context, avatarURL string) {
+	if key, ok := storage.KeyFromURL(s.blobs, avatarURL); ok {
+		s.deleteBlob(ctx, key)
+	}
+}
+
+// deleteBlob 删除对象，失败只记录日志
+func (s *ProfileService) deleteBlob(ctx context.Context, key string) {
+	if err := s.blobs.Delete(ctx, key); err != nil {
+		logrus.WithError(err).WithField("key", key).Warn("Failed to delete blob")
+	}
+}
+
+// parseBirthDate 解析生日，空字符串表示清除
+func parseBirthDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	birthDate, err := time.Parse(models.BirthDateLayout, value)
+	if err != nil {
+		return nil, ErrInvalidBirthDate
+	}
+	if birthDate.Before(minBirthDate) || birthDate.After(time.Now()) {
+		return nil, ErrInvalidBirthDate
+	}
+	return &birthDate, nil
+}
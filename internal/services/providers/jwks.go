@@ -0,0 +1,187 @@
+package providers
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// jwksCacheTTL 公钥集缓存时间
+	jwksCacheTTL = time.Hour
+	// jwksMinRefreshInterval 遇到未知 kid 时强制刷新的最小间隔，防止被恶意令牌刷爆
+	jwksMinRefreshInterval = time.Minute
+)
+
+// jsonWebKey JWKS 中的单个公钥
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// JWKSCache 远程 JWKS 公钥缓存
+type JWKSCache struct {
+	url    string
+	client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// NewJWKSCache 创建 JWKS 公钥缓存
+func NewJWKSCache(url string, client *http.Client) *JWKSCache {
+	return &JWKSCache{
+		url:    url,
+		client: client,
+		keys:   make(map[string]crypto.PublicKey),
+	}
+}
+
+// Keyfunc 返回供 jwt 解析使用的公钥查找函数
+func (c *JWKSCache) Keyfunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return c.key(ctx, kid)
+	}
+}
+
+// key 根据 kid 查找公钥，缓存过期或 kid 未知时重新拉取
+func (c *JWKSCache) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key, ok := c.keys[kid]
+	expired := time.Since(c.fetchedAt) > jwksCacheTTL
+	if ok && !expired {
+		return key, nil
+	}
+
+	if expired || time.Since(c.fetchedAt) > jwksMinRefreshInterval {
+		if err := c.refresh(ctx); err != nil {
+			if ok {
+				// 拉取失败时继续使用旧公钥
+				logrus.WithError(err).WithField("jwks_url", c.url).Warn("Failed to refresh JWKS, using cached keys")
+				return key, nil
+			}
+			return nil, err
+		}
+		if key, ok = c.keys[kid]; ok {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown signing key id %q", kid)
+}
+
+// refresh 拉取并解析 JWKS
+func (c *JWKSCache) refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build JWKS request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: JWKS endpoint returned status %d", ErrProviderUnavailable, resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("%w: invalid JWKS response: %v", ErrProviderUnavailable, err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			logrus.WithError(err).WithField("kid", jwk.Kid).Warn("Skipping unsupported JWKS key")
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	c.keys = keys
+	c.fetchedAt = time.Now()
+	return nil
+}
+
+// publicKey 将 JWK 转换为公钥
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBase64URLInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA modulus: %w", err)
+		}
+		e, err := decodeBase64URLInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA exponent: %w", err)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported EC curve %q", k.Crv)
+		}
+		x, err := decodeBase64URLInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC x coordinate: %w", err)
+		}
+		y, err := decodeBase64URLInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC y coordinate: %w", err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("EC point is not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// decodeBase64URLInt 解码 base64url 编码的大整数
+func decodeBase64URLInt(value string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(bytes), nil
+}
@@ -0,0 +1,195 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultOIDCTimeout 默认请求超时
+const defaultOIDCTimeout = 10 * time.Second
+
+// OIDCConfig OpenID Connect 提供方配置
+type OIDCConfig struct {
+	ClientID     string
+	ClientSecret string
+	AuthURL      string // 授权端点
+	TokenURL     string // 令牌端点
+	JWKSURL      string // ID 令牌签名公钥集
+	Issuer       string // ID 令牌 iss
+	RedirectURL  string // 授权完成后的回调地址，需与提供方后台登记一致
+	Scopes       []string
+	Timeout      time.Duration
+}
+
+// OIDCProvider OpenID Connect 授权码 + PKCE 登录提供方（Google、Apple 等）
+type OIDCProvider struct {
+	name   string
+	config OIDCConfig
+	client *http.Client
+	jwks   *JWKSCache
+}
+
+// tokenResponse 令牌端点响应
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// idTokenClaims ID 令牌载荷
+type idTokenClaims struct {
+	Nonce         string      `json:"nonce"`
+	Email         string      `json:"email"`
+	EmailVerified interface{} `json:"email_verified"` // Apple 以字符串形式返回
+	Name          string      `json:"name"`
+	jwt.RegisteredClaims
+}
+
+// NewOIDCProvider 创建 OpenID Connect 登录提供方
+func NewOIDCProvider(name string, config OIDCConfig) *OIDCProvider {
+	// 设置默认值
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+	if config.Timeout == 0 {
+		config.Timeout = defaultOIDCTimeout
+	}
+
+	client := &http.Client{Timeout: config.Timeout}
+	return &OIDCProvider{
+		name:   name,
+		config: config,
+		client: client,
+		jwks:   NewJWKSCache(config.JWKSURL, client),
+	}
+}
+
+// Name 提供方名称
+func (p *OIDCProvider) Name() string {
+	return p.name
+}
+
+// AuthorizationURL 构造授权端点跳转地址
+func (p *OIDCProvider) AuthorizationURL(state, nonce, codeChallenge string) string {
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(p.config.AuthURL, "?") {
+		separator = "&"
+	}
+	return p.config.AuthURL + separator + query.Encode()
+}
+
+// Exchange 用授权码换取 ID 令牌并校验，返回用户身份
+func (p *OIDCProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error) {
+	idToken, err := p.requestIDToken(ctx, code, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &idTokenClaims{}
+	_, err = jwt.ParseWithClaims(idToken, claims, p.jwks.Keyfunc(ctx),
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384"}),
+		jwt.WithIssuer(p.config.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30*time.Second),
+	)
+	if err != nil {
+		logrus.WithError(err).WithField("provider", p.name).Warn("ID token verification failed")
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredential, err)
+	}
+	if claims.Nonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidCredential)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidCredential)
+	}
+
+	identity := &Identity{
+		Provider: p.name,
+		Subject:  claims.Subject,
+		Nickname: claims.Name,
+	}
+	// 仅采信提供方已验证的邮箱
+	if isTrue(claims.EmailVerified) {
+		identity.Email = strings.ToLower(claims.Email)
+	}
+	return identity, nil
+}
+
+// requestIDToken 调用令牌端点
+func (p *OIDCProvider) requestIDToken(ctx context.Context, code, codeVerifier string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("client_secret", p.config.ClientSecret)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+
+	var result tokenResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("%w: invalid token response (status %d)", ErrProviderUnavailable, resp.StatusCode)
+	}
+
+	switch {
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return "", fmt.Errorf("%w: token endpoint returned status %d", ErrProviderUnavailable, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK || result.Error != "":
+		// invalid_grant 等：授权码无效、已使用或 code_verifier 不匹配
+		return "", fmt.Errorf("%w: %s %s", ErrInvalidCredential, result.Error, result.ErrorDescription)
+	case result.IDToken == "":
+		return "", fmt.Errorf("%w: token response has no id_token", ErrInvalidCredential)
+	}
+
+	return result.IDToken, nil
+}
+
+// isTrue 兼容布尔值与字符串形式的布尔值
+func isTrue(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	default:
+		return false
+	}
+}
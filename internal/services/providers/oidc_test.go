@@ -0,0 +1,348 @@
+package providers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID     = "client-1"
+	testClientSecret = "secret-1"
+	testRedirectURL  = "https://app.example.com/callback"
+	testAuthCode     = "auth-code"
+)
+
+// mockIdP 本地模拟的 OpenID Connect 提供方，提供令牌端点和 JWKS 端点
+type mockIdP struct {
+	server *httptest.Server
+
+	mu        sync.Mutex
+	keys      map[string]*ecdsa.PrivateKey // JWKS 中公布的签名密钥
+	challenge string                       // 授权请求携带的 code_challenge
+	idToken   string                       // 令牌端点返回的 ID 令牌
+	jwksHits  int
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	t.Helper()
+
+	idp := &mockIdP{keys: make(map[string]*ecdsa.PrivateKey)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", idp.handleToken)
+	mux.HandleFunc("/jwks", idp.handleJWKS)
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+// provider 创建指向本 IdP 的登录提供方
+func (idp *mockIdP) provider() *OIDCProvider {
+	return NewOIDCProvider("mock", OIDCConfig{
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		AuthURL:      idp.server.URL + "/authorize",
+		TokenURL:     idp.server.URL + "/token",
+		JWKSURL:      idp.server.URL + "/jwks",
+		Issuer:       idp.server.URL,
+		RedirectURL:  testRedirectURL,
+	})
+}
+
+// publish 设置 JWKS 中公布的密钥，替换此前的全部密钥
+func (idp *mockIdP) publish(keys map[string]*ecdsa.PrivateKey) {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	idp.keys = keys
+}
+
+// issue 设置下一次授权码兑换返回的 ID 令牌
+func (idp *mockIdP) issue(challenge, idToken string) {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	idp.challenge = challenge
+	idp.idToken = idToken
+}
+
+func (idp *mockIdP) handleToken(w http.ResponseWriter, r *http.Request) {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" ||
+		r.PostForm.Get("client_id") != testClientID ||
+		r.PostForm.Get("client_secret") != testClientSecret ||
+		r.PostForm.Get("redirect_uri") != testRedirectURL {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+		return
+	}
+	if r.PostForm.Get("code") != testAuthCode ||
+		CodeChallengeS256(r.PostForm.Get("code_verifier")) != idp.challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"access_token": "access", "id_token": idp.idToken})
+}
+
+func (idp *mockIdP) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+
+	idp.jwksHits++
+	keys := make([]jsonWebKey, 0, len(idp.keys))
+	for kid, key := range idp.keys {
+		keys = append(keys, jsonWebKey{
+			Kty: "EC",
+			Kid: kid,
+			Use: "sig",
+			Crv: "P-256",
+			X:   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+			Y:   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+}
+
+// hits JWKS 端点被请求的次数
+func (idp *mockIdP) hits() int {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	return idp.jwksHits
+}
+
+// claims 返回一份有效的 ID 令牌载荷
+func (idp *mockIdP) claims() *idTokenClaims {
+	now := time.Now()
+	return &idTokenClaims{
+		Nonce:         "nonce-1",
+		Email:         "User@Example.com",
+		EmailVerified: "true",
+		Name:          "Test User",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    idp.server.URL,
+			Subject:   "subject-1",
+			Audience:  jwt.ClaimStrings{testClientID},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Minute)),
+		},
+	}
+}
+
+// newECKey 生成 P-256 签名密钥
+func newECKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	return key
+}
+
+// signIDToken 用指定密钥签发 ID 令牌
+func signIDToken(t *testing.T, kid string, key *ecdsa.PrivateKey, claims *idTokenClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+	return signed
+}
+
+// exchange 模拟一次完整的授权码 + PKCE 登录
+func exchange(idp *mockIdP, provider *OIDCProvider, idToken, nonce string) (*Identity, error) {
+	verifier := NewCodeVerifier()
+	idp.issue(CodeChallengeS256(verifier), idToken)
+	return provider.Exchange(context.Background(), testAuthCode, verifier, nonce)
+}
+
+func TestOIDCAuthorizationURL(t *testing.T) {
+	idp := newMockIdP(t)
+
+	raw := idp.provider().AuthorizationURL("state-1", "nonce-1", "challenge-1")
+	link, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("AuthorizationURL() = %q: %v", raw, err)
+	}
+	query := link.Query()
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             testClientID,
+		"redirect_uri":          testRedirectURL,
+		"state":                 "state-1",
+		"nonce":                 "nonce-1",
+		"code_challenge":        "challenge-1",
+		"code_challenge_method": "S256",
+	}
+	for name, value := range want {
+		if got := query.Get(name); got != value {
+			t.Errorf("AuthorizationURL() %s = %q, want %q", name, got, value)
+		}
+	}
+}
+
+func TestOIDCExchange(t *testing.T) {
+	idp := newMockIdP(t)
+	key := newECKey(t)
+	idp.publish(map[string]*ecdsa.PrivateKey{"k1": key})
+	provider := idp.provider()
+
+	identity, err := exchange(idp, provider, signIDToken(t, "k1", key, idp.claims()), "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	want := Identity{Provider: "mock", Subject: "subject-1", Email: "user@example.com", Nickname: "Test User"}
+	if *identity != want {
+		t.Errorf("Exchange() = %+v, want %+v", *identity, want)
+	}
+
+	// 未验证的邮箱不采信
+	claims := idp.claims()
+	claims.EmailVerified = false
+	identity, err = exchange(idp, provider, signIDToken(t, "k1", key, claims), "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	if identity.Email != "" {
+		t.Errorf("Exchange() email = %q for unverified email, want empty", identity.Email)
+	}
+}
+
+func TestOIDCExchangeRejectsCode(t *testing.T) {
+	idp := newMockIdP(t)
+	key := newECKey(t)
+	idp.publish(map[string]*ecdsa.PrivateKey{"k1": key})
+	provider := idp.provider()
+	idp.issue(CodeChallengeS256("verifier-1"), signIDToken(t, "k1", key, idp.claims()))
+
+	tests := []struct {
+		name, code, verifier string
+	}{
+		{"unknown code", "other-code", "verifier-1"},
+		{"wrong code_verifier", testAuthCode, "verifier-2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := provider.Exchange(context.Background(), tt.code, tt.verifier, "nonce-1"); !errors.Is(err, ErrInvalidCredential) {
+				t.Errorf("Exchange() error = %v, want ErrInvalidCredential", err)
+			}
+		})
+	}
+}
+
+func TestOIDCExchangeRejectsIDToken(t *testing.T) {
+	idp := newMockIdP(t)
+	key := newECKey(t)
+	idp.publish(map[string]*ecdsa.PrivateKey{"k1": key})
+	provider := idp.provider()
+
+	tests := []struct {
+		name   string
+		token  func() string
+		nonce  string
+		reason string
+	}{
+		{"forged signature", func() string {
+			return signIDToken(t, "k1", newECKey(t), idp.claims())
+		}, "nonce-1", "token signed by a key other than the published one"},
+		{"wrong issuer", func() string {
+			claims := idp.claims()
+			claims.Issuer = "https://evil.example.com"
+			return signIDToken(t, "k1", key, claims)
+		}, "nonce-1", "iss is not the configured issuer"},
+		{"wrong audience", func() string {
+			claims := idp.claims()
+			claims.Audience = jwt.ClaimStrings{"other-client"}
+			return signIDToken(t, "k1", key, claims)
+		}, "nonce-1", "aud is not our client id"},
+		{"nonce mismatch", func() string {
+			return signIDToken(t, "k1", key, idp.claims())
+		}, "nonce-2", "nonce differs from the one bound to the login"},
+		{"expired", func() string {
+			claims := idp.claims()
+			claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+			return signIDToken(t, "k1", key, claims)
+		}, "nonce-1", "exp is in the past"},
+		{"missing subject", func() string {
+			claims := idp.claims()
+			claims.Subject = ""
+			return signIDToken(t, "k1", key, claims)
+		}, "nonce-1", "sub is empty"},
+		{"unsigned", func() string {
+			token := jwt.NewWithClaims(jwt.SigningMethodNone, idp.claims())
+			token.Header["kid"] = "k1"
+			signed, _ := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+			return signed
+		}, "nonce-1", "alg none"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := exchange(idp, provider, tt.token(), tt.nonce); !errors.Is(err, ErrInvalidCredential) {
+				t.Errorf("Exchange() error = %v, want ErrInvalidCredential (%s)", err, tt.reason)
+			}
+		})
+	}
+}
+
+func TestJWKSKeyRotation(t *testing.T) {
+	idp := newMockIdP(t)
+	oldKey, newKey := newECKey(t), newECKey(t)
+	idp.publish(map[string]*ecdsa.PrivateKey{"k1": oldKey})
+	provider := idp.provider()
+
+	if _, err := exchange(idp, provider, signIDToken(t, "k1", oldKey, idp.claims()), "nonce-1"); err != nil {
+		t.Fatalf("Exchange() with k1 error = %v", err)
+	}
+	if got := idp.hits(); got != 1 {
+		t.Fatalf("JWKS fetched %d times, want 1", got)
+	}
+
+	// 提供方轮换密钥，只公布新密钥
+	idp.publish(map[string]*ecdsa.PrivateKey{"k2": newKey})
+	rotated := signIDToken(t, "k2", newKey, idp.claims())
+
+	// 距上次拉取不足最小刷新间隔，未知 kid 直接拒绝，不会每个令牌都拉取一次
+	if _, err := exchange(idp, provider, rotated, "nonce-1"); !errors.Is(err, ErrInvalidCredential) {
+		t.Fatalf("Exchange() with unknown kid error = %v, want ErrInvalidCredential", err)
+	}
+	if got := idp.hits(); got != 1 {
+		t.Fatalf("JWKS fetched %d times within the refresh interval, want 1", got)
+	}
+
+	// 超过最小刷新间隔后，未知 kid 触发重新拉取并找到新密钥
+	provider.jwks.mu.Lock()
+	provider.jwks.fetchedAt = time.Now().Add(-jwksMinRefreshInterval - time.Second)
+	provider.jwks.mu.Unlock()
+
+	if _, err := exchange(idp, provider, rotated, "nonce-1"); err != nil {
+		t.Fatalf("Exchange() with rotated key error = %v", err)
+	}
+	if got := idp.hits(); got != 2 {
+		t.Fatalf("JWKS fetched %d times, want 2", got)
+	}
+
+	// 旧密钥已从公钥集移除，用它签发的令牌不再被接受
+	if _, err := exchange(idp, provider, signIDToken(t, "k1", oldKey, idp.claims()), "nonce-1"); !errors.Is(err, ErrInvalidCredential) {
+		t.Errorf("Exchange() with retired key error = %v, want ErrInvalidCredential", err)
+	}
+}
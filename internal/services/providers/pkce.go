@@ -0,0 +1,23 @@
+package providers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+)
+
+// NewCodeVerifier 生成 PKCE code_verifier（RFC 7636，43 个字符）
+func NewCodeVerifier() string {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
+
+// CodeChallengeS256 计算 code_verifier 对应的 S256 code_challenge
+func CodeChallengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
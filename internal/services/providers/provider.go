@@ -0,0 +1,42 @@
+package providers
+
+import (
+	"context"
+	"errors"
+)
+
+// 第三方登录错误
+var (
+	// ErrInvalidCredential 提供方拒绝了登录凭据（如 code 无效或已使用）
+	ErrInvalidCredential = errors.New("provider rejected credential")
+	// ErrProviderUnavailable 提供方服务不可用或返回了无法解析的响应
+	ErrProviderUnavailable = errors.New("provider unavailable")
+)
+
+// Identity 第三方提供方返回的用户身份
+type Identity struct {
+	Provider string // 提供方名称
+	Subject  string // 提供方内的用户标识
+	UnionID  string // 跨应用统一标识（可选）
+	Email    string // 邮箱（可选）
+	Nickname string // 昵称（可选）
+}
+
+// LoginProvider 第三方登录提供方
+// 不同提供方的凭据格式不同，例如微信小程序为 wx.login 返回的 code
+type LoginProvider interface {
+	// Name 提供方名称，同时作为 user_identities.provider 的取值
+	Name() string
+	// Authenticate 用客户端凭据向提供方换取用户身份
+	Authenticate(ctx context.Context, credential string) (*Identity, error)
+}
+
+// RedirectProvider 基于浏览器跳转的授权码登录提供方（OAuth2 / OpenID Connect）
+type RedirectProvider interface {
+	// Name 提供方名称，同时作为 user_identities.provider 的取值
+	Name() string
+	// AuthorizationURL 构造跳转到提供方授权页的地址
+	AuthorizationURL(state, nonce, codeChallenge string) string
+	// Exchange 用授权码和 PKCE code_verifier 换取并校验用户身份
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error)
+}
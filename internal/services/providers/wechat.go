@@ -0,0 +1,116 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// WeChatProviderName 微信小程序提供方名称
+	WeChatProviderName = "wechat"
+	// DefaultWeChatCode2SessionURL 微信 code2session 接口地址
+	DefaultWeChatCode2SessionURL = "https://api.weixin.qq.com/sns/jscode2session"
+	// defaultWeChatTimeout 默认请求超时
+	defaultWeChatTimeout = 5 * time.Second
+)
+
+// WeChatConfig 微信小程序登录配置
+type WeChatConfig struct {
+	AppID           string
+	AppSecret       string
+	Code2SessionURL string // 可指向本地桩服务用于测试
+	Timeout         time.Duration
+}
+
+// WeChatProvider 微信小程序登录提供方
+type WeChatProvider struct {
+	config WeChatConfig
+	client *http.Client
+}
+
+// code2SessionResponse 微信 code2session 接口响应
+type code2SessionResponse struct {
+	OpenID     string `json:"openid"`
+	SessionKey string `json:"session_key"`
+	UnionID    string `json:"unionid"`
+	ErrCode    int    `json:"errcode"`
+	ErrMsg     string `json:"errmsg"`
+}
+
+// NewWeChatProvider 创建微信小程序登录提供方
+func NewWeChatProvider(config WeChatConfig) *WeChatProvider {
+	// 设置默认值
+	if config.Code2SessionURL == "" {
+		config.Code2SessionURL = DefaultWeChatCode2SessionURL
+	}
+	if config.Timeout == 0 {
+		config.Timeout = defaultWeChatTimeout
+	}
+
+	return &WeChatProvider{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// Name 提供方名称
+func (p *WeChatProvider) Name() string {
+	return WeChatProviderName
+}
+
+// Authenticate 使用 wx.login 获取的 code 换取 openid/unionid
+func (p *WeChatProvider) Authenticate(ctx context.Context, code string) (*Identity, error) {
+	query := url.Values{}
+	query.Set("appid", p.config.AppID)
+	query.Set("secret", p.config.AppSecret)
+	query.Set("js_code", code)
+	query.Set("grant_type", "authorization_code")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.Code2SessionURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build code2session request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: code2session returned status %d", ErrProviderUnavailable, resp.StatusCode)
+	}
+
+	var result code2SessionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("%w: invalid code2session response: %v", ErrProviderUnavailable, err)
+	}
+
+	if result.ErrCode != 0 {
+		logrus.WithFields(logrus.Fields{
+			"errcode": result.ErrCode,
+			"errmsg":  result.ErrMsg,
+		}).Warn("WeChat code2session rejected")
+
+		// -1 为微信系统繁忙，其余均视为凭据问题（如 40029 code 无效、40163 code 已使用）
+		if result.ErrCode == -1 {
+			return nil, fmt.Errorf("%w: %s", ErrProviderUnavailable, result.ErrMsg)
+		}
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCredential, result.ErrMsg)
+	}
+	if result.OpenID == "" {
+		return nil, fmt.Errorf("%w: code2session returned empty openid", ErrProviderUnavailable)
+	}
+
+	return &Identity{
+		Provider: WeChatProviderName,
+		Subject:  result.OpenID,
+		UnionID:  result.UnionID,
+	}, nil
+}
@@ -0,0 +1,80 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newWeChatStub 启动模拟 code2session 接口的本地服务
+func newWeChatStub(t *testing.T, handler http.HandlerFunc) *WeChatProvider {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return NewWeChatProvider(WeChatConfig{
+		AppID:           "wx-app",
+		AppSecret:       "wx-secret",
+		Code2SessionURL: server.URL,
+	})
+}
+
+func TestWeChatAuthenticate(t *testing.T) {
+	provider := newWeChatStub(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("appid") != "wx-app" || query.Get("secret") != "wx-secret" ||
+			query.Get("grant_type") != "authorization_code" {
+			t.Errorf("unexpected code2session query %q", r.URL.RawQuery)
+		}
+		if query.Get("js_code") != "good-code" {
+			fmt.Fprint(w, `{"errcode": 40029, "errmsg": "invalid code"}`)
+			return
+		}
+		fmt.Fprint(w, `{"openid": "openid-1", "unionid": "unionid-1", "session_key": "key"}`)
+	})
+
+	identity, err := provider.Authenticate(context.Background(), "good-code")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	want := Identity{Provider: WeChatProviderName, Subject: "openid-1", UnionID: "unionid-1"}
+	if *identity != want {
+		t.Errorf("Authenticate() = %+v, want %+v", *identity, want)
+	}
+
+	if _, err := provider.Authenticate(context.Background(), "used-code"); !errors.Is(err, ErrInvalidCredential) {
+		t.Errorf("Authenticate() with rejected code error = %v, want ErrInvalidCredential", err)
+	}
+}
+
+func TestWeChatAuthenticateUnavailable(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"system busy", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"errcode": -1, "errmsg": "system error"}`)
+		}},
+		{"server error", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}},
+		{"invalid json", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `<html>`)
+		}},
+		{"empty openid", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"session_key": "key"}`)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newWeChatStub(t, tt.handler)
+			if _, err := provider.Authenticate(context.Background(), "code"); !errors.Is(err, ErrProviderUnavailable) {
+				t.Errorf("Authenticate() error = %v, want ErrProviderUnavailable", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/xuchengvcc/restart-life-api/internal/database"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository/mysql"
+	redisrepo "github.com/xuchengvcc/restart-life-api/internal/repository/redis"
+	"github.com/xuchengvcc/restart-life-api/internal/utils"
+)
+
+const testAccessExpiry = 15 * time.Minute
+
+// newTestRedis 启动 miniredis 并返回连接到它的 RedisDB
+func newTestRedis(t *testing.T) (*database.RedisDB, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	rdb := &database.RedisDB{Client: goredis.NewClient(&goredis.Options{Addr: server.Addr()})}
+	t.Cleanup(func() { rdb.Client.Close() })
+	return rdb, server
+}
+
+// newTestMySQL 创建基于 sqlmock 的 MySQLDB
+func newTestMySQL(t *testing.T) (*database.MySQLDB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return &database.MySQLDB{DB: db}, mock
+}
+
+// newTestTokenService 创建使用测试数据库的令牌服务
+func newTestTokenService(db *database.MySQLDB, rdb *database.RedisDB) *TokenService {
+	return NewTokenService(
+		utils.NewJWTManager("jwt-secret", testAccessExpiry),
+		redisrepo.NewRefreshTokenRepository(rdb),
+		redisrepo.NewTokenRevocationRepository(rdb),
+		redisrepo.NewSessionRepository(rdb),
+		mysql.NewRoleRepository(db),
+		0,
+	)
+}
+
+// userRows 按 users 表查询字段构造一行结果
+func userRows(user *models.User) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows([]string{
+		"user_id", "username", "email", "email_verified_at", "password_hash", "created_at", "updated_at", "last_login",
+		"last_login_ip", "failed_login_count", "last_failed_login_at", "last_failed_login_ip", "is_active",
+		"deletion_scheduled_at", "is_guest", "device_id", "avatar_url", "bio", "birth_date", "gender", "country", "timezone",
+	}).AddRow(
+		user.UserID, user.Username, nullable(user.Email), timeValue(user.EmailVerifiedAt), user.PasswordHash, now, now, nil,
+		nil, user.FailedLogins, nil, nil, user.IsActive,
+		timeValue(user.DeletionAt), user.IsGuest, nil, nil, nil, nil, nil, nil, nil,
+	)
+}
+
+// nameRows 构造权限名、角色名等单列名称查询结果
+func nameRows(names ...string) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"name"})
+	for _, name := range names {
+		rows.AddRow(name)
+	}
+	return rows
+}
+
+// nullable 空字符串对应 NULL
+func nullable(value string) driver.Value {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// timeValue nil 对应 NULL
+func timeValue(value *time.Time) driver.Value {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
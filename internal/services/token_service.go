@@ -0,0 +1,324 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository"
+	"github.com/xuchengvcc/restart-life-api/internal/repository/mysql"
+	redisrepo "github.com/xuchengvcc/restart-life-api/internal/repository/redis"
+	"github.com/xuchengvcc/restart-life-api/internal/utils"
+)
+
+// DefaultRefreshTokenExpiry 默认刷新令牌有效期
+const DefaultRefreshTokenExpiry = 7 * 24 * time.Hour
+
+// 令牌业务错误
+var (
+	// ErrInvalidRefreshToken 刷新令牌无效或已过期
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	// ErrRefreshTokenReused 检测到已轮换的刷新令牌被重复使用
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+	// ErrSessionNotFound 会话不存在或不属于当前用户
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+// TokenService 令牌服务，负责访问令牌签发与刷新令牌轮换
+type TokenService struct {
+	jwt           *utils.JWTManager
+	refreshTokens *redisrepo.RefreshTokenRepository
+	revocations   *redisrepo.TokenRevocationRepository
+	sessions      *redisrepo.SessionRepository
+	roles         *mysql.RoleRepository
+	refreshExpiry time.Duration
+	sessionLimits map[string]int // 平台 -> 同时在线会话上限
+}
+
+// NewTokenService 创建令牌服务
+func NewTokenService(
+	jwt *utils.JWTManager,
+	refreshTokens *redisrepo.RefreshTokenRepository,
+	revocations *redisrepo.TokenRevocationRepository,
+	sessions *redisrepo.SessionRepository,
+	roles *mysql.RoleRepository,
+	refreshExpiry time.Duration,
+) *TokenService {
+	if refreshExpiry <= 0 {
+		refreshExpiry = DefaultRefreshTokenExpiry
+	}
+	return &TokenService{
+		jwt:           jwt,
+		refreshTokens: refreshTokens,
+		revocations:   revocations,
+		sessions:      sessions,
+		roles:         roles,
+		refreshExpiry: refreshExpiry,
+		sessionLimits: make(map[string]int),
+	}
+}
+
+// SetSessionLimits 设置各平台同时在线会话上限，未设置或不大于 0 的平台不限制
+func (s *TokenService) SetSessionLimits(limits map[string]int) {
+	s.sessionLimits = make(map[string]int, len(limits))
+	for platform, limit := range limits {
+		s.sessionLimits[strings.ToLower(platform)] = limit
+	}
+}
+
+// IssueTokenPair 为新的登录签发访问令牌和刷新令牌（开启新的令牌家族，即新会话）
+func (s *TokenService) IssueTokenPair(ctx context.Context, user *models.User, device models.DeviceInfo) (*models.TokenPair, error) {
+	if err := s.enforceSessionLimit(ctx, user.UserID, device.Platform); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &models.Session{
+		SessionID:  utils.RandomHex(16),
+		UserID:     user.UserID,
+		Platform:   device.Platform,
+		Version:    device.Version,
+		UserAgent:  device.UserAgent,
+		IP:         device.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+	if err := s.sessions.Create(ctx, session, s.refreshExpiry); err != nil {
+		return nil, err
+	}
+
+	return s.issue(ctx, user, session.SessionID, device.Platform)
+}
+
+// RotateRefreshToken 校验并消费刷新令牌，返回其所属记录
+// 已轮换过的令牌再次出现时视为被盗用，整个令牌家族会被吊销
+func (s *TokenService) RotateRefreshToken(ctx context.Context, refreshToken string) (*models.RefreshToken, error) {
+	tokenHash := hashRefreshToken(refreshToken)
+
+	record, err := s.refreshTokens.Get(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	firstUse, err := s.refreshTokens.MarkRotated(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			// 读取后恰好过期
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	current, err := s.refreshTokens.FamilyCurrent(ctx, record.FamilyID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			// 家族已被吊销
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	if !firstUse || current != tokenHash {
+		if err := s.refreshTokens.RevokeFamily(ctx, record.FamilyID); err != nil {
+			return nil, err
+		}
+		logrus.WithFields(logrus.Fields{
+			"user_id":   record.UserID,
+			"family_id": record.FamilyID,
+			"platform":  record.Platform,
+		}).Warn("Refresh token reuse detected, token family revoked")
+		return nil, ErrRefreshTokenReused
+	}
+
+	return record, nil
+}
+
+// IssueRotatedTokenPair 在原令牌家族内签发新的令牌对，并更新会话活跃信息
+func (s *TokenService) IssueRotatedTokenPair(ctx context.Context, user *models.User, previous *models.RefreshToken, device models.DeviceInfo) (*models.TokenPair, error) {
+	if err := s.sessions.Touch(ctx, previous.FamilyID, device, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.issue(ctx, user, previous.FamilyID, previous.Platform)
+}
+
+// ValidateAccessToken 校验访问令牌
+// 令牌本身被吊销、用户执行了全部登出、或所属登录会话被吊销时，令牌均视为失效
+func (s *TokenService) ValidateAccessToken(ctx context.Context, accessToken string) (*utils.Claims, error) {
+	claims, err := s.jwt.ParseAccessToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.revocations.IsTokenIDRevoked(ctx, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, utils.ErrTokenRevoked
+	}
+
+	userID, err := strconv.ParseUint(claims.UserID, 10, 64)
+	if err != nil {
+		return nil, utils.ErrTokenInvalid
+	}
+	generation, err := s.revocations.GetUserGeneration(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Generation < generation {
+		return nil, utils.ErrTokenRevoked
+	}
+
+	if claims.SessionID != "" {
+		if _, err := s.refreshTokens.FamilyCurrent(ctx, claims.SessionID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, utils.ErrTokenRevoked
+			}
+			return nil, err
+		}
+	}
+
+	return claims, nil
+}
+
+// RevokeFamily 吊销令牌家族
+func (s *TokenService) RevokeFamily(ctx context.Context, familyID string) error {
+	return s.refreshTokens.RevokeFamily(ctx, familyID)
+}
+
+// RevokeSession 吊销当前访问令牌及其所属登录会话
+func (s *TokenService) RevokeSession(ctx context.Context, claims *utils.Claims) error {
+	if claims.ExpiresAt != nil {
+		if err := s.revocations.RevokeTokenID(ctx, claims.ID, time.Until(claims.ExpiresAt.Time)); err != nil {
+			return err
+		}
+	}
+	if claims.SessionID != "" {
+		return s.refreshTokens.RevokeFamily(ctx, claims.SessionID)
+	}
+	return nil
+}
+
+// ListSessions 列出用户的活跃会话
+func (s *TokenService) ListSessions(ctx context.Context, userID uint64) ([]*models.Session, error) {
+	return s.sessions.ListByUser(ctx, userID)
+}
+
+// RevokeUserSession 吊销用户的指定会话
+func (s *TokenService) RevokeUserSession(ctx context.Context, userID uint64, sessionID string) error {
+	session, err := s.sessions.Get(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	if session.UserID != userID {
+		return ErrSessionNotFound
+	}
+	return s.sessions.Delete(ctx, userID, sessionID)
+}
+
+// InvalidateAccessTokens 使用户已签发的访问令牌全部失效，刷新令牌保留
+// 用于角色变更等场景：客户端刷新令牌后即可拿到新的权限
+func (s *TokenService) InvalidateAccessTokens(ctx context.Context, userID uint64) error {
+	return s.revocations.BumpUserGeneration(ctx, userID, s.jwt.Expiry())
+}
+
+// RevokeAllForUser 吊销用户已签发的全部访问令牌和刷新令牌
+func (s *TokenService) RevokeAllForUser(ctx context.Context, userID uint64) error {
+	if err := s.revocations.BumpUserGeneration(ctx, userID, s.jwt.Expiry()); err != nil {
+		return err
+	}
+	return s.refreshTokens.RevokeUserFamilies(ctx, userID)
+}
+
+// issue 签发访问令牌并在指定家族中保存新的刷新令牌
+func (s *TokenService) issue(ctx context.Context, user *models.User, familyID, platform string) (*models.TokenPair, error) {
+	permissions, err := s.roles.GetUserPermissions(ctx, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 按代数而非签发时间判断吊销，吊销后立即签发的令牌不会因与吊销处于同一秒而失效
+	generation, err := s.revocations.AcquireUserGeneration(ctx, user.UserID, s.jwt.Expiry())
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, _, err := s.jwt.GenerateAccessToken(
+		strconv.FormatUint(user.UserID, 10), user.Username, platform, familyID, permissions, generation,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken := utils.RandomHex(32)
+	record := &models.RefreshToken{
+		TokenHash: hashRefreshToken(refreshToken),
+		UserID:    user.UserID,
+		FamilyID:  familyID,
+		Platform:  platform,
+		IssuedAt:  time.Now(),
+	}
+	if err := s.refreshTokens.Save(ctx, record, s.refreshExpiry); err != nil {
+		return nil, fmt.Errorf("failed to issue refresh token: %w", err)
+	}
+
+	return &models.TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(s.jwt.Expiry().Seconds()),
+		RefreshExpiresIn: int64(s.refreshExpiry.Seconds()),
+	}, nil
+}
+
+// enforceSessionLimit 平台会话数达到上限时，挤掉该平台最早创建的会话
+func (s *TokenService) enforceSessionLimit(ctx context.Context, userID uint64, platform string) error {
+	limit := s.sessionLimits[strings.ToLower(platform)]
+	if limit <= 0 {
+		return nil
+	}
+
+	sessions, err := s.sessions.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	samePlatform := make([]*models.Session, 0, len(sessions))
+	for _, session := range sessions {
+		if strings.EqualFold(session.Platform, platform) {
+			samePlatform = append(samePlatform, session)
+		}
+	}
+
+	// 为新会话腾出一个位置，列表已按创建时间升序
+	for i := 0; i <= len(samePlatform)-limit; i++ {
+		if err := s.sessions.Delete(ctx, userID, samePlatform[i].SessionID); err != nil {
+			return err
+		}
+		logrus.WithFields(logrus.Fields{
+			"user_id":    userID,
+			"session_id": samePlatform[i].SessionID,
+			"platform":   platform,
+		}).Info("Session evicted by per-platform session limit")
+	}
+	return nil
+}
+
+// hashRefreshToken 计算刷新令牌的存储哈希
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/utils"
+)
+
+// tokenFixture 基于 sqlmock 和 miniredis 的令牌服务
+type tokenFixture struct {
+	service *TokenService
+	sql     sqlmock.Sqlmock
+	redis   *miniredis.Miniredis
+}
+
+func newTokenFixture(t *testing.T) *tokenFixture {
+	t.Helper()
+
+	db, mock := newTestMySQL(t)
+	rdb, server := newTestRedis(t)
+
+	return &tokenFixture{
+		service: newTestTokenService(db, rdb),
+		sql:     mock,
+		redis:   server,
+	}
+}
+
+// login 模拟一次登录，签发新会话的令牌对
+func (f *tokenFixture) login(t *testing.T, user *models.User) *models.TokenPair {
+	t.Helper()
+
+	f.sql.ExpectQuery(`FROM user_roles`).WithArgs(user.UserID).WillReturnRows(nameRows(models.PermissionContentRead))
+	pair, err := f.service.IssueTokenPair(context.Background(), user, models.DeviceInfo{Platform: "ios"})
+	if err != nil {
+		t.Fatalf("IssueTokenPair() error = %v", err)
+	}
+	return pair
+}
+
+func TestRevokeAllThenLoginSameSecond(t *testing.T) {
+	f := newTokenFixture(t)
+	ctx := context.Background()
+	user := &models.User{UserID: testUserID, Username: "alice", IsActive: true}
+
+	before := f.login(t, user)
+	if _, err := f.service.ValidateAccessToken(ctx, before.AccessToken); err != nil {
+		t.Fatalf("ValidateAccessToken() error = %v", err)
+	}
+
+	// 吊销后立即重新登录，两者通常处于同一秒内
+	if err := f.service.RevokeAllForUser(ctx, user.UserID); err != nil {
+		t.Fatalf("RevokeAllForUser() error = %v", err)
+	}
+	after := f.login(t, user)
+
+	if _, err := f.service.ValidateAccessToken(ctx, before.AccessToken); !errors.Is(err, utils.ErrTokenRevoked) {
+		t.Errorf("ValidateAccessToken() for token issued before revocation error = %v, want ErrTokenRevoked", err)
+	}
+	if _, err := f.service.ValidateAccessToken(ctx, after.AccessToken); err != nil {
+		t.Errorf("ValidateAccessToken() for token issued right after revocation error = %v", err)
+	}
+
+	// 代数记录至少保留到最新令牌过期，否则代数归零后该令牌能躲过下一次吊销
+	if ttl := f.redis.TTL("auth:user_token_gen:7"); ttl < testAccessExpiry {
+		t.Errorf("token generation TTL = %v, want at least %v", ttl, testAccessExpiry)
+	}
+
+	if err := f.service.InvalidateAccessTokens(ctx, user.UserID); err != nil {
+		t.Fatalf("InvalidateAccessTokens() error = %v", err)
+	}
+	if _, err := f.service.ValidateAccessToken(ctx, after.AccessToken); !errors.Is(err, utils.ErrTokenRevoked) {
+		t.Errorf("ValidateAccessToken() after InvalidateAccessTokens error = %v, want ErrTokenRevoked", err)
+	}
+	if err := f.sql.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	f := newTokenFixture(t)
+	ctx := context.Background()
+	user := &models.User{UserID: testUserID, Username: "alice", IsActive: true}
+
+	stolen := f.login(t, user)
+	other := f.login(t, user)
+
+	record, err := f.service.RotateRefreshToken(ctx, stolen.RefreshToken)
+	if err != nil {
+		t.Fatalf("RotateRefreshToken() error = %v", err)
+	}
+	f.sql.ExpectQuery(`FROM user_roles`).WithArgs(user.UserID).WillReturnRows(nameRows(models.PermissionContentRead))
+	rotated, err := f.service.IssueRotatedTokenPair(ctx, user, record, models.DeviceInfo{Platform: "ios"})
+	if err != nil {
+		t.Fatalf("IssueRotatedTokenPair() error = %v", err)
+	}
+
+	// 已轮换的刷新令牌再次使用，视为被盗用
+	if _, err := f.service.RotateRefreshToken(ctx, stolen.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("RotateRefreshToken() with a rotated token error = %v, want ErrRefreshTokenReused", err)
+	}
+
+	// 整个家族随之失效，包括轮换后签发的令牌
+	if _, err := f.service.RotateRefreshToken(ctx, rotated.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("RotateRefreshToken() with the family's newest token error = %v, want ErrInvalidRefreshToken", err)
+	}
+	if _, err := f.service.ValidateAccessToken(ctx, rotated.AccessToken); !errors.Is(err, utils.ErrTokenRevoked) {
+		t.Errorf("ValidateAccessToken() in the revoked family error = %v, want ErrTokenRevoked", err)
+	}
+
+	// 同一用户的其他登录会话不受影响
+	if _, err := f.service.ValidateAccessToken(ctx, other.AccessToken); err != nil {
+		t.Errorf("ValidateAccessToken() in another family error = %v", err)
+	}
+	if _, err := f.service.RotateRefreshToken(ctx, other.RefreshToken); err != nil {
+		t.Errorf("RotateRefreshToken() in another family error = %v", err)
+	}
+
+	if err := f.sql.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
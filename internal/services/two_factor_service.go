@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xuchengvcc/restart-life-api/internal/models"
+	"github.com/xuchengvcc/restart-life-api/internal/repository"
+	"github.com/xuchengvcc/restart-life-api/internal/repository/mysql"
+	redisrepo "github.com/xuchengvcc/restart-life-api/internal/repository/redis"
+	"github.com/xuchengvcc/restart-life-api/internal/utils"
+)
+
+const (
+	// TOTPIssuer 验证器 App 中显示的服务名称
+	TOTPIssuer = "Restart Life"
+	// loginChallengeTTL 登录挑战有效期
+	loginChallengeTTL = 5 * time.Minute
+	// loginChallengeMaxAttempts 单个登录挑战允许的最大验证次数
+	loginChallengeMaxAttempts = 5
+	// recoveryCodeCount 每次生成的恢复码数量
+	recoveryCodeCount = 10
+)
+
+// 两步验证业务错误
+var (
+	// ErrTwoFactorAlreadyEnabled 两步验证已启用
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication already enabled")
+	// ErrTwoFactorNotEnabled 两步验证未启用
+	ErrTwoFactorNotEnabled = errors.New("two-factor authentication not enabled")
+	// ErrTwoFactorSetupRequired 尚未生成 TOTP 密钥
+	ErrTwoFactorSetupRequired = errors.New("two-factor setup not started")
+	// ErrInvalidTwoFactorCode 验证码或恢复码错误
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+	// ErrInvalidLoginChallenge 登录挑战无效、已过期或尝试次数过多
+	ErrInvalidLoginChallenge = errors.New("invalid login challenge")
+	// ErrGuestTwoFactor 游客账户不能启用两步验证
+	ErrGuestTwoFactor = errors.New("guest accounts cannot enable two-factor authentication")
+)
+
+// TwoFactorService TOTP 两步验证服务
+type TwoFactorService struct {
+	totps      *mysql.TOTPRepository
+	challenges *redisrepo.TwoFactorRepository
+	secrets    *utils.SecretBox
+}
+
+// NewTwoFactorService 创建两步验证服务
+func NewTwoFactorService(totps *mysql.TOTPRepository, challenges *redisrepo.TwoFactorRepository, secrets *utils.SecretBox) *TwoFactorService {
+	return &TwoFactorService{
+		totps:      totps,
+		challenges: challenges,
+		secrets:    secrets,
+	}
+}
+
+// IsEnabled 用户是否已启用两步验证
+func (s *TwoFactorService) IsEnabled(ctx context.Context, userID uint64) (bool, error) {
+	totp, err := s.totps.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return totp.EnabledAt != nil, nil
+}
+
+// BeginSetup 生成新的 TOTP 密钥，需调用 Confirm 校验验证码后才会启用
+func (s *TwoFactorService) BeginSetup(ctx context.Context, user *models.User) (*models.TOTPSetupResponse, error) {
+	if user.IsGuest {
+		return nil, ErrGuestTwoFactor
+	}
+
+	secret := utils.GenerateTOTPSecret()
+	sealed, err := s.secrets.Seal(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.totps.SavePending(ctx, user.UserID, sealed); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTwoFactorAlreadyEnabled
+		}
+		return nil, err
+	}
+
+	account := user.Email
+	if account == "" {
+		account = user.Username
+	}
+	return &models.TOTPSetupResponse{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPURI(TOTPIssuer, account, secret),
+	}, nil
+}
+
+// Confirm 校验验证码并启用两步验证，返回恢复码
+func (s *TwoFactorService) Confirm(ctx context.Context, userID uint64, code string) ([]string, error) {
+	totp, err := s.totps.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTwoFactorSetupRequired
+		}
+		return nil, err
+	}
+	if totp.EnabledAt != nil {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	if err := s.verifyTOTP(ctx, totp, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes := generateRecoveryCodes()
+	if err := s.totps.Enable(ctx, userID, hashes, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTwoFactorAlreadyEnabled
+		}
+		return nil, err
+	}
+
+	logrus.WithField("user_id", userID).Info("Two-factor authentication enabled")
+	return codes, nil
+}
+
+// Disable 校验验证码或恢复码后关闭两步验证
+func (s *TwoFactorService) Disable(ctx context.Context, userID uint64, code string) error {
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+	if err := s.totps.Delete(ctx, userID); err != nil {
+		return err
+	}
+
+	logrus.WithField("user_id", userID).Info("Two-factor authentication disabled")
+	return nil
+}
+
+// RegenerateRecoveryCodes 校验验证码后重新生成恢复码
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID uint64, code string) ([]string, error) {
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes := generateRecoveryCodes()
+	if err := s.totps.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	logrus.WithField("user_id", userID).Info("Recovery codes regenerated")
+	return codes, nil
+}
+
+// Verify 校验已启用账户的 TOTP 验证码或恢复码，恢复码校验通过即作废
+func (s *TwoFactorService) Verify(ctx context.Context, userID uint64, code string) error {
+	totp, err := s.totps.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrTwoFactorNotEnabled
+		}
+		return err
+	}
+	if totp.EnabledAt == nil {
+		return ErrTwoFactorNotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == utils.TOTPDigits {
+		return s.verifyTOTP(ctx, totp, code)
+	}
+
+	if err := s.totps.UseRecoveryCode(ctx, userID, hashRecoveryCode(code), time.Now()); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrInvalidTwoFactorCode
+		}
+		return err
+	}
+	logrus.WithField("user_id", userID).Info("Recovery code used")
+	return nil
+}
+
+// CreateLoginChallenge 密码等第一因素校验通过后创建登录挑战
+func (s *TwoFactorService) CreateLoginChallenge(ctx context.Context, userID uint64, device models.DeviceInfo) (*models.TwoFactorChallenge, error) {
+	token := utils.RandomHex(32)
+	state := &models.LoginChallengeState{UserID: userID, Device: device}
+	if err := s.challenges.SaveChallenge(ctx, token, state, loginChallengeTTL); err != nil {
+		return nil, err
+	}
+
+	return &models.TwoFactorChallenge{
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+		ExpiresIn:         int64(loginChallengeTTL.Seconds()),
+	}, nil
+}
+
+// OpenLoginChallenge 读取登录挑战并累加尝试次数，尝试次数过多时挑战作废
+func (s *TwoFactorService) OpenLoginChallenge(ctx context.Context, token string) (*models.LoginChallengeState, error) {
+	state, attempts, err := s.challenges.GetChallenge(ctx, token)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidLoginChallenge
+		}
+		return nil, err
+	}
+	if attempts > loginChallengeMaxAttempts {
+		if err := s.challenges.DeleteChallenge(ctx, token); err != nil {
+			logrus.WithError(err).Warn("Failed to delete exhausted login challenge")
+		}
+		return nil, ErrInvalidLoginChallenge
+	}
+	return state, nil
+}
+
+// CompleteLoginChallenge 校验登录挑战的第二因素，成功后挑战作废
+func (s *TwoFactorService) CompleteLoginChallenge(ctx context.Context, token string, state *models.LoginChallengeState, code string) error {
+	if err := s.Verify(ctx, state.UserID, code); err != nil {
+		return err
+	}
+	return s.challenges.DeleteChallenge(ctx, token)
+}
+
+// verifyTOTP 校验 TOTP 验证码，同一时间步的验证码只能使用一次
+func (s *TwoFactorService) verifyTOTP(ctx context.Context, totp *models.UserTOTP, code string) error {
+	secret, err := s.secrets.Open(totp.SecretEncrypted)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt totp secret: %w", err)
+	}
+
+	step, ok := utils.ValidateTOTP(secret, code, time.Now())
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+
+	// 标记保留到该时间步超出允许的时钟偏差为止
+	firstUse, err := s.challenges.MarkTOTPStepUsed(ctx, totp.UserID, step, 3*utils.TOTPPeriod)
+	if err != nil {
+		return err
+	}
+	if !firstUse {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// generateRecoveryCodes 生成恢复码（xxxxx-xxxxx 格式）及其哈希
+func generateRecoveryCodes() ([]string, []string) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		raw := utils.RandomHex(5)
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	return codes, hashes
+}
+
+// hashRecoveryCode 计算恢复码的存储哈希，忽略大小写和分隔符
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
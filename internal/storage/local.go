@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultLocalURLPrefix 本地存储默认的访问路径前缀
+const DefaultLocalURLPrefix = "/uploads"
+
+// LocalStore 本地文件系统存储，文件由 API 服务以静态资源方式提供
+type LocalStore struct {
+	dir       string
+	urlPrefix string
+}
+
+// NewLocalStore 创建本地文件系统存储
+func NewLocalStore(dir, urlPrefix string) (*LocalStore, error) {
+	// 设置默认值
+	if dir == "" {
+		dir = "data/uploads"
+	}
+	if urlPrefix == "" {
+		urlPrefix = DefaultLocalURLPrefix
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return &LocalStore{
+		dir:       dir,
+		urlPrefix: strings.TrimRight(urlPrefix, "/"),
+	}, nil
+}
+
+// Dir 存储根目录
+func (s *LocalStore) Dir() string {
+	return s.dir
+}
+
+// URLPrefix 访问路径前缀
+func (s *LocalStore) URLPrefix() string {
+	return s.urlPrefix
+}
+
+// Put 写入对象，先写临时文件再重命名，避免读到写了一半的文件
+func (s *LocalStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create blob file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	return nil
+}
+
+// Get 读取对象
+func (s *LocalStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrBlobNotFound
+		}
+		return nil, fmt.Errorf("failed to read blob: %w", err)
+	}
+	return data, nil
+}
+
+// Delete 删除对象
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	err := os.Remove(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	return nil
+}
+
+// URL 返回对象的访问地址
+func (s *LocalStore) URL(key string) string {
+	return s.urlPrefix + "/" + key
+}
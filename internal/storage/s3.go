@@ -0,0 +1,239 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// defaultS3Timeout 默认请求超时
+const defaultS3Timeout = 30 * time.Second
+
+// S3Config S3 兼容存储配置
+type S3Config struct {
+	Endpoint        string // 如 https://s3.ap-east-1.amazonaws.com、http://localhost:9000（MinIO）
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	UsePathStyle    bool   // 使用 endpoint/bucket/key 形式访问，MinIO 等自建服务通常需要开启
+	PublicBaseURL   string // 对象公开访问地址前缀（如 CDN），为空时使用存储地址
+	Timeout         time.Duration
+}
+
+// S3Store S3 兼容对象存储，使用 AWS Signature V4 签名
+// 对象需通过存储桶策略或 CDN 对外公开读取
+type S3Store struct {
+	config   *S3Config
+	endpoint *url.URL
+	client   *http.Client
+}
+
+// NewS3Store 创建 S3 兼容对象存储
+func NewS3Store(config *S3Config) (*S3Store, error) {
+	if config.Endpoint == "" || config.Bucket == "" {
+		return nil, errors.New("s3 endpoint and bucket are required")
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, errors.New("s3 access key is required")
+	}
+	endpoint, err := url.Parse(strings.TrimRight(config.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", config.Endpoint)
+	}
+
+	// 设置默认值
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if config.Timeout == 0 {
+		config.Timeout = defaultS3Timeout
+	}
+
+	return &S3Store{
+		config:   config,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: config.Timeout},
+	}, nil
+}
+
+// Put 上传对象
+func (s *S3Store) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to build s3 request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.ContentLength = int64(len(data))
+
+	_, err = s.do(req, data)
+	return err
+}
+
+// Get 下载对象
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build s3 request: %w", err)
+	}
+	return s.do(req, nil)
+}
+
+// Delete 删除对象
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return fmt.Errorf("failed to build s3 request: %w", err)
+	}
+	_, err = s.do(req, nil)
+	return err
+}
+
+// URL 返回对象的公开访问地址
+func (s *S3Store) URL(key string) string {
+	if s.config.PublicBaseURL != "" {
+		return strings.TrimRight(s.config.PublicBaseURL, "/") + "/" + key
+	}
+	return s.objectURL(key)
+}
+
+// objectURL 对象在存储服务上的地址
+func (s *S3Store) objectURL(key string) string {
+	path := "/" + escapeS3Path(key)
+	if s.config.UsePathStyle {
+		return s.endpoint.Scheme + "://" + s.endpoint.Host + "/" + s.config.Bucket + path
+	}
+	return s.endpoint.Scheme + "://" + s.config.Bucket + "." + s.endpoint.Host + path
+}
+
+// do 签名并发送请求，返回响应内容
+func (s *S3Store) do(req *http.Request, payload []byte) ([]byte, error) {
+	s.sign(req, payload, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound && req.Method == http.MethodGet {
+		return nil, ErrBlobNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("s3 %s %s returned status %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read s3 response: %w", err)
+	}
+	return body, nil
+}
+
+// sign 按 AWS Signature V4 为请求添加 Authorization 头
+func (s *S3Store) sign(req *http.Request, payload []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	// 参与签名的请求头，按名称排序
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	names := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		headers["content-type"] = contentType
+		names = append([]string{"content-type"}, names...)
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), date)
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+// escapeS3Path 按 SigV4 规则编码对象键：除非保留字符和 / 外全部百分号编码
+func escapeS3Path(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// sha256Hex 计算十六进制 SHA-256
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 计算 HMAC-SHA256
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
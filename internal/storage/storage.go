@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/xuchengvcc/restart-life-api/internal/config"
+)
+
+// 存储方式
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// 对象存储错误
+var (
+	// ErrInvalidKey 对象键不合法
+	ErrInvalidKey = errors.New("invalid blob key")
+	// ErrBlobNotFound 对象不存在
+	ErrBlobNotFound = errors.New("blob not found")
+)
+
+// BlobStore 对象存储接口，键使用 / 分隔的相对路径，如 avatars/42/abc.jpg
+type BlobStore interface {
+	// Put 写入对象，已存在时覆盖
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Get 读取对象，不存在时返回 ErrBlobNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete 删除对象，对象不存在时不报错
+	Delete(ctx context.Context, key string) error
+	// URL 返回对象的公开访问地址
+	URL(key string) string
+}
+
+// NewFromConfig 根据全局配置创建对象存储
+// 未配置存储方式时使用本地文件系统，便于本地开发
+func NewFromConfig(cfg *config.Config) (BlobStore, error) {
+	store := cfg.Storage
+
+	switch strings.ToLower(store.Driver) {
+	case DriverS3:
+		return NewS3Store(&S3Config{
+			Endpoint:        store.S3.Endpoint,
+			Region:          store.S3.Region,
+			Bucket:          store.S3.Bucket,
+			AccessKeyID:     store.S3.AccessKeyID,
+			SecretAccessKey: store.S3.SecretAccessKey,
+			UsePathStyle:    store.S3.UsePathStyle,
+			PublicBaseURL:   store.S3.PublicBaseURL,
+			Timeout:         store.S3.Timeout,
+		})
+	case DriverLocal, "":
+		return NewLocalStore(store.Local.Dir, store.Local.URLPrefix)
+	default:
+		return nil, fmt.Errorf("unsupported storage driver %q", store.Driver)
+	}
+}
+
+// KeyFromURL 从对象的公开地址反推对象键，非本存储的地址返回 false
+func KeyFromURL(store BlobStore, url string) (string, bool) {
+	prefix := store.URL("")
+	if url == "" || !strings.HasPrefix(url, prefix) {
+		return "", false
+	}
+	key := strings.TrimPrefix(url, prefix)
+	if validateKey(key) != nil {
+		return "", false
+	}
+	return key, true
+}
+
+// validateKey 校验对象键，禁止绝对路径和 .. 以免越出存储目录
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
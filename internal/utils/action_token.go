@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 一次性操作令牌用途
+const (
+	// PurposeVerifyEmail 邮箱验证
+	PurposeVerifyEmail = "verify_email"
+	// PurposeResetPassword 重置密码
+	PurposeResetPassword = "reset_password"
+)
+
+// ActionClaims 一次性操作令牌载荷
+// Binding 为签发时的状态指纹（如邮箱地址、密码哈希指纹），使用时状态已变化则令牌作废
+type ActionClaims struct {
+	Binding string `json:"bnd,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// UserID 令牌所属用户ID
+func (c *ActionClaims) UserID() (uint64, error) {
+	return strconv.ParseUint(c.Subject, 10, 64)
+}
+
+// ActionTokenSigner 一次性操作令牌（验证邮箱、重置密码等）签发器
+// 每种用途使用由主密钥派生的独立签名密钥，操作令牌不能被当作访问令牌使用，反之亦然
+type ActionTokenSigner struct {
+	secret []byte
+}
+
+// NewActionTokenSigner 创建一次性操作令牌签发器
+func NewActionTokenSigner(secret string) *ActionTokenSigner {
+	return &ActionTokenSigner{secret: []byte(secret)}
+}
+
+// Sign 签发操作令牌
+func (s *ActionTokenSigner) Sign(purpose string, userID uint64, binding string, ttl time.Duration) (string, *ActionClaims, error) {
+	now := time.Now()
+	claims := &ActionClaims{
+		Binding: binding,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        NewTokenID(),
+			Issuer:    TokenIssuer,
+			Subject:   strconv.FormatUint(userID, 10),
+			Audience:  jwt.ClaimStrings{purpose},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.key(purpose))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to sign action token: %w", err)
+	}
+	return token, claims, nil
+}
+
+// Parse 校验操作令牌的签名、用途和有效期
+func (s *ActionTokenSigner) Parse(purpose, tokenString string) (*ActionClaims, error) {
+	claims := &ActionClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return s.key(purpose), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(TokenIssuer),
+		jwt.WithAudience(purpose),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrTokenExpired
+		}
+		return nil, ErrTokenInvalid
+	}
+	return claims, nil
+}
+
+// key 派生指定用途的签名密钥
+func (s *ActionTokenSigner) key(purpose string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("action-token:" + purpose))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// API 密钥格式
+const (
+	// APIKeyPrefix API 密钥明文前缀，便于识别与密钥泄露扫描
+	APIKeyPrefix = "rlk_"
+	// apiKeyRandomBytes 密钥随机部分的字节数
+	apiKeyRandomBytes = 24
+	// apiKeyDisplayLength 列表中展示的密钥前缀长度
+	apiKeyDisplayLength = len(APIKeyPrefix) + 8
+)
+
+// API 密钥错误
+var (
+	// ErrAPIKeyInvalid API 密钥无效、已过期或已吊销
+	ErrAPIKeyInvalid = errors.New("api key is invalid")
+	// ErrRateLimited 请求过于频繁
+	ErrRateLimited = errors.New("rate limit exceeded")
+)
+
+// RateLimitError 请求超出限流，携带可重试的等待时间
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+// Error 实现 error 接口
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrRateLimited, e.RetryAfter)
+}
+
+// Unwrap 支持 errors.Is(err, ErrRateLimited)
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// GenerateAPIKey 生成 API 密钥，返回明文、用于展示的前缀和用于存储的摘要
+// 密钥随机部分熵足够高，直接使用 SHA-256 摘要存储即可，无需慢哈希
+func GenerateAPIKey() (key, prefix, hash string) {
+	key = APIKeyPrefix + RandomHex(apiKeyRandomBytes)
+	return key, key[:apiKeyDisplayLength], HashAPIKey(key)
+}
+
+// HashAPIKey 计算 API 密钥摘要
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAPIKeyFormat 快速判断字符串是否符合 API 密钥格式，避免无意义的数据库查询
+func IsAPIKeyFormat(key string) bool {
+	if len(key) != len(APIKeyPrefix)+apiKeyRandomBytes*2 || !strings.HasPrefix(key, APIKeyPrefix) {
+		return false
+	}
+	_, err := hex.DecodeString(key[len(APIKeyPrefix):])
+	return err == nil
+}
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+
+	// 注册可解码的图片格式
+	_ "image/gif"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// 图片处理限制
+const (
+	// MaxImagePixels 解码前允许的最大像素数，防止解压炸弹
+	MaxImagePixels = 40_000_000
+	// avatarJPEGQuality 头像重新编码的 JPEG 质量
+	avatarJPEGQuality = 85
+)
+
+// 图片处理错误
+var (
+	// ErrUnsupportedImage 无法识别的图片格式
+	ErrUnsupportedImage = errors.New("unsupported image format")
+	// ErrImageTooLarge 图片尺寸超出限制
+	ErrImageTooLarge = errors.New("image dimensions too large")
+)
+
+// ProcessAvatar 将上传的图片居中裁剪为正方形并缩放到 size×size，重新编码为 JPEG
+// 重新编码会丢弃 EXIF 等元数据，同时确保存储的内容一定是合法图片
+func ProcessAvatar(r io.Reader, size int) ([]byte, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxImagePixels {
+		return nil, ErrImageTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	// 居中裁剪为正方形
+	bounds := src.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	x0 := bounds.Min.X + (bounds.Dx()-side)/2
+	y0 := bounds.Min.Y + (bounds.Dy()-side)/2
+	crop := image.Rect(x0, y0, x0+side, y0+side)
+
+	if side < size {
+		size = side
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	// 透明区域填充为白色，JPEG 不支持透明通道
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: avatarJPEGQuality}); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
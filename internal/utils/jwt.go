@@ -0,0 +1,221 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// TokenIssuer 令牌签发者
+	TokenIssuer = "restart-life-api"
+	// DefaultAccessTokenExpiry 默认访问令牌有效期
+	DefaultAccessTokenExpiry = 24 * time.Hour
+)
+
+// 令牌校验错误
+var (
+	// ErrTokenInvalid 令牌无效
+	ErrTokenInvalid = errors.New("token is invalid")
+	// ErrTokenExpired 令牌已过期
+	ErrTokenExpired = errors.New("token has expired")
+	// ErrTokenRevoked 令牌已被吊销
+	ErrTokenRevoked = errors.New("token has been revoked")
+)
+
+// Claims JWT 载荷
+type Claims struct {
+	UserID      string   `json:"user_id"`
+	Username    string   `json:"username"`
+	Platform    string   `json:"platform"`
+	SessionID   string   `json:"sid,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	Generation  int64    `json:"gen,omitempty"` // 签发时用户的访问令牌代数，用户吊销全部令牌后代数递增
+	jwt.RegisteredClaims
+}
+
+// JWTManager JWT 令牌管理
+// 配置了非对称密钥集时使用 RS256/EdDSA 签名并在头部携带 kid，否则回退为 HS256 共享密钥
+type JWTManager struct {
+	secret []byte
+	expiry time.Duration
+
+	keys        map[string]*SigningKey
+	keyOrder    []string
+	activeKeyID string
+	validAlgs   []string
+}
+
+// NewJWTManager 创建使用 HS256 共享密钥的 JWT 令牌管理器
+func NewJWTManager(secret string, expiry time.Duration) *JWTManager {
+	if expiry <= 0 {
+		expiry = DefaultAccessTokenExpiry
+	}
+	return &JWTManager{
+		secret:    []byte(secret),
+		expiry:    expiry,
+		validAlgs: []string{jwt.SigningMethodHS256.Alg()},
+	}
+}
+
+// NewJWTManagerWithKeys 创建使用非对称密钥集的 JWT 令牌管理器
+// activeKeyID 指定的密钥退役或未指定时，按配置顺序使用第一个可签名的密钥
+// 其余密钥只用于校验，新密钥可提前发布到 JWKS，旧密钥退役后保留一个访问令牌有效期
+func NewJWTManagerWithKeys(keys []*SigningKey, activeKeyID string, expiry time.Duration) (*JWTManager, error) {
+	if expiry <= 0 {
+		expiry = DefaultAccessTokenExpiry
+	}
+	m := &JWTManager{
+		expiry:      expiry,
+		keys:        make(map[string]*SigningKey, len(keys)),
+		activeKeyID: activeKeyID,
+	}
+
+	algs := make(map[string]bool)
+	for _, key := range keys {
+		if _, exists := m.keys[key.KeyID]; exists {
+			return nil, fmt.Errorf("duplicate signing key id %q", key.KeyID)
+		}
+		m.keys[key.KeyID] = key
+		m.keyOrder = append(m.keyOrder, key.KeyID)
+		if !algs[key.Method.Alg()] {
+			algs[key.Method.Alg()] = true
+			m.validAlgs = append(m.validAlgs, key.Method.Alg())
+		}
+	}
+
+	if activeKeyID != "" {
+		if key, ok := m.keys[activeKeyID]; !ok || key.Private == nil {
+			return nil, fmt.Errorf("%w: key %q not found or missing private key", ErrSigningKeyUnavailable, activeKeyID)
+		}
+	}
+	if m.signingKey(time.Now()) == nil {
+		return nil, ErrSigningKeyUnavailable
+	}
+	return m, nil
+}
+
+// signingKey 选择当前用于签名的密钥
+func (m *JWTManager) signingKey(now time.Time) *SigningKey {
+	if key, ok := m.keys[m.activeKeyID]; ok && key.canSign(now) {
+		return key
+	}
+	for _, kid := range m.keyOrder {
+		if key := m.keys[kid]; key.canSign(now) {
+			return key
+		}
+	}
+	return nil
+}
+
+// Expiry 访问令牌有效期
+func (m *JWTManager) Expiry() time.Duration {
+	return m.expiry
+}
+
+// GenerateAccessToken 生成访问令牌，sessionID 为签发该令牌的登录会话（刷新令牌家族）
+// permissions 为签发时用户拥有的权限，角色变更后需重新签发才会生效
+// generation 为签发时用户的访问令牌代数
+func (m *JWTManager) GenerateAccessToken(userID, username, platform, sessionID string, permissions []string, generation int64) (string, *Claims, error) {
+	now := time.Now()
+	claims := &Claims{
+		UserID:      userID,
+		Username:    username,
+		Platform:    platform,
+		SessionID:   sessionID,
+		Permissions: permissions,
+		Generation:  generation,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        NewTokenID(),
+			Issuer:    TokenIssuer,
+			Subject:   userID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.expiry)),
+		},
+	}
+
+	var token string
+	var err error
+	if m.keys == nil {
+		token, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	} else {
+		key := m.signingKey(now)
+		if key == nil {
+			return "", nil, ErrSigningKeyUnavailable
+		}
+		t := jwt.NewWithClaims(key.Method, claims)
+		t.Header["kid"] = key.KeyID
+		token, err = t.SignedString(key.Private)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to sign token: %w", err)
+	}
+	return token, claims, nil
+}
+
+// HasPermission 令牌是否包含指定权限
+func (c *Claims) HasPermission(permission string) bool {
+	for _, p := range c.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAccessToken 解析并校验访问令牌
+func (m *JWTManager) ParseAccessToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, m.keyfunc,
+		jwt.WithValidMethods(m.validAlgs),
+		jwt.WithIssuer(TokenIssuer),
+	)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrTokenExpired
+		}
+		return nil, ErrTokenInvalid
+	}
+	return claims, nil
+}
+
+// JWKS 返回当前仍被接受的公钥集，使用 HS256 时为空
+func (m *JWTManager) JWKS() *JSONWebKeySet {
+	set := &JSONWebKeySet{Keys: make([]JSONWebKey, 0, len(m.keyOrder))}
+	now := time.Now()
+	for _, kid := range m.keyOrder {
+		key := m.keys[kid]
+		if key.acceptable(now, m.expiry) {
+			set.Keys = append(set.Keys, key.jwk())
+		}
+	}
+	return set
+}
+
+// keyfunc 按令牌头部的 kid 查找校验密钥
+func (m *JWTManager) keyfunc(token *jwt.Token) (interface{}, error) {
+	if m.keys == nil {
+		return m.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := m.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("algorithm %s does not match key %q", token.Method.Alg(), kid)
+	}
+	if !key.acceptable(time.Now(), m.expiry) {
+		return nil, fmt.Errorf("key %q has been retired", kid)
+	}
+	return key.Public, nil
+}
+
+// NewTokenID 生成随机令牌ID（jti）
+func NewTokenID() string {
+	return RandomHex(16)
+}
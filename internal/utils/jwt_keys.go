@@ -0,0 +1,184 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 支持的非对称签名算法
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// ErrSigningKeyUnavailable 没有可用于签名的密钥
+var ErrSigningKeyUnavailable = errors.New("no active signing key")
+
+// SigningKey 访问令牌签名密钥
+// 只有公钥的密钥仅用于校验，用于轮换后继续接受旧密钥签发的令牌
+type SigningKey struct {
+	KeyID     string
+	Method    jwt.SigningMethod
+	Private   crypto.Signer
+	Public    crypto.PublicKey
+	RetiredAt time.Time // 非零时该密钥不再签名，旧令牌过期后不再被接受
+}
+
+// LoadSigningKey 从 PEM 文件加载签名密钥
+// privateKeyFile 与 publicKeyFile 至少提供一个，仅提供私钥时公钥由私钥导出
+func LoadSigningKey(keyID, algorithm, privateKeyFile, publicKeyFile string, retiredAt time.Time) (*SigningKey, error) {
+	if keyID == "" {
+		return nil, errors.New("signing key id is required")
+	}
+	key := &SigningKey{KeyID: keyID, RetiredAt: retiredAt}
+
+	switch algorithm {
+	case AlgorithmRS256:
+		key.Method = jwt.SigningMethodRS256
+	case AlgorithmEdDSA:
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("signing key %s: unsupported algorithm %q", keyID, algorithm)
+	}
+
+	if privateKeyFile != "" {
+		block, err := readPEM(privateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %s: %w", keyID, err)
+		}
+		private, err := parsePrivateKey(block)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %s: %w", keyID, err)
+		}
+		key.Private = private
+		key.Public = private.Public()
+	}
+
+	if publicKeyFile != "" {
+		block, err := readPEM(publicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %s: %w", keyID, err)
+		}
+		public, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %s: invalid public key: %w", keyID, err)
+		}
+		key.Public = public
+	}
+
+	if key.Public == nil {
+		return nil, fmt.Errorf("signing key %s: private_key_file or public_key_file is required", keyID)
+	}
+	if err := checkKeyType(key.Method, key.Public); err != nil {
+		return nil, fmt.Errorf("signing key %s: %w", keyID, err)
+	}
+	return key, nil
+}
+
+// canSign 密钥当前是否可用于签名
+func (k *SigningKey) canSign(now time.Time) bool {
+	return k.Private != nil && (k.RetiredAt.IsZero() || now.Before(k.RetiredAt))
+}
+
+// acceptable 密钥签发的令牌当前是否仍被接受，退役后保留一个访问令牌有效期的重叠窗口
+func (k *SigningKey) acceptable(now time.Time, expiry time.Duration) bool {
+	return k.RetiredAt.IsZero() || now.Before(k.RetiredAt.Add(expiry))
+}
+
+// JSONWebKey JWKS 中的单个公钥
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JSONWebKeySet 公钥集
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// jwk 将公钥转换为 JWK
+func (k *SigningKey) jwk() JSONWebKey {
+	jwk := JSONWebKey{Kid: k.KeyID, Use: "sig", Alg: k.Method.Alg()}
+	switch public := k.Public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+	return jwk
+}
+
+// readPEM 读取 PEM 文件的第一个块
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+	return block, nil
+}
+
+// parsePrivateKey 解析 PKCS#8 或 PKCS#1 格式的私钥
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	if strings.HasPrefix(block.Type, "RSA ") {
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key: %w", err)
+		}
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("unsupported private key type")
+	}
+	return signer, nil
+}
+
+// checkKeyType 校验公钥类型与算法匹配
+func checkKeyType(method jwt.SigningMethod, public crypto.PublicKey) error {
+	switch method {
+	case jwt.SigningMethodRS256:
+		key, ok := public.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("RS256 requires an RSA key")
+		}
+		if key.N.BitLen() < 2048 {
+			return errors.New("RSA key must be at least 2048 bits")
+		}
+	case jwt.SigningMethodEdDSA:
+		if _, ok := public.(ed25519.PublicKey); !ok {
+			return errors.New("EdDSA requires an Ed25519 key")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordCost bcrypt 计算成本
+const PasswordCost = 12
+
+// MaxPasswordBytes bcrypt 支持的最大密码字节数，多字节字符按 UTF-8 编码后的长度计算
+const MaxPasswordBytes = 72
+
+// ErrPasswordTooLong 密码超过 bcrypt 支持的字节数
+var ErrPasswordTooLong = errors.New("password too long")
+
+// HashPassword 使用 bcrypt 生成密码哈希
+func HashPassword(password string) (string, error) {
+	if len(password) > MaxPasswordBytes {
+		return "", ErrPasswordTooLong
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), PasswordCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// CheckPassword 校验密码是否与哈希匹配
+func CheckPassword(hash, password string) bool {
+	if hash == "" {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// SecretBox 使用 AES-256-GCM 加密需要可逆存储的敏感数据（如 TOTP 密钥）
+type SecretBox struct {
+	aead cipher.AEAD
+}
+
+// NewSecretBox 创建加密器，密钥由主密钥按用途派生
+func NewSecretBox(secret, purpose string) (*SecretBox, error) {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("secret-box:" + purpose))
+
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gcm: %w", err)
+	}
+	return &SecretBox{aead: aead}, nil
+}
+
+// Seal 加密明文，返回 base64 编码的 nonce + 密文
+func (b *SecretBox) Seal(plaintext string) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := b.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open 解密 Seal 的输出
+func (b *SecretBox) Open(ciphertext string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("invalid ciphertext encoding: %w", err)
+	}
+	if len(data) < b.aead.NonceSize() {
+		return "", errors.New("ciphertext too short")
+	}
+
+	nonce, sealed := data[:b.aead.NonceSize()], data[b.aead.NonceSize():]
+	plaintext, err := b.aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return string(plaintext), nil
+}
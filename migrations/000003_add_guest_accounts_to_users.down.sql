@@ -0,0 +1,14 @@
+-- 第三方登录等非游客账户也可能没有邮箱，这些账户不能删除，email 也就无法恢复为 NOT NULL，需先人工处理
+-- MySQL 的 DDL 不在事务中，必须在删除任何数据之前检查：添加约束时会校验现有数据，存在此类账户时在此失败，游客数据保持不变
+ALTER TABLE users ADD CONSTRAINT chk_users_down_email CHECK (is_guest = TRUE OR email IS NOT NULL);
+
+-- 未升级的游客账户没有邮箱，回滚前需先删除（角色随外键级联删除）
+DELETE FROM users WHERE is_guest = TRUE;
+
+ALTER TABLE users DROP CHECK chk_users_down_email;
+
+ALTER TABLE users
+    DROP INDEX uk_users_device_id,
+    DROP COLUMN device_id,
+    DROP COLUMN is_guest,
+    MODIFY COLUMN email VARCHAR(255) NOT NULL;
@@ -0,0 +1,6 @@
+-- 游客账户支持：游客没有邮箱，登录凭据为绑定的设备ID
+ALTER TABLE users
+    MODIFY COLUMN email VARCHAR(255) NULL,
+    ADD COLUMN is_guest BOOLEAN NOT NULL DEFAULT FALSE AFTER is_active,
+    ADD COLUMN device_id VARCHAR(128) NULL AFTER is_guest,
+    ADD UNIQUE KEY uk_users_device_id (device_id);
@@ -0,0 +1,2 @@
+-- 删除第三方账户关联表（索引随表删除）
+DROP TABLE IF EXISTS user_identities;
@@ -0,0 +1,20 @@
+-- 创建第三方账户关联表
+CREATE TABLE IF NOT EXISTS user_identities (
+    identity_id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id INT UNSIGNED NOT NULL,
+    provider VARCHAR(32) NOT NULL COMMENT '登录提供方，如 wechat',
+    provider_subject VARCHAR(255) NOT NULL COMMENT '提供方内的用户标识，如微信 openid',
+    union_id VARCHAR(255) NULL COMMENT '跨应用统一标识，如微信 unionid',
+    email VARCHAR(255) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP NULL,
+
+    UNIQUE KEY uk_user_identities_provider_subject (provider, provider_subject),
+
+    -- 外键约束
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 创建索引
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
+CREATE INDEX idx_user_identities_union_id ON user_identities(provider, union_id);
@@ -0,0 +1,3 @@
+-- 邮箱验证状态：NULL 表示尚未验证
+ALTER TABLE users
+    ADD COLUMN email_verified_at TIMESTAMP NULL AFTER email;
@@ -0,0 +1,25 @@
+-- 创建 TOTP 两步验证表，每个用户最多一条记录
+CREATE TABLE IF NOT EXISTS user_totp (
+    user_id INT UNSIGNED PRIMARY KEY,
+    secret_encrypted VARCHAR(255) NOT NULL COMMENT 'AES-GCM 加密后的 TOTP 密钥',
+    enabled_at TIMESTAMP NULL COMMENT 'NULL 表示已生成密钥但尚未确认',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    -- 外键约束
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 创建两步验证恢复码表，仅保存哈希
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    code_id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id INT UNSIGNED NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE KEY uk_user_recovery_codes_user_code (user_id, code_hash),
+
+    -- 外键约束
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
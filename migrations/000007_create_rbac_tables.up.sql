@@ -0,0 +1,69 @@
+-- 创建角色表
+CREATE TABLE IF NOT EXISTS roles (
+    role_id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(50) UNIQUE NOT NULL,
+    description VARCHAR(255) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 创建权限表，权限名形如 资源:操作
+CREATE TABLE IF NOT EXISTS permissions (
+    permission_id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) UNIQUE NOT NULL,
+    description VARCHAR(255) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 创建角色权限关联表
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id INT UNSIGNED NOT NULL,
+    permission_id INT UNSIGNED NOT NULL,
+
+    PRIMARY KEY (role_id, permission_id),
+
+    -- 外键约束
+    FOREIGN KEY (role_id) REFERENCES roles(role_id) ON DELETE CASCADE,
+    FOREIGN KEY (permission_id) REFERENCES permissions(permission_id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 创建用户角色关联表，普通玩家没有任何角色
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id INT UNSIGNED NOT NULL,
+    role_id INT UNSIGNED NOT NULL,
+    granted_by INT UNSIGNED NULL COMMENT '授予该角色的管理员',
+    granted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (user_id, role_id),
+
+    -- 外键约束
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (role_id) REFERENCES roles(role_id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE INDEX idx_user_roles_role_id ON user_roles(role_id);
+
+-- 初始角色与权限
+INSERT INTO roles (name, description) VALUES
+    ('admin', '超级管理员，拥有全部权限'),
+    ('operator', '运营人员，负责内容与玩家管理');
+
+INSERT INTO permissions (name, description) VALUES
+    ('admin:access', '访问管理后台接口'),
+    ('users:read', '查看用户信息'),
+    ('users:write', '启用或禁用用户'),
+    ('roles:read', '查看角色与权限'),
+    ('roles:write', '授予或撤销用户角色'),
+    ('content:read', '查看游戏内容配置'),
+    ('content:write', '修改游戏内容配置');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.role_id, p.permission_id FROM roles r CROSS JOIN permissions p
+WHERE r.name = 'admin';
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.role_id, p.permission_id FROM roles r JOIN permissions p
+    ON p.name IN ('admin:access', 'users:read', 'users:write', 'content:read', 'content:write')
+WHERE r.name = 'operator';
+
+-- 首个管理员需手动授予，例如：
+-- INSERT INTO user_roles (user_id, role_id) SELECT <user_id>, role_id FROM roles WHERE name = 'admin';
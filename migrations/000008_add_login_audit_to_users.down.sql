@@ -0,0 +1,5 @@
+ALTER TABLE users
+    DROP COLUMN last_failed_login_ip,
+    DROP COLUMN last_failed_login_at,
+    DROP COLUMN failed_login_count,
+    DROP COLUMN last_login_ip;
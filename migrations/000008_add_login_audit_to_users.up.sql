@@ -0,0 +1,7 @@
+-- 登录审计：最后登录 IP 与连续失败登录记录，成功登录后失败次数清零
+-- 实际的限流与锁定状态保存在 Redis 中，这里只用于审计与后台排查
+ALTER TABLE users
+    ADD COLUMN last_login_ip VARCHAR(45) NULL AFTER last_login,
+    ADD COLUMN failed_login_count INT UNSIGNED NOT NULL DEFAULT 0 AFTER last_login_ip,
+    ADD COLUMN last_failed_login_at TIMESTAMP NULL AFTER failed_login_count,
+    ADD COLUMN last_failed_login_ip VARCHAR(45) NULL AFTER last_failed_login_at;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS data_exports;
+
+ALTER TABLE users
+    DROP INDEX idx_users_deletion_scheduled_at,
+    DROP COLUMN deletion_scheduled_at;
@@ -0,0 +1,23 @@
+-- 账户注销：申请注销后进入冷静期，到期由清理任务删除用户（关联数据通过 ON DELETE CASCADE 一并删除）
+ALTER TABLE users
+    ADD COLUMN deletion_scheduled_at TIMESTAMP NULL COMMENT '计划删除时间，NULL 表示未申请注销' AFTER is_active,
+    ADD INDEX idx_users_deletion_scheduled_at (deletion_scheduled_at);
+
+-- 创建个人数据导出任务表，导出文件保存在对象存储中，过期后由清理任务删除
+CREATE TABLE IF NOT EXISTS data_exports (
+    export_id CHAR(32) PRIMARY KEY,
+    user_id INT UNSIGNED NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT 'pending | ready | failed',
+    blob_key VARCHAR(255) NULL COMMENT '导出文件在对象存储中的键',
+    size_bytes BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    error_message VARCHAR(255) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP NULL,
+    expires_at TIMESTAMP NULL,
+
+    -- 外键约束
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE INDEX idx_data_exports_user_id ON data_exports(user_id, created_at);
+CREATE INDEX idx_data_exports_expires_at ON data_exports(expires_at);
@@ -0,0 +1,20 @@
+-- 创建个人 API 密钥表，只保存密钥的 SHA-256 摘要，明文仅在创建时返回一次
+CREATE TABLE IF NOT EXISTS api_keys (
+    key_id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id INT UNSIGNED NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL COMMENT '密钥明文前缀，用于在列表中识别密钥',
+    key_hash CHAR(64) UNIQUE NOT NULL,
+    scopes VARCHAR(500) NOT NULL DEFAULT '' COMMENT '空格分隔的授权范围',
+    rate_limit INT UNSIGNED NOT NULL DEFAULT 0 COMMENT '每个限流窗口的请求上限',
+    last_used_at TIMESTAMP NULL,
+    last_used_ip VARCHAR(45) NULL,
+    expires_at TIMESTAMP NULL COMMENT 'NULL 表示永不过期',
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- 外键约束
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE INDEX idx_api_keys_user_id ON api_keys(user_id, created_at);
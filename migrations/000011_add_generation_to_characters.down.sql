@@ -0,0 +1,7 @@
+ALTER TABLE characters
+    DROP COLUMN personality,
+    DROP COLUMN family_background,
+    DROP COLUMN weight_kg,
+    DROP COLUMN height_cm,
+    DROP COLUMN generator_version,
+    DROP COLUMN generation_seed;
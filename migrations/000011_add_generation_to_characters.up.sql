@@ -0,0 +1,9 @@
+-- 角色随机生成：保存种子与生成规则版本以便复现，以及生成的先天条件
+-- 旧角色没有生成记录，相关字段允许为 NULL
+ALTER TABLE characters
+    ADD COLUMN generation_seed BIGINT NULL COMMENT '生成种子' AFTER race,
+    ADD COLUMN generator_version SMALLINT UNSIGNED NULL COMMENT '生成规则版本' AFTER generation_seed,
+    ADD COLUMN height_cm DECIMAL(5,1) NULL COMMENT '出生身长（厘米）' AFTER generator_version,
+    ADD COLUMN weight_kg DECIMAL(5,2) NULL COMMENT '出生体重（千克）' AFTER height_cm,
+    ADD COLUMN family_background VARCHAR(20) NULL COMMENT 'poor | working | middle | affluent | wealthy' AFTER weight_kg,
+    ADD COLUMN personality JSON NULL COMMENT '大五人格，各项 0~100' AFTER family_background;
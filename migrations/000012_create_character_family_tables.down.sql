@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS character_family_members;
+DROP TABLE IF EXISTS character_households;
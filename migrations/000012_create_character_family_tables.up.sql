@@ -0,0 +1,28 @@
+-- 创建角色家庭表：角色出生时随机生成的家庭情况，家庭经济状况沿用 characters.family_background
+CREATE TABLE IF NOT EXISTS character_households (
+    character_id CHAR(36) PRIMARY KEY,
+    home_region VARCHAR(20) NOT NULL COMMENT 'urban | town | rural',
+    housing VARCHAR(20) NOT NULL COMMENT 'owned | rented | shared',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- 外键约束
+    FOREIGN KEY (character_id) REFERENCES characters(character_id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 创建角色家庭成员表
+CREATE TABLE IF NOT EXISTS character_family_members (
+    member_id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    character_id CHAR(36) NOT NULL,
+    relation VARCHAR(30) NOT NULL COMMENT '与角色的关系，如 father、older_sister、maternal_grandmother',
+    gender VARCHAR(20) NOT NULL,
+    age_at_birth INTEGER NOT NULL COMMENT '角色出生时该成员的年龄',
+    is_alive BOOLEAN NOT NULL DEFAULT TRUE COMMENT '角色出生时是否在世',
+    occupation VARCHAR(50) NULL,
+    closeness INTEGER NOT NULL DEFAULT 50 CHECK (closeness >= 0 AND closeness <= 100),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- 外键约束
+    FOREIGN KEY (character_id) REFERENCES characters(character_id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE INDEX idx_character_family_members_character_id ON character_family_members(character_id, member_id);
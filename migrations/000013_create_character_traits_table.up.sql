@@ -0,0 +1,24 @@
+-- 创建角色性格倾向表：每个维度为 -1 ~ 1 的连续分值，出生时生成，随生活事件缓慢变化
+CREATE TABLE IF NOT EXISTS character_traits (
+    character_id CHAR(36) PRIMARY KEY,
+    extraversion DECIMAL(4,3) NOT NULL DEFAULT 0 COMMENT '-1 内向 ~ 1 外向',
+    optimism DECIMAL(4,3) NOT NULL DEFAULT 0 COMMENT '-1 悲观 ~ 1 乐观',
+    bravery DECIMAL(4,3) NOT NULL DEFAULT 0 COMMENT '-1 谨慎 ~ 1 勇敢',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    CHECK (extraversion >= -1 AND extraversion <= 1),
+    CHECK (optimism >= -1 AND optimism <= 1),
+    CHECK (bravery >= -1 AND bravery <= 1),
+
+    -- 外键约束
+    FOREIGN KEY (character_id) REFERENCES characters(character_id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 为已有角色补充性格倾向：有大五人格记录的按生成规则的期望值换算，其余取中性值
+INSERT INTO character_traits (character_id, extraversion, optimism, bravery)
+SELECT
+    character_id,
+    COALESCE((JSON_EXTRACT(personality, '$.extraversion') - 50) / 50, 0),
+    COALESCE((0.7 * (50 - JSON_EXTRACT(personality, '$.neuroticism')) + 0.3 * (JSON_EXTRACT(personality, '$.extraversion') - 50)) / 50, 0),
+    COALESCE((0.6 * (JSON_EXTRACT(personality, '$.openness') - 50) + 0.4 * (50 - JSON_EXTRACT(personality, '$.neuroticism'))) / 50, 0)
+FROM characters;
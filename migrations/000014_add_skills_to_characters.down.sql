@@ -0,0 +1,6 @@
+ALTER TABLE characters
+    DROP COLUMN business_skill,
+    DROP COLUMN artistic_skill,
+    DROP COLUMN athletic_skill,
+    DROP COLUMN social_skill,
+    DROP COLUMN academic_skill;
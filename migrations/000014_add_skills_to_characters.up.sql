@@ -0,0 +1,7 @@
+-- 角色技能：学业、社交、运动、艺术、商业，出生时为 0，通过训练和生活事件提升
+ALTER TABLE characters
+    ADD COLUMN academic_skill INTEGER NOT NULL DEFAULT 0 CHECK (academic_skill >= 0 AND academic_skill <= 100) AFTER appearance,
+    ADD COLUMN social_skill INTEGER NOT NULL DEFAULT 0 CHECK (social_skill >= 0 AND social_skill <= 100) AFTER academic_skill,
+    ADD COLUMN athletic_skill INTEGER NOT NULL DEFAULT 0 CHECK (athletic_skill >= 0 AND athletic_skill <= 100) AFTER social_skill,
+    ADD COLUMN artistic_skill INTEGER NOT NULL DEFAULT 0 CHECK (artistic_skill >= 0 AND artistic_skill <= 100) AFTER athletic_skill,
+    ADD COLUMN business_skill INTEGER NOT NULL DEFAULT 0 CHECK (business_skill >= 0 AND business_skill <= 100) AFTER artistic_skill;
@@ -0,0 +1,6 @@
+DELETE FROM permissions WHERE name = 'characters:delete';
+
+DROP INDEX idx_characters_archived_at ON characters;
+
+ALTER TABLE characters
+    DROP COLUMN archived_at;
@@ -0,0 +1,14 @@
+-- 角色归档：玩家删除角色时先归档，保留期内可恢复，期满后由后台任务彻底删除
+ALTER TABLE characters
+    ADD COLUMN archived_at TIMESTAMP NULL COMMENT '归档时间，NULL 表示未归档' AFTER is_active;
+
+CREATE INDEX idx_characters_archived_at ON characters(archived_at);
+
+-- 彻底删除角色属于不可恢复操作，仅授予超级管理员
+INSERT INTO permissions (name, description) VALUES
+    ('characters:delete', '彻底删除玩家角色');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.role_id, p.permission_id FROM roles r JOIN permissions p
+    ON p.name = 'characters:delete'
+WHERE r.name = 'admin';
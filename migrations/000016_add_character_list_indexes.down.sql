@@ -0,0 +1,6 @@
+CREATE INDEX idx_characters_user_id ON characters(user_id);
+
+DROP INDEX idx_characters_user_birth ON characters;
+DROP INDEX idx_characters_user_final_age ON characters;
+DROP INDEX idx_characters_user_money ON characters;
+DROP INDEX idx_characters_user_created_at ON characters;
@@ -0,0 +1,9 @@
+-- 角色列表分页索引：按 (排序值, character_id) 作为键集翻页
+-- final_age 排序使用函数索引，表达式需与查询中的 COALESCE(final_age, -1) 保持一致
+CREATE INDEX idx_characters_user_created_at ON characters(user_id, created_at, character_id);
+CREATE INDEX idx_characters_user_money ON characters(user_id, money, character_id);
+CREATE INDEX idx_characters_user_final_age ON characters(user_id, (COALESCE(final_age, -1)), character_id);
+CREATE INDEX idx_characters_user_birth ON characters(user_id, birth_country, birth_year);
+
+-- 以上索引均以 user_id 开头，可替代原有的 user_id 单列索引
+DROP INDEX idx_characters_user_id ON characters;